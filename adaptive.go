@@ -0,0 +1,108 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+const (
+	// The number of events per bucket of the root wheel which adaptive
+	// tuning aims for (see NewTimerWheel).
+	adaptiveTarget = 100
+	// How far from the target the density must drift before the
+	// Timer Wheel is rebuilt.
+	adaptiveSlack = 4
+)
+
+type adaptiveTuning struct {
+	// 0 unless WithAdaptiveBucketSize was given.
+	min     int64
+	max     int64
+	retuned func(from, to time.Duration)
+	// The time and count of invoked events when the density was last
+	// measured.
+	since   int64
+	invoked uint64
+}
+
+// Retunes bucketSize to the workload as it changes. The Timer Wheel
+// measures how many events become due per bucket of the root wheel,
+// over each span of time covered by the root wheel's ring, and if
+// that has drifted more than 4 times away from around 100 events per
+// bucket (see NewTimerWheel) it is rebuilt with a bucketSize which
+// would have given 100, within min and max. Every pending event is
+// kept, along with its key, tag, handle and so on, and the current
+// time is unchanged. Rebuilding is O(n), and happens at the end of a
+// call to AdvanceTo (or AdvanceBy and so on). Retuned, if not nil, is
+// called with the old and new bucketSize after each rebuild. Min must
+// be greater than 0, and max must be at least min; the bucketSize
+// given to NewTimerWheel need not lie between them. A start time set
+// by WithAlignment is not kept aligned.
+func WithAdaptiveBucketSize(min, max time.Duration, retuned func(from, to time.Duration)) Option {
+	if min <= 0 || max < min {
+		panic("TimerWheel adaptive bucket sizes must be greater than 0, with max at least min")
+	}
+	return func(tw *TimerWheel) {
+		tw.adaptive = adaptiveTuning{min: int64(min), max: int64(max), retuned: retuned, since: tw.now}
+	}
+}
+
+// Called at the end of each advance. Once the root wheel's ring has
+// been passed since the last measurement, works out the density of
+// events and rebuilds the Timer Wheel if it is too far from the
+// target.
+func (tw *TimerWheel) retune() {
+	at := &tw.adaptive
+	if at.min == 0 || tw.now-at.since < tw.ringWidth() {
+		return
+	}
+	invoked := tw.stats.fired + tw.stats.expired
+	density := float64(invoked-at.invoked) * float64(tw.bucketSize) / float64(tw.now-at.since)
+	at.since, at.invoked = tw.now, invoked
+	if density <= adaptiveTarget*adaptiveSlack && density >= adaptiveTarget/adaptiveSlack {
+		return
+	}
+	bucketSize := at.max
+	if density > 0 {
+		if size := float64(tw.bucketSize) * adaptiveTarget / density; size < float64(at.max) {
+			bucketSize = int64(size)
+		}
+	}
+	if bucketSize < at.min {
+		bucketSize = at.min
+	}
+	if bucketSize == tw.bucketSize {
+		return
+	}
+	from := tw.bucketSize
+	tw.rebuild(bucketSize, len(tw.ring))
+	if at.retuned != nil {
+		at.retuned(time.Duration(from), time.Duration(bucketSize))
+	}
+}
+
+// Rebuilds the hierarchy with the new bucketSize and number of
+// buckets in the root wheel's ring, starting from the root wheel's
+// current bucket, and puts every pending event back in it, in order.
+func (tw *TimerWheel) rebuild(bucketSize int64, buckets int) {
+	events := make([]*eventNode, 0, tw.Length())
+	tw.forEachEvent(func(event *eventNode) bool {
+		events = append(events, event)
+		return true
+	})
+	start := tw.start + int64(tw.ringIdx)*tw.bucketSize
+	tw.removeAll()
+	if buckets != len(tw.ring) {
+		sliced, tieBreak := tw.ring[0].sliced, tw.ring[0].tieBreak
+		tw.ring = make([]bucket, buckets)
+		for idx := range tw.ring {
+			tw.ring[idx] = bucket{sliced: sliced, tieBreak: tieBreak}
+		}
+	}
+	tw.ringIdx = 0
+	tw.start = start
+	tw.bucketSize = bucketSize
+	for _, event := range events {
+		event.next.eventNode = nil
+	}
+	tw.placeSortedEvents(events)
+}
@@ -0,0 +1,58 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveBucketSize(t *testing.T) {
+	start := time.Unix(0, 0)
+	var retunes [][2]time.Duration
+	tw := NewTimerWheel(start, time.Millisecond, WithAdaptiveBucketSize(time.Microsecond, time.Second, func(from, to time.Duration) {
+		retunes = append(retunes, [2]time.Duration{from, to})
+	}))
+	var invoked []time.Time
+	record := func(at time.Time) Event {
+		return func(*time.Time) { invoked = append(invoked, at) }
+	}
+	// 1000 events in every millisecond, for more than a ring's worth
+	for idx := 0; idx < 40000; idx++ {
+		at := start.Add(time.Duration(idx) * time.Microsecond)
+		tw.ScheduleEventAt(at, record(at))
+	}
+	tw.ScheduleKeyedEventAt("later", start.Add(time.Hour), record(start.Add(time.Hour)))
+	h, _ := tw.ScheduleHandleAt(start.Add(time.Minute), record(start.Add(time.Minute)))
+	for idx := 0; idx < 36; idx++ {
+		tw.AdvanceBy(time.Millisecond, 0)
+	}
+	if len(retunes) != 1 || retunes[0][0] != time.Millisecond ||
+		retunes[0][1] < 95*time.Microsecond || retunes[0][1] > 100*time.Microsecond {
+		t.Fatalf("Expected to retune to around 100µs, but got %v", retunes)
+	}
+	if li := tw.Levels(); li[0].BucketSize != retunes[0][1] {
+		t.Errorf("Expected the root wheel rebuilt, but got %v", li[0].BucketSize)
+	}
+	if tw.Length() != 4001 || !tw.Now().Equal(start.Add(36*time.Millisecond)) {
+		t.Errorf("Expected every pending event kept, but got %v at %v", tw.Length(), tw.Now())
+	}
+	// keys and handles still work
+	if !tw.CancelKey("later") || !h.Cancel() {
+		t.Error("Expected the keyed event and handle to survive the rebuild")
+	}
+	tw.AdvanceBy(time.Hour, 0)
+	if len(invoked) != 40000 {
+		t.Fatalf("Expected 40000 events invoked, but got %v", len(invoked))
+	}
+	for idx := 1; idx < len(invoked); idx++ {
+		if invoked[idx].Before(invoked[idx-1]) {
+			t.Fatalf("Events invoked out of order at %v", idx)
+		}
+	}
+	// an idle hour is far too sparse
+	if len(retunes) != 2 || retunes[1][1] != time.Second {
+		t.Errorf("Expected to retune to the maximum, but got %v", retunes)
+	}
+	if stats := tw.Stats(); stats.Scheduled != stats.Fired+stats.Cancelled {
+		t.Errorf("Expected statistics untouched by rebuilding, but got %+v", stats)
+	}
+}
@@ -0,0 +1,37 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type aggregation struct {
+	notify func(now time.Time, counts map[string]int)
+	counts map[string]int
+}
+
+// With this option, events are never invoked. Instead, every call to
+// AdvanceTo (or AdvanceBy, or Drain) that would have invoked events
+// calls notify once, with the time advanced to and the number of
+// events that came due for each tag. Untagged events are counted
+// under the empty tag. This suits consumers that only need to know
+// how many events of each kind expired; such events may be
+// scheduled with a nil Event. The counts map belongs to notify.
+func WithAggregation(notify func(now time.Time, counts map[string]int)) Option {
+	return func(tw *TimerWheel) {
+		tw.aggregate.notify = notify
+	}
+}
+
+func (a *aggregation) add(event *eventNode) {
+	if a.counts == nil {
+		a.counts = make(map[string]int)
+	}
+	a.counts[event.tag]++
+}
+
+func (tw *TimerWheel) notifyAggregate(now time.Time) {
+	if counts := tw.aggregate.counts; counts != nil {
+		tw.aggregate.counts = nil
+		tw.aggregate.notify(now, counts)
+	}
+}
@@ -0,0 +1,43 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregation(t *testing.T) {
+	start := time.Unix(0, 0)
+	notifications := []map[string]int{}
+	tw := NewTimerWheel(start, 1, WithAggregation(func(now time.Time, counts map[string]int) {
+		notifications = append(notifications, counts)
+	}), WithNilEvents())
+	for idx := 0; idx < 100; idx++ {
+		tw.ScheduleTaggedEventIn("heartbeat", time.Duration(idx), nil)
+	}
+	tw.ScheduleTaggedEventIn("session", 10, nil)
+	tw.ScheduleEvents([]ScheduledEvent{{At: time.Unix(0, 20), Tag: "session"}, {At: time.Unix(0, 200)}})
+
+	tw.AdvanceTo(time.Unix(0, 49), 0)
+	if len(notifications) != 1 {
+		t.Fatalf("Expected one notification, but got %v", notifications)
+	}
+	if counts := notifications[0]; len(counts) != 2 || counts["heartbeat"] != 50 || counts["session"] != 2 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+	// nothing due, no notification
+	tw.AdvanceTo(time.Unix(0, 49), 0)
+	if len(notifications) != 1 {
+		t.Errorf("Expected no further notification, but got %v", notifications)
+	}
+
+	tw.Drain(0)
+	if len(notifications) != 2 {
+		t.Fatalf("Expected a notification from Drain, but got %v", notifications)
+	}
+	if counts := notifications[1]; len(counts) != 2 || counts["heartbeat"] != 50 || counts[""] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+	if stats := tw.Stats(); stats.Fired != 103 {
+		t.Errorf("Expected 103 fired, but found %v", stats.Fired)
+	}
+}
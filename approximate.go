@@ -0,0 +1,31 @@
+package gotimerwheel
+
+// Trades precision for throughput. By default, the events of each
+// bucket of the root wheel are kept in order of time, so that
+// AdvanceTo invokes exactly the events scheduled up to and including
+// its target. With this option, events are simply appended to their
+// bucket, in order of scheduling, and a bucket's events are invoked
+// together, in that order, once the Timer Wheel's time reaches the
+// last instant of the bucket. No event is invoked early, but an event
+// may be invoked up to bucketSize late, and events in the same bucket
+// may be invoked out of order of time. Scheduling and invoking never
+// sort, which suits workloads such as idle timeouts which do not
+// need more precision than bucketSize. NextEventAt reports the time
+// at which the earliest event will be invoked rather than the time it
+// is scheduled for, so AdvanceToNextEvent and RunUntilEmpty work as
+// before. Any tie-break set by WithTieBreak is ignored.
+func WithApproximateExpiry() Option {
+	return func(tw *TimerWheel) {
+		tw.approximate = true
+	}
+}
+
+// Returns the time at which an event scheduled for at is invoked: at
+// itself, or with WithApproximateExpiry, the last instant of the
+// bucket of the root wheel which holds it.
+func (tw *TimerWheel) invokedAt(at int64) int64 {
+	if !tw.approximate {
+		return at
+	}
+	return tw.start + ((at-tw.start)/tw.bucketSize+1)*tw.bucketSize - 1
+}
@@ -0,0 +1,65 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApproximateExpiry(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, options := range [][]Option{{WithApproximateExpiry()}, {WithApproximateExpiry(), WithSliceBuckets()}} {
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		var invoked []int
+		record := func(idx int) Event {
+			return func(*time.Time) { invoked = append(invoked, idx) }
+		}
+		// out of order within the bucket
+		tw.ScheduleEventAt(start.Add(1900*time.Microsecond), record(0))
+		tw.ScheduleEventAt(start.Add(1100*time.Microsecond), record(1))
+		tw.ScheduleEventAt(start.Add(2*time.Millisecond), record(2))
+		if at, _ := tw.NextEventAt(); !at.Equal(start.Add(2*time.Millisecond - 1)) {
+			t.Errorf("Expected the end of the bucket, but got %v", at)
+		}
+		// not due until the end of the bucket
+		if count := tw.AdvanceTo(start.Add(1950*time.Microsecond), 0); count != 0 {
+			t.Errorf("Expected nothing invoked part way through a bucket, but got %v", count)
+		}
+		if count := tw.AdvanceTo(start.Add(2*time.Millisecond-1), 0); count != 2 ||
+			len(invoked) != 2 || invoked[0] != 0 || invoked[1] != 1 {
+			t.Errorf("Expected the bucket invoked in order of scheduling, but got %v", invoked)
+		}
+		// with a limit, the current time never goes backwards
+		tw.ScheduleEventAt(start.Add(3*time.Millisecond), record(3))
+		tw.ScheduleEventAt(start.Add(3*time.Millisecond), record(4))
+		tw.ScheduleEventAt(start.Add(3100*time.Microsecond), record(5))
+		tw.AdvanceTo(start.Add(3500*time.Microsecond), 0)
+		if count := tw.AdvanceTo(start.Add(4*time.Millisecond), 2); count != 2 || !tw.Now().Equal(start.Add(3500*time.Microsecond)) {
+			t.Errorf("Expected 2 events invoked and the time unchanged, but got %v at %v", count, tw.Now())
+		}
+		if _, count := tw.RunUntilEmpty(0); count != 1 || !tw.IsEmpty() {
+			t.Errorf("Expected RunUntilEmpty to invoke the last event, but got %v", count)
+		}
+	}
+}
+
+// Events for random times within each bucket, which an exact Timer
+// Wheel must insert in order.
+func BenchmarkApproximateExpiry(b *testing.B) {
+	for _, options := range [][]Option{nil, {WithApproximateExpiry()}} {
+		name := "exact"
+		if options != nil {
+			name = "approximate"
+		}
+		b.Run(name, func(b *testing.B) {
+			start := time.Unix(0, 0)
+			tw := NewTimerWheel(start, time.Millisecond, options...)
+			event := func(*time.Time) {}
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				at := time.Duration(n/100)*time.Millisecond + time.Duration((n*7919)%1000)*time.Microsecond
+				tw.ScheduleEventAt(start.Add(at), event)
+			}
+			tw.AdvanceBy(time.Duration(b.N/100+1)*time.Millisecond, 0)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package gotimerwheel
+
+type arena struct {
+	// 0 unless WithArena was given.
+	size int
+	// What is left of the block being allocated from.
+	block []eventNode
+}
+
+// Allocates events in blocks of size events at a time, rather than
+// one by one, so that a Timer Wheel holding millions of pending
+// events presents the garbage collector with a few thousand objects
+// rather than millions. A block is only garbage once every event in
+// it is: events which have been invoked or cancelled keep their
+// block alive for as long as any other event in it is pending (or is
+// still referred to, for example by an EventHandle), though
+// everything they refer to, such as their functions, is dropped as
+// soon as they settle. Clear and Close give up the block being
+// allocated from, so that the blocks of a Timer Wheel which is
+// cleared are freed as a whole. Size must be at least 1.
+func WithArena(size int) Option {
+	if size < 1 {
+		panic("TimerWheel arena size must be at least 1")
+	}
+	return func(tw *TimerWheel) {
+		tw.arena = arena{size: size}
+	}
+}
+
+// Returns a new event, a copy of event.
+func (tw *TimerWheel) newEvent(event eventNode) *eventNode {
+	a := &tw.root.arena
+	var node *eventNode
+	if a.size == 0 {
+		node = new(eventNode)
+	} else {
+		if len(a.block) == 0 {
+			a.block = make([]eventNode, a.size)
+		}
+		node = &a.block[0]
+		a.block = a.block[1:]
+	}
+	// Copied rather than returning &event, which would allocate event
+	// whether or not it is needed.
+	*node = event
+	return node
+}
+
+// Drops everything an event which has been invoked or cancelled refers
+// to, as its node may be kept alive by the other events of its block.
+// Its time is kept for EventHandle.At.
+func (tw *TimerWheel) retire(event *eventNode) {
+	if tw.root.arena.size > 0 {
+		*event = eventNode{at: event.at}
+	}
+}
+
+func (a *arena) release() {
+	a.block = nil
+}
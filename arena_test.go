@@ -0,0 +1,82 @@
+package gotimerwheel
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestArena(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithArena(1000))
+	fired := 0
+	e := func(*time.Time) { fired++ }
+	at := start
+	allocs := testing.AllocsPerRun(999, func() {
+		at = at.Add(time.Millisecond)
+		tw.ScheduleEventAt(at, e)
+	})
+	if allocs > 0.01 {
+		t.Errorf("Expected events to be allocated from the arena, but got %v allocations per event", allocs)
+	}
+	h1, _ := tw.ScheduleHandleAt(at, e)
+	h2, _ := tw.ScheduleHandleAt(at, e)
+	if h1.event == h2.event {
+		t.Fatal("Expected each event to have a node of its own")
+	}
+	clone := tw.Clone()
+	if len(clone.arena.block) != 0 {
+		t.Error("Expected a clone not to share the block being allocated from")
+	}
+	if count := tw.AdvanceTo(at, 0); count != 1002 || fired != 1002 {
+		t.Fatalf("Expected every event to be invoked, but invoked %v", count)
+	}
+	tw.ScheduleEventAt(at.Add(time.Second), e)
+	tw.Clear()
+	if tw.arena.block != nil {
+		t.Error("Expected Clear to give up the block being allocated from")
+	}
+	if clone.AdvanceTo(at, 0) != 1002 {
+		t.Error("Expected the clone's events to be invoked")
+	}
+}
+
+// Events which have settled must not keep what they refer to alive
+// while other events of their block are pending.
+func TestArenaRetires(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithArena(1000))
+	collected := make(chan string, 3)
+	schedule := func(name string, in time.Duration) *EventHandle {
+		payload := new([64]byte)
+		runtime.SetFinalizer(payload, func(*[64]byte) { collected <- name })
+		h, _ := tw.ScheduleHandleIn(in, func(*time.Time) { _ = payload[0] })
+		return h
+	}
+	fired := schedule("fired", time.Millisecond)
+	cancelled := schedule("cancelled", time.Hour)
+	cleared := schedule("cleared", time.Hour)
+	pending, _ := tw.ScheduleHandleIn(time.Hour, func(*time.Time) {})
+	tw.AdvanceBy(time.Millisecond, 0)
+	cancelled.Cancel()
+	tw.Clear()
+	remaining := map[string]bool{"fired": true, "cancelled": true, "cleared": true}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(remaining) > 0 && time.Now().Before(deadline) {
+		runtime.GC()
+		select {
+		case name := <-collected:
+			delete(remaining, name)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if len(remaining) > 0 {
+		t.Errorf("Expected the functions of settled events to be collectable, but %v were not", remaining)
+	}
+	if !fired.At().Equal(start.Add(time.Millisecond)) || cleared.State() != EventCancelled || pending.State() != EventCancelled {
+		t.Error("Expected the handles to keep working")
+	}
+	// The EventHandles keep their nodes, and so the block, alive.
+	runtime.KeepAlive(fired)
+	runtime.KeepAlive(cancelled)
+}
@@ -0,0 +1,130 @@
+package gotimerwheel
+
+import (
+	"sort"
+	"time"
+)
+
+// An event together with the time at which it should be invoked. See
+// ScheduleEvents.
+type ScheduledEvent struct {
+	At    time.Time
+	Event Event
+	// Optional. See ScheduleTaggedEventAt.
+	Tag string
+	// Optional. See SchedulePriorityEventAt.
+	Priority int
+}
+
+// Schedules many events in one go. This is equivalent to calling
+// ScheduleEventAt for each of them in turn, but much cheaper for
+// large numbers of events: the events are sorted first so that each
+// bucket of the root wheel is built up in a single pass. If any of
+// the events is in the past of the Timer Wheel's current time then
+// ScheduledInPast is returned and none of the events are scheduled,
+// unless another policy has been set with WithPastPolicy; likewise
+// NilEvent if any has a nil Event (see WithNilEvents),
+// DuplicateEvent if duplicates are being rejected (see
+// WithDuplicateDetection), and TooFarInFuture if any is beyond the
+// horizon (see WithMaxLevels).
+func (tw *TimerWheel) ScheduleEvents(events []ScheduledEvent) error {
+	if tw.closed {
+		return Closed
+	}
+	for idx := range events {
+		if events[idx].Event == nil && !tw.nilEvents {
+			return NilEvent
+		}
+		if err := tw.refusePast(events[idx].At.UnixNano()); err != nil {
+			return err
+		}
+		if tw.refuseBeyond(events[idx].At.UnixNano()) {
+			return TooFarInFuture
+		}
+	}
+	if tw.rejectDuplicates(events) {
+		return DuplicateEvent
+	}
+	if tw.full(len(events)) {
+		return WheelFull
+	}
+	nodes := make([]*eventNode, len(events))
+	for idx := range events {
+		event := &events[idx]
+		nodes[idx] = tw.newEvent(eventNode{at: event.At.UnixNano(), fun: event.Event, tag: event.Tag, priority: event.Priority})
+	}
+	sortEvents(nodes, tw.tieBreak)
+	nodes, past := tw.splitPast(nodes)
+	tw.scheduleSortedEvents(nodes)
+	for _, event := range past {
+		tw.invokePast(event)
+	}
+	return nil
+}
+
+// Schedules events, which must already be sorted and must not be in
+// the past.
+func (tw *TimerWheel) scheduleSortedEvents(nodes []*eventNode) {
+	tw.stats.scheduled += uint64(len(nodes))
+	for _, event := range nodes {
+		tw.trackDuplicate(event)
+	}
+	if tw.observer != nil {
+		for _, event := range nodes {
+			tw.observer.OnScheduled(tw.toTime(event.at))
+		}
+	}
+	tw.placeSortedEvents(nodes)
+}
+
+// Puts events, which must already be sorted and must not be in the
+// past, into the buckets which hold them.
+func (tw *TimerWheel) placeSortedEvents(nodes []*eventNode) {
+	if tw.cascade.head != nil || tw.approximate || tw.deferral.advancing {
+		for _, event := range nodes {
+			tw.placeEvent(event)
+		}
+		return
+	}
+	for len(nodes) > 0 {
+		idx := int((nodes[0].at - tw.start) / tw.bucketSize)
+		if idx >= len(tw.ring) {
+			// Everything else is beyond the root wheel too.
+			for _, event := range nodes {
+				tw.scheduleBeyond(event)
+			}
+			break
+		}
+		// Find the run of events that belongs to this bucket.
+		bucketEnd := tw.start + int64(idx+1)*tw.bucketSize
+		run := sort.Search(len(nodes), func(i int) bool { return nodes[i].at >= bucketEnd })
+		b := &(tw.ring[idx])
+		b.addSortedEvents(nodes[:run])
+		tw.noteOccupancy(idx, run)
+		nodes = nodes[run:]
+	}
+}
+
+// Merges events, which must already be sorted, into the bucket in a
+// single pass over the bucket.
+func (b *bucket) addSortedEvents(events []*eventNode) {
+	if b.unsorted || b.sliced {
+		for _, event := range events {
+			b.appendEvent(event)
+		}
+		return
+	}
+	enContainer := &b.eventNodeContainer
+	for _, event := range events {
+		for enContainer.eventNode != nil && !before(event, enContainer.eventNode, b.tieBreak) {
+			enContainer = &enContainer.eventNode.next
+		}
+		event.next.eventNode = enContainer.eventNode
+		enContainer.eventNode = event
+		enContainer = &event.next
+	}
+	if enContainer.eventNode == nil && len(events) > 0 {
+		b.tail = events[len(events)-1]
+	}
+	b.count += len(events)
+}
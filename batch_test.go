@@ -0,0 +1,62 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleEvents(t *testing.T) {
+	var run *callbackRun
+	run = newCallbackRun(t, time.Unix(0, 10), time.Unix(0, 400), 5,
+		e{time.Unix(0, 10), func(*time.Time) { run.assertExecCount(0) }},
+		e{time.Unix(0, 171), func(*time.Time) { run.assertExecCount(6) }},
+		e{time.Unix(0, 15), func(*time.Time) { run.assertExecCount(3) }},
+		e{time.Unix(0, 14), func(*time.Time) { run.assertExecCount(2) }},
+		e{time.Unix(0, 330), func(*time.Time) { run.assertExecCount(7) }},
+		e{time.Unix(0, 13), func(*time.Time) { run.assertExecCount(1) }},
+		e{time.Unix(0, 170), func(*time.Time) { run.assertExecCount(5) }},
+		e{time.Unix(0, 16), func(*time.Time) { run.assertExecCount(4) }},
+	)
+	// an event already in the wheel is merged with the batch
+	run.ScheduleEventAt(time.Unix(0, 399), func(*time.Time) { run.assertExecCount(8) })
+	batch := make([]ScheduledEvent, len(run.events))
+	for idx, event := range run.events {
+		batch[idx] = ScheduledEvent{At: event.Time, Event: event.Event}
+	}
+	if err := run.ScheduleEvents(batch); err != nil {
+		t.Fatal(err)
+	}
+	assertNowLength(t, run.TimerWheel, run.start, len(batch)+1)
+	if count := run.AdvanceTo(run.end, 0); count != len(batch)+1 {
+		t.Errorf("Expected %v callbacks to be invoked, but got %v", len(batch)+1, count)
+	}
+	run.assertExecCount(len(batch) + 1)
+}
+
+func TestScheduleEventsInPast(t *testing.T) {
+	start := time.Unix(0, 10)
+	tw := NewTimerWheel(start, 1, WithNilEvents())
+	err := tw.ScheduleEvents([]ScheduledEvent{
+		{At: time.Unix(0, 20)},
+		{At: time.Unix(0, 9)},
+	})
+	if !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	assertNowLength(t, tw, start, 0)
+}
+
+func BenchmarkScheduleEvents(b *testing.B) {
+	start := time.Unix(0, 0)
+	batch := make([]ScheduledEvent, 10000)
+	for idx := range batch {
+		// all into the same few buckets, in reverse order
+		batch[idx] = ScheduledEvent{At: start.Add(time.Duration(len(batch) - idx))}
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tw := NewTimerWheel(start, time.Duration(len(batch)/4), WithNilEvents())
+		tw.ScheduleEvents(batch)
+	}
+}
@@ -0,0 +1,18 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Like AdvanceTo, but treats now as an exclusive boundary: only the
+// events scheduled strictly before now are invoked, which suits
+// deadlines defined as "before" some time. The Timer Wheel's current
+// time still becomes now (unless limit stops the advance early), so
+// events scheduled for exactly now stay scheduled, and are invoked
+// by the next advance which includes now. Events invoked are passed
+// now, as with AdvanceTo, and an event they schedule for now is not
+// invoked by this advance. Returns the number of events invoked.
+func (tw *TimerWheel) AdvanceBefore(now time.Time, limit int) int {
+	count, _ := tw.advance(now, now.UnixNano()-1, limitTo(limit))
+	return count
+}
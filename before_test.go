@@ -0,0 +1,42 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvanceBefore(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	var invoked []time.Duration
+	record := func(d time.Duration) {
+		tw.ScheduleEventAt(start.Add(d), func(now *time.Time) {
+			if !now.Equal(start.Add(2 * time.Millisecond)) {
+				t.Errorf("Expected the events passed the boundary, but got %v", now)
+			}
+			invoked = append(invoked, d)
+		})
+	}
+	record(time.Millisecond)
+	record(2*time.Millisecond - 1)
+	record(2 * time.Millisecond)
+	tw.ScheduleEventAt(start.Add(time.Millisecond), func(now *time.Time) {
+		// for the current time, so not invoked by this advance
+		tw.ScheduleEventAt(*now, func(*time.Time) { invoked = append(invoked, -1) })
+	})
+	if count := tw.AdvanceBefore(start.Add(2*time.Millisecond), 0); count != 3 || len(invoked) != 2 {
+		t.Errorf("Expected only the events before the boundary, but got %v", invoked)
+	}
+	if !tw.Now().Equal(start.Add(2*time.Millisecond)) || tw.Length() != 2 {
+		t.Errorf("Expected the boundary reached with 2 events left, but got %v at %v", tw.Length(), tw.Now())
+	}
+	// the boundary itself is included by the next AdvanceTo
+	if count := tw.AdvanceTo(start.Add(2*time.Millisecond), 0); count != 2 || invoked[3] != -1 {
+		t.Errorf("Expected the events at the boundary invoked, but got %v", invoked)
+	}
+	// across buckets and levels
+	tw.ScheduleEventAt(start.Add(time.Second), func(*time.Time) { invoked = append(invoked, time.Second) })
+	if tw.AdvanceBefore(start.Add(time.Second), 0) != 0 || tw.AdvanceTo(start.Add(time.Second), 0) != 1 {
+		t.Error("Expected the event after the boundary invoked only by AdvanceTo")
+	}
+}
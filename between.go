@@ -0,0 +1,109 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Returns the number of events scheduled for from or later but
+// before to, such as the work due in the next few seconds. Buckets
+// which lie wholly within the range are counted without looking at
+// their events, so only the buckets at either end of the range are
+// walked, and this is O(buckets) rather than O(n) for however many
+// events are scheduled.
+func (tw *TimerWheel) CountBetween(from, to time.Time) int {
+	count := 0
+	tw.eachBetween(from.UnixNano(), to.UnixNano(), func(b *bucket) {
+		count += b.count
+	}, func(*eventNode) {
+		count++
+	})
+	return count
+}
+
+// Returns the events scheduled for from or later but before to, in the
+// order in which they would be invoked. Unlike CountBetween, this
+// walks the events scheduled before from as well.
+func (tw *TimerWheel) EventsBetween(from, to time.Time) []ScheduledEvent {
+	lo, hi := from.UnixNano(), to.UnixNano()
+	var events []ScheduledEvent
+	tw.forEachEvent(func(event *eventNode) bool {
+		if event.at >= hi {
+			return false
+		}
+		if event.at >= lo {
+			events = append(events, ScheduledEvent{At: tw.toTime(event.at), Event: event.fun, Tag: event.tag, Priority: event.priority})
+		}
+		return true
+	})
+	return events
+}
+
+// Cancels every event scheduled for from or later but before to, as
+// when a downstream dependency has gone away and its burst of retries
+// is no longer wanted, and returns the number cancelled. Keyed events
+// lose their keys. As with CountBetween, buckets which lie wholly
+// within the range are emptied at once rather than event by event.
+func (tw *TimerWheel) CancelBetween(from, to time.Time) int {
+	var emptied, events []*eventNode
+	collect := func(event *eventNode) bool {
+		emptied = append(emptied, event)
+		return true
+	}
+	tw.eachBetween(from.UnixNano(), to.UnixNano(), func(b *bucket) {
+		b.each(collect)
+		*b = bucket{sliced: b.sliced, tieBreak: b.tieBreak}
+	}, func(event *eventNode) {
+		events = append(events, event)
+	})
+	for _, event := range events {
+		if tw.removeEvent(event) {
+			emptied = append(emptied, event)
+		}
+	}
+	for _, event := range emptied {
+		tw.forgetKey(event)
+		tw.cancelled(event, "cancel")
+	}
+	if len(emptied) > 0 {
+		tw.dropEmptyLevels()
+	}
+	return len(emptied)
+}
+
+// Calls whole for each bucket of the hierarchy lying wholly within
+// [lo, hi), and each for every other event within it, in no
+// particular order.
+func (tw *TimerWheel) eachBetween(lo, hi int64, whole func(*bucket), each func(*eventNode)) {
+	if hi <= lo {
+		return
+	}
+	within := func(event *eventNode) bool {
+		if event.at >= lo && event.at < hi {
+			each(event)
+		}
+		return true
+	}
+	for level := tw; level != nil && level.start < hi; level = level.next {
+		for idx := level.ringIdx; idx < len(level.ring); idx++ {
+			b := &level.ring[idx]
+			start := level.start + int64(idx)*level.bucketSize
+			end := start + level.bucketSize
+			if start >= hi {
+				break
+			}
+			switch {
+			case b.count == 0 || end <= lo:
+			case start >= lo && end <= hi:
+				whole(b)
+			default:
+				b.each(within)
+			}
+		}
+		for event := level.cascade.head; event != nil; event = event.next.eventNode {
+			within(event)
+		}
+	}
+	for _, entry := range tw.overflow.events {
+		within(entry.event)
+	}
+}
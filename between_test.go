@@ -0,0 +1,106 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCountBetween(t *testing.T) {
+	start := time.Unix(0, 0)
+	rng := rand.New(rand.NewSource(1092))
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}, {WithOverflowHeap(2)}, {WithIncrementalCascade(5)}} {
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		for idx := 0; idx < 5000; idx++ {
+			tw.ScheduleEventAt(start.Add(time.Duration(rng.Int63n(int64(time.Hour)))), func(*time.Time) {})
+		}
+		for round := 0; round < 20; round++ {
+			tw.AdvanceBy(time.Duration(rng.Int63n(int64(time.Second))), 0)
+			from := tw.Now().Add(time.Duration(rng.Int63n(int64(time.Minute))))
+			to := from.Add(time.Duration(rng.Int63n(int64(10 * time.Minute))))
+			// before ForEach finishes any cascade under way
+			count := tw.CountBetween(from, to)
+			var all []time.Time
+			tw.ForEach(func(at time.Time, e Event) bool {
+				all = append(all, at)
+				return true
+			})
+			expected := 0
+			for _, at := range all {
+				if !at.Before(from) && at.Before(to) {
+					expected++
+				}
+			}
+			if count != expected {
+				t.Fatalf("Expected %v events between %v and %v, but got %v", expected, from, to, count)
+			}
+			events := tw.EventsBetween(from, to)
+			if len(events) != expected {
+				t.Fatalf("Expected %v events between %v and %v, but got %v", expected, from, to, len(events))
+			}
+			for idx := range events {
+				if events[idx].At.Before(from) || !events[idx].At.Before(to) ||
+					(idx > 0 && events[idx].At.Before(events[idx-1].At)) {
+					t.Fatalf("Unexpected event at %v", events[idx].At)
+				}
+			}
+		}
+		if tw.CountBetween(start.Add(time.Hour), start) != 0 || tw.CountBetween(start, start.Add(2*time.Hour)) != tw.Length() {
+			t.Error("Expected an empty range to count nothing and the whole range everything")
+		}
+	}
+}
+
+func TestCancelBetween(t *testing.T) {
+	start := time.Unix(0, 0)
+	rng := rand.New(rand.NewSource(1093))
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}, {WithOverflowHeap(2)}, {WithIncrementalCascade(5)}} {
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		fired := 0
+		e := func(*time.Time) { fired++ }
+		var handles []*EventHandle
+		for idx := 0; idx < 5000; idx++ {
+			at := start.Add(time.Duration(rng.Int63n(int64(time.Hour))))
+			switch idx % 3 {
+			case 0:
+				tw.ScheduleEventAt(at, e)
+			case 1:
+				h, _ := tw.ScheduleHandleAt(at, e)
+				handles = append(handles, h)
+			default:
+				tw.ScheduleKeyedEventAt(fmt.Sprint(idx), at, e)
+			}
+		}
+		tw.AdvanceBy(time.Duration(rng.Int63n(int64(time.Second))), 0)
+		from := tw.Now().Add(time.Duration(rng.Int63n(int64(time.Minute))))
+		to := from.Add(10 * time.Minute)
+		length, expected := tw.Length(), tw.CountBetween(from, to)
+		if count := tw.CancelBetween(from, to); count != expected {
+			t.Fatalf("Expected to cancel %v events, but cancelled %v", expected, count)
+		}
+		if tw.CountBetween(from, to) != 0 || len(tw.EventsBetween(from, to)) != 0 || tw.Length() != length-expected {
+			t.Fatalf("Expected no events between %v and %v, and %v left", from, to, length-expected)
+		}
+		for _, h := range handles {
+			within := !h.At().Before(from) && h.At().Before(to)
+			if within != (h.State() == EventCancelled) {
+				t.Fatalf("Unexpected state %v for event at %v", h.State(), h.At())
+			}
+		}
+		if stats := tw.Stats(); stats.Cancelled != uint64(expected) {
+			t.Errorf("Expected %v events counted as cancelled, but got %v", expected, stats.Cancelled)
+		}
+		for key, event := range tw.keys {
+			if at := tw.toTime(event.at); !at.Before(from) && at.Before(to) {
+				t.Fatalf("Expected key %v at %v to have been forgotten", key, at)
+			}
+		}
+		for !tw.IsEmpty() {
+			tw.AdvanceBy(time.Minute, 0)
+		}
+		if fired+expected != 5000 || int(tw.Stats().Fired) != fired {
+			t.Errorf("Expected %v events to fire, but %v did", 5000-expected, fired)
+		}
+	}
+}
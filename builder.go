@@ -0,0 +1,76 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	EventTimeNotSet = errors.New("Event built without a time")
+)
+
+// A builder of ScheduledEvents. Create one with NewEvent, set its
+// properties and finally call Build, which validates the event
+// against the Timer Wheel it is destined for.
+type EventBuilder struct {
+	event ScheduledEvent
+	atSet bool
+}
+
+// Starts building a ScheduledEvent which will invoke e.
+func NewEvent(e Event) *EventBuilder {
+	return &EventBuilder{event: ScheduledEvent{Event: e}}
+}
+
+// Sets the time at which the event should be invoked, replacing any
+// time set previously.
+func (eb *EventBuilder) At(at time.Time) *EventBuilder {
+	eb.event.At = at
+	eb.atSet = true
+	return eb
+}
+
+// Sets the event's priority. See SchedulePriorityEventAt.
+func (eb *EventBuilder) Priority(priority int) *EventBuilder {
+	eb.event.Priority = priority
+	return eb
+}
+
+// Sets the event's tag. See ScheduleTaggedEventAt.
+func (eb *EventBuilder) Tag(tag string) *EventBuilder {
+	eb.event.Tag = tag
+	return eb
+}
+
+// Validates the event against tw and returns it, ready to be passed
+// to tw's ScheduleEvent or ScheduleEvents. Returns EventTimeNotSet if
+// At was never called, NilEvent if the event is nil and tw refuses
+// such events (see WithNilEvents), ScheduledInPast if the time is in
+// the past of tw's current time and tw refuses such events (see
+// WithPastPolicy), TooFarInFuture if the time is beyond tw's horizon
+// (see WithMaxLevels), and DuplicateEvent if tw rejects duplicates
+// (see WithDuplicateDetection) and already has an event with the
+// same tag and time. Nothing is scheduled, and validation does not
+// count towards tw's Stats.
+func (eb *EventBuilder) Build(tw *TimerWheel) (ScheduledEvent, error) {
+	event := eb.event
+	switch {
+	case !eb.atSet:
+		return ScheduledEvent{}, EventTimeNotSet
+	case event.Event == nil && !tw.nilEvents:
+		return ScheduledEvent{}, NilEvent
+	case tw.pastPolicy == PastError && event.At.UnixNano() < tw.now:
+		return ScheduledEvent{}, tw.pastError(event.At.UnixNano())
+	case tw.refuseBeyond(event.At.UnixNano()):
+		return ScheduledEvent{}, TooFarInFuture
+	case tw.isDuplicate(event.Tag, event.At.UnixNano()):
+		return ScheduledEvent{}, DuplicateEvent
+	default:
+		return event, nil
+	}
+}
+
+// Schedules a single event. See ScheduleEventAt.
+func (tw *TimerWheel) ScheduleEvent(event ScheduledEvent) error {
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: event.At.UnixNano(), fun: event.Event, tag: event.Tag, priority: event.Priority}))
+}
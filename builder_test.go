@@ -0,0 +1,74 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventBuilder(t *testing.T) {
+	counts := map[string]int{}
+	tw := NewTimerWheel(time.Unix(0, 0), 1, WithAggregation(func(now time.Time, c map[string]int) { counts = c }), WithNilEvents())
+	// a later At replaces an earlier one
+	event, err := NewEvent(nil).At(time.Unix(0, 9)).At(time.Unix(0, 5)).Tag("x").Build(tw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !event.At.Equal(time.Unix(0, 5)) {
+		t.Errorf("Expected the last time set to be used, but got %v", event.At)
+	}
+	if err = tw.ScheduleEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	tw.AdvanceTo(time.Unix(0, 5), 0)
+	if counts["x"] != 1 {
+		t.Errorf("Expected tagged event to fire, but got %v", counts)
+	}
+
+	if _, err = NewEvent(nil).Tag("x").Build(tw); err != EventTimeNotSet {
+		t.Errorf("Expected EventTimeNotSet, but got %v", err)
+	}
+	if _, err = NewEvent(nil).At(time.Unix(0, 1)).Build(tw); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+}
+
+func TestEventBuilderDuplicate(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1, WithDuplicateDetection(DuplicateReject))
+	builder := NewEvent(func(*time.Time) {}).At(time.Unix(0, 5)).Tag("timeout")
+	event, err := builder.Build(tw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.ScheduleEvent(event)
+	if _, err = builder.Build(tw); err != DuplicateEvent {
+		t.Errorf("Expected DuplicateEvent, but got %v", err)
+	}
+	if _, err = builder.Tag("other").Build(tw); err != nil {
+		t.Errorf("Expected a different tag to be fine, but got %v", err)
+	}
+	if duplicates := tw.Stats().Duplicates; duplicates != 0 {
+		t.Errorf("Expected validation not to count duplicates, but got %v", duplicates)
+	}
+}
+
+func TestEventBuilderPriority(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	var invoked []string
+	for _, name := range []string{"low", "high"} {
+		name := name
+		priority := 0
+		if name == "high" {
+			priority = 1
+		}
+		event, err := NewEvent(func(*time.Time) { invoked = append(invoked, name) }).At(time.Unix(0, 5)).Priority(priority).Build(tw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tw.ScheduleEvent(event)
+	}
+	tw.AdvanceTo(time.Unix(0, 5), 0)
+	if len(invoked) != 2 || invoked[0] != "high" || invoked[1] != "low" {
+		t.Errorf("Expected [high low], but got %v", invoked)
+	}
+}
@@ -0,0 +1,114 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	WheelFull = errors.New("The Timer Wheel already holds its maximum number of events")
+)
+
+type capacity struct {
+	max     int
+	evicted func(at time.Time, e Event)
+	evict   bool
+}
+
+// Limits the number of events which may be scheduled at once, so that
+// callers scheduling events on behalf of clients cannot exhaust
+// memory. Once max events are scheduled, scheduling another returns
+// WheelFull, unless an eviction policy has been set with
+// WithEviction. Max must be greater than 0.
+func WithCapacity(max int) Option {
+	if max <= 0 {
+		panic("TimerWheel capacity must be greater than 0")
+	}
+	return func(tw *TimerWheel) {
+		tw.capacity.max = max
+	}
+}
+
+// When the Timer Wheel is at its capacity (see WithCapacity),
+// scheduling a single event evicts the farthest-future scheduled
+// event to make room, rather than returning WheelFull. The evicted
+// event is cancelled and passed to evicted, which may be nil. If the
+// new event is itself later than every scheduled event then it is
+// refused with WheelFull as usual. ScheduleEvents and Merge never
+// evict. Helpers such as DeadlineTree, StagedTimeout and Timer do not
+// notice their events being evicted.
+func WithEviction(evicted func(at time.Time, e Event)) Option {
+	return func(tw *TimerWheel) {
+		tw.capacity.evict = true
+		tw.capacity.evicted = evicted
+	}
+}
+
+// Returns the number of scheduled events, in O(1).
+func (tw *TimerWheel) pending() int {
+	s := &tw.stats
+	return int(s.scheduled - s.fired - s.expired - s.cancelled)
+}
+
+// Returns true if there is no room for count more events.
+func (tw *TimerWheel) full(count int) bool {
+	return tw.capacity.max > 0 && tw.pending()+count > tw.capacity.max
+}
+
+// Makes room for an event for at, evicting if permitted. Returns
+// false if there is no room.
+func (tw *TimerWheel) makeRoom(at int64) bool {
+	if !tw.full(1) {
+		return true
+	}
+	if !tw.capacity.evict {
+		return false
+	}
+	latest := tw.latestEvent()
+	if latest == nil || latest.at <= at {
+		return false
+	}
+	at, fun := latest.at, latest.fun
+	tw.forgetKey(latest)
+	tw.cancelEventFor(latest, "evict")
+	if tw.capacity.evicted != nil {
+		tw.capacity.evicted(tw.toTime(at), fun)
+	}
+	return true
+}
+
+// Returns one of the farthest-future scheduled events, or nil if
+// there are none.
+func (tw *TimerWheel) latestEvent() *eventNode {
+	tw.completeCascades()
+	var latest *eventNode
+	for _, entry := range tw.overflow.events {
+		if latest == nil || entry.event.at > latest.at {
+			latest = entry.event
+		}
+	}
+	if latest != nil {
+		return latest
+	}
+	var levels []*TimerWheel
+	for level := tw; level != nil; level = level.next {
+		levels = append(levels, level)
+	}
+	// Every bucket of a level is later than every bucket of the
+	// level before it, but a level may have become empty.
+	for idx := len(levels) - 1; idx >= 0; idx-- {
+		level := levels[idx]
+		for bucketIdx := len(level.ring) - 1; bucketIdx >= level.ringIdx; bucketIdx-- {
+			level.ring[bucketIdx].each(func(event *eventNode) bool {
+				if latest == nil || event.at > latest.at {
+					latest = event
+				}
+				return true
+			})
+			if latest != nil {
+				return latest
+			}
+		}
+	}
+	return nil
+}
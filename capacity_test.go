@@ -0,0 +1,76 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCapacity(t *testing.T) {
+	nop := func(*time.Time) {}
+	tw := NewTimerWheel(time.Unix(0, 0), 10, WithCapacity(3))
+	for _, at := range []int64{10, 20, 30} {
+		if err := tw.ScheduleEventAt(time.Unix(0, at), nop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.ScheduleEventAt(time.Unix(0, 5), nop); err != WheelFull {
+		t.Errorf("Expected WheelFull, but got %v", err)
+	}
+	if err := tw.ScheduleEvents([]ScheduledEvent{{At: time.Unix(0, 5), Event: nop}}); err != WheelFull {
+		t.Errorf("Expected WheelFull, but got %v", err)
+	}
+	tw.AdvanceTo(time.Unix(0, 10), 0)
+	if err := tw.ScheduleEventAt(time.Unix(0, 15), nop); err != nil {
+		t.Errorf("Expected room once an event has fired, but got %v", err)
+	}
+	other := NewTimerWheel(time.Unix(0, 0), 10)
+	other.ScheduleEventAt(time.Unix(0, 50), nop)
+	if err := tw.Merge(other); err != WheelFull || other.Length() != 1 {
+		t.Errorf("Expected WheelFull and nothing moved, but got %v", err)
+	}
+	assertNowLength(t, tw, time.Unix(0, 10), 3)
+}
+
+func TestEviction(t *testing.T) {
+	nop := func(*time.Time) {}
+	var evicted []time.Time
+	tw := NewTimerWheel(time.Unix(0, 0), 10, WithCapacity(3), WithEviction(func(at time.Time, e Event) {
+		evicted = append(evicted, at)
+	}))
+	// spread over several levels of the hierarchy
+	for _, at := range []int64{10, 5000, 400} {
+		tw.ScheduleEventAt(time.Unix(0, at), nop)
+	}
+	tw.ScheduleKeyedEventAt("key", time.Unix(0, 20), nop)
+	if len(evicted) != 1 || !evicted[0].Equal(time.Unix(0, 5000)) {
+		t.Errorf("Expected the event at 5000 to be evicted, but got %v", evicted)
+	}
+	if err := tw.ScheduleEventAt(time.Unix(0, 9000), nop); err != WheelFull {
+		t.Errorf("Expected WheelFull for an event later than every other, but got %v", err)
+	}
+	tw.ScheduleEventAt(time.Unix(0, 15), nop)
+	tw.ScheduleEventAt(time.Unix(0, 12), nop)
+	if len(evicted) != 3 || !evicted[1].Equal(time.Unix(0, 400)) || !evicted[2].Equal(time.Unix(0, 20)) {
+		t.Errorf("Expected the events at 400 and 20 to be evicted, but got %v", evicted)
+	}
+	if tw.CancelKey("key") {
+		t.Error("Expected the evicted keyed event to be forgotten")
+	}
+	if stats := tw.Stats(); stats.Cancelled != 3 || tw.Length() != 3 {
+		t.Errorf("Expected 3 cancelled and 3 scheduled, but got %+v", stats)
+	}
+}
+
+func TestEvictionRefusedDuplicate(t *testing.T) {
+	nop := func(*time.Time) {}
+	evicted := 0
+	tw := NewTimerWheel(time.Unix(0, 0), 10, WithCapacity(2), WithDuplicateDetection(DuplicateReject), WithEviction(func(time.Time, Event) { evicted++ }))
+	tw.ScheduleTaggedEventAt("timeout", time.Unix(0, 10), nop)
+	tw.ScheduleEventAt(time.Unix(0, 20), nop)
+	if err := tw.ScheduleTaggedEventAt("timeout", time.Unix(0, 10), nop); err != DuplicateEvent {
+		t.Errorf("Expected DuplicateEvent, but got %v", err)
+	}
+	if evicted != 0 || tw.Length() != 2 {
+		t.Errorf("Expected the refused duplicate to evict nothing, but got %v evicted and %v left", evicted, tw.Length())
+	}
+}
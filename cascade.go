@@ -0,0 +1,138 @@
+package gotimerwheel
+
+// Events cascaded down from the next wheel which have yet to be put
+// in this wheel's buckets, in order of insertion. See
+// WithIncrementalCascade.
+type pendingCascade struct {
+	head  *eventNode
+	tail  *eventNode
+	count int
+}
+
+// Spreads the work of cascading events down from the next wheel
+// across calls to AdvanceTo (or AdvanceBy and so on), moving at most
+// budget events into their new buckets per call. Without this, the
+// advance which passes the end of the root wheel's ring moves every
+// event of the next wheel's bucket at once, and so once per ring of
+// the root wheel an advance may stall for as long as that takes. With
+// it, an advance which runs out of budget stops at the start of the
+// bucket it has reached, just as if it had hit its limit: the events
+// invoked so far are returned, the Timer Wheel's current time is left
+// at the start of that bucket, and the next advance carries on moving
+// events before invoking any. Keep calling AdvanceTo while
+// IsCascading returns true. Cascades between next wheels are spread
+// out in the same way, from the budget left over once the root wheel
+// is up to date. Events scheduled, extended or cancelled whilst a
+// cascade is under way keep their order with the events being
+// cascaded, but calls which walk every event (such as ForEach,
+// NextEventAt, Levels and Clone) first finish the cascade. Budget
+// must be at least 1.
+func WithIncrementalCascade(budget int) Option {
+	if budget < 1 {
+		panic("TimerWheel cascade budget must be at least 1")
+	}
+	return func(tw *TimerWheel) {
+		tw.cascadeBudget = budget
+	}
+}
+
+// Returns true if an advance has stopped part way through cascading
+// events down to the root wheel (see WithIncrementalCascade), so
+// events due by the target of that advance may not have been invoked.
+func (tw *TimerWheel) IsCascading() bool {
+	return tw.cascade.head != nil
+}
+
+func (c *pendingCascade) append(event *eventNode) {
+	event.next.eventNode = nil
+	if c.tail == nil {
+		c.head = event
+	} else {
+		c.tail.next.eventNode = event
+	}
+	c.tail = event
+	c.count++
+}
+
+// Returns false if the event is not pending.
+func (c *pendingCascade) remove(event *eventNode) bool {
+	var prev *eventNode
+	for cur := c.head; cur != nil; prev, cur = cur, cur.next.eventNode {
+		if cur != event {
+			continue
+		}
+		if prev == nil {
+			c.head = cur.next.eventNode
+		} else {
+			prev.next.eventNode = cur.next.eventNode
+		}
+		if c.tail == cur {
+			c.tail = prev
+		}
+		cur.next.eventNode = nil
+		c.count--
+		return true
+	}
+	return false
+}
+
+// Takes over a bucket's events, which must already be in order of
+// insertion, from first to last.
+func (tw *TimerWheel) deferCascade(first, last *eventNode, count int) {
+	c := &tw.cascade
+	if c.tail == nil {
+		c.head = first
+	} else {
+		c.tail.next.eventNode = first
+	}
+	c.tail = last
+	c.count += count
+}
+
+// Moves up to *budget pending events into this wheel's buckets,
+// reducing *budget accordingly. A negative budget moves them all.
+func (tw *TimerWheel) migrate(budget *int) {
+	c := &tw.cascade
+	for c.head != nil && *budget != 0 {
+		event := c.head
+		c.head = event.next.eventNode
+		c.count--
+		tw.addEvent(event)
+		*budget--
+	}
+	if c.head == nil {
+		c.tail = nil
+	}
+}
+
+// Moves every pending event of this wheel into its buckets.
+func (tw *TimerWheel) completeCascade() {
+	if tw.cascade.head != nil {
+		all := -1
+		tw.migrate(&all)
+	}
+}
+
+// Moves every pending event of every wheel in the hierarchy into its
+// buckets, for the calls which walk every event.
+func (tw *TimerWheel) completeCascades() {
+	if tw.cascadeBudget == 0 {
+		return
+	}
+	for level := tw; level != nil; level = level.next {
+		level.completeCascade()
+	}
+}
+
+// Spends the budget on the pending events of each wheel, root first.
+// Returns false if the root wheel still has events pending, in which
+// case the advance must not invoke any more events.
+func (tw *TimerWheel) cascadeWithin(budget *int) bool {
+	if tw.cascadeBudget == 0 {
+		return true
+	}
+	for level := tw; level != nil && *budget > 0; level = level.next {
+		level.migrate(budget)
+	}
+	return tw.cascade.head == nil
+}
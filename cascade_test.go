@@ -0,0 +1,122 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestIncrementalCascade(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithIncrementalCascade(10))
+	invoked := 0
+	for idx := 0; idx < 100; idx++ {
+		tw.ScheduleEventAt(start.Add(40*time.Millisecond), func(*time.Time) { invoked++ })
+	}
+	// the advance which passes the end of the ring moves only 10
+	if count := tw.AdvanceTo(start.Add(50*time.Millisecond), 0); count != 0 || !tw.Now().Equal(start.Add(32*time.Millisecond)) {
+		t.Errorf("Expected the advance to stop at the new window, but got %v at %v", count, tw.Now())
+	}
+	if tw.Length() != 100 || tw.cascade.count != 90 {
+		t.Errorf("Expected 90 events still to be cascaded, but got %v", tw.cascade.count)
+	}
+	calls := 1
+	for tw.IsCascading() {
+		tw.AdvanceTo(start.Add(50*time.Millisecond), 0)
+		calls++
+	}
+	if calls != 10 || invoked != 100 {
+		t.Errorf("Expected 10 calls invoking 100 events, but got %v and %v", calls, invoked)
+	}
+
+	// walking every event finishes the cascade
+	for idx := 0; idx < 20; idx++ {
+		tw.ScheduleEventAt(start.Add(90*time.Millisecond), func(*time.Time) {})
+	}
+	tw.AdvanceTo(start.Add(70*time.Millisecond), 0)
+	if tw.cascade.count != 10 {
+		t.Fatalf("Expected 10 events still to be cascaded, but got %v", tw.cascade.count)
+	}
+	if at, found := tw.NextEventAt(); !found || !at.Equal(start.Add(90*time.Millisecond)) || tw.cascade.count != 0 {
+		t.Errorf("Expected the cascade finished, but got %v with %v pending", at, tw.cascade.count)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a budget of 0 to panic")
+			}
+		}()
+		WithIncrementalCascade(0)
+	}()
+}
+
+// Scheduling, cancelling and extending events whilst a cascade is
+// under way gives the same order of invocation as cascading all at
+// once.
+func TestIncrementalCascadeOrder(t *testing.T) {
+	start := time.Unix(0, 0)
+	rng := rand.New(rand.NewSource(1089))
+	var expected, got []int
+	blue := NewTimerWheel(start, time.Millisecond)
+	green := NewTimerWheel(start, time.Millisecond, WithIncrementalCascade(3))
+	var blueHandles, greenHandles []*EventHandle
+	var ats []time.Time
+	schedule := func(at time.Time) {
+		idx := len(ats)
+		h, err := blue.ScheduleHandleAt(at, func(*time.Time) { expected = append(expected, idx) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		blueHandles = append(blueHandles, h)
+		if h, err = green.ScheduleHandleAt(at, func(*time.Time) { got = append(got, idx) }); err != nil {
+			t.Fatal(err)
+		}
+		greenHandles = append(greenHandles, h)
+		ats = append(ats, at)
+	}
+	for idx := 0; idx < 2000; idx++ {
+		// coarse times, so that many events share a time
+		schedule(start.Add(time.Duration(rng.Intn(2000)) * time.Millisecond))
+	}
+	for !blue.IsEmpty() {
+		target := blue.Now().Add(time.Duration(rng.Intn(5)) * time.Millisecond)
+		blue.AdvanceTo(target, 0)
+		for green.AdvanceTo(target, 0); green.IsCascading(); green.AdvanceTo(target, 0) {
+			// Only events after the target are touched, so that both
+			// wheels agree on what is pending.
+			later := target.Add(time.Duration(1+rng.Intn(100)) * time.Millisecond)
+			switch n := rng.Intn(len(ats)); rng.Intn(4) {
+			case 0:
+				schedule(later)
+			case 1:
+				if ats[n].After(target) && blueHandles[n].Cancel() != greenHandles[n].Cancel() {
+					t.Fatalf("Cancel disagreed for %v", n)
+				}
+			case 2:
+				if !ats[n].After(target) {
+					break
+				}
+				// a time of its own, as where an extended event goes
+				// amongst others for the same time depends on the
+				// bucket it was in
+				later = later.Add(time.Duration(n))
+				if (blue.ExtendDeadline(blueHandles[n], later) == nil) != (green.ExtendDeadline(greenHandles[n], later) == nil) {
+					t.Fatalf("ExtendDeadline disagreed for %v", n)
+				}
+				ats[n] = later
+			}
+		}
+		if blue.Length() != green.Length() {
+			t.Fatalf("Expected %v events, but got %v", blue.Length(), green.Length())
+		}
+	}
+	if !green.IsEmpty() || len(got) != len(expected) {
+		t.Fatalf("Expected %v events invoked, but got %v", len(expected), len(got))
+	}
+	for idx := range expected {
+		if got[idx] != expected[idx] {
+			t.Fatalf("Expected %v at %v, but got %v", expected[idx], idx, got[idx])
+		}
+	}
+}
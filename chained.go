@@ -0,0 +1,64 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// What a ChainedEvent asks for once it has been invoked. If At is set
+// then the event is invoked again at At; otherwise if In is positive
+// then it is invoked again In after the time it was invoked with. The
+// zero NextSchedule ends the chain. If Tag is non-empty, it replaces
+// the event's tag for the next invocation.
+type NextSchedule struct {
+	At  time.Time
+	In  time.Duration
+	Tag string
+}
+
+// Events that compute from their own outcome when, if ever, they
+// should next be invoked. When invoked, a ChainedEvent is given the
+// same time as an Event is.
+type ChainedEvent func(now time.Time) NextSchedule
+
+// Schedules a ChainedEvent to be invoked at the indicated time. The
+// NextSchedule it returns is applied by the Timer Wheel as soon as it
+// returns, before any other event is invoked. If the next time it
+// asks for is not strictly after the time it was invoked with then
+// it is not rescheduled: otherwise it would be invoked again, without
+// end, by the same call to AdvanceTo. Returns ScheduledInPast if at
+// is in the past.
+func (tw *TimerWheel) ScheduleChainedEventAt(at time.Time, e ChainedEvent) error {
+	return tw.scheduleChainedEvent(at, "", e)
+}
+
+// Schedules a ChainedEvent to be invoked at the current Timer Wheel's
+// time plus the supplied duration. See ScheduleChainedEventAt.
+func (tw *TimerWheel) ScheduleChainedEventIn(in time.Duration, e ChainedEvent) error {
+	return tw.ScheduleChainedEventAt(tw.after(in), e)
+}
+
+func (tw *TimerWheel) scheduleChainedEvent(at time.Time, tag string, e ChainedEvent) error {
+	event := tw.newEvent(eventNode{at: at.UnixNano(), tag: tag, chained: e})
+	if e != nil {
+		// fun is only used to present the event through ForEach.
+		event.fun = func(now *time.Time) { e(*now) }
+	}
+	return tw.scheduleEvent(event)
+}
+
+// Invokes a chained event, applying its NextSchedule to this Timer
+// Wheel (see recur).
+func (tw *TimerWheel) chain(event *eventNode, now *time.Time) {
+	next := event.chained(*now)
+	tag := event.tag
+	if next.Tag != "" {
+		tag = next.Tag
+	}
+	if !next.At.IsZero() {
+		if next.At.After(*now) {
+			tw.scheduleChainedEvent(next.At, tag, event.chained)
+		}
+	} else if next.In > 0 {
+		tw.scheduleChainedEvent(now.Add(next.In), tag, event.chained)
+	}
+}
@@ -0,0 +1,66 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChainedEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	// retry quickly until the third attempt, then poll slowly, then
+	// stop at an absolute time
+	attempts := 0
+	tw.ScheduleChainedEventIn(1, func(now time.Time) NextSchedule {
+		attempts++
+		switch {
+		case attempts < 3:
+			return NextSchedule{In: 1, Tag: "retry"}
+		case attempts < 5:
+			return NextSchedule{In: 100, Tag: "poll"}
+		case attempts == 5:
+			return NextSchedule{At: time.Unix(0, 1000)}
+		}
+		return NextSchedule{}
+	})
+	tags := []string{}
+	for !tw.IsEmpty() {
+		tw.forEachEvent(func(event *eventNode) bool {
+			tags = append(tags, event.tag)
+			return true
+		})
+		tw.AdvanceToNextEvent()
+	}
+	assertNowLength(t, tw, time.Unix(0, 1000), 0)
+	if attempts != 6 {
+		t.Errorf("Expected 6 attempts, but got %v", attempts)
+	}
+	// the tag carries over to the absolute reschedule
+	expected := []string{"", "retry", "retry", "poll", "poll", "poll"}
+	if len(tags) != len(expected) {
+		t.Fatalf("Expected tags %v, but got %v", expected, tags)
+	}
+	for idx, tag := range expected {
+		if tags[idx] != tag {
+			t.Fatalf("Expected tags %v, but got %v", expected, tags)
+		}
+	}
+}
+
+func TestChainedEventSameTime(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	invoked := 0
+	tw.ScheduleChainedEventIn(5, func(now time.Time) NextSchedule {
+		invoked++
+		if invoked < 1000 {
+			return NextSchedule{At: now}
+		}
+		return NextSchedule{}
+	})
+	tw.AdvanceBy(10, 0)
+	if invoked != 1 {
+		t.Errorf("Expected a chain asking for its own time to end, but got %v invocations", invoked)
+	}
+	assertNowLength(t, tw, time.Unix(0, 10), 0)
+}
@@ -0,0 +1,73 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Cancels every scheduled event. The Timer Wheel's current time is
+// unchanged.
+func (tw *TimerWheel) Clear() {
+	length := tw.Length()
+	if tw.tracingOn() && length > 0 {
+		tw.logger.Printf("gotimerwheel: cancel n=%d reason=clear", length)
+	}
+	tw.stats.cancelled += uint64(length)
+	var retired []*eventNode
+	if tw.arena.size > 0 {
+		tw.forEachEvent(func(event *eventNode) bool {
+			retired = append(retired, event)
+			return true
+		})
+	}
+	tw.removeAll()
+	for key := range tw.keys {
+		delete(tw.keys, key)
+	}
+	tw.duplicates.clear()
+	for _, event := range tw.ids {
+		tw.settleID(event, EventCancelled)
+	}
+	for _, event := range retired {
+		tw.retire(event)
+	}
+	tw.arena.release()
+	tw.clears++
+	for _, ns := range tw.namespaces {
+		ns.members = make(map[*eventNode]uint64)
+	}
+}
+
+// Reinitialises the Timer Wheel, as if it had just been created by
+// NewTimerWheel with startAt and the same bucketSize and options:
+// every scheduled event is dropped and the statistics are reset. The
+// ring is reused rather than reallocated, which makes this cheaper
+// than creating a new Timer Wheel.
+func (tw *TimerWheel) Reset(startAt time.Time) {
+	tw.Clear()
+	tw.ringIdx = 0
+	tw.now = startAt.UnixNano()
+	tw.start = tw.now
+	tw.loc = startAt.Location()
+	if tw.alignment > 0 {
+		tw.start = alignTime(tw.start, tw.alignment)
+	}
+	tw.stats = counters{}
+	tw.lag.reset()
+	tw.skew.reset()
+	tw.settled.reset()
+	tw.adaptive.since, tw.adaptive.invoked = tw.now, 0
+	tw.panics.collected = nil
+	tw.waiters.reach(tw.now)
+}
+
+// Empties every bucket in the hierarchy. The position and time of the
+// Timer Wheel are unchanged.
+func (tw *TimerWheel) removeAll() {
+	sliced, tieBreak := tw.ring[0].sliced, tw.ring[0].tieBreak
+	for idx := range tw.ring {
+		tw.ring[idx] = bucket{sliced: sliced, tieBreak: tieBreak}
+	}
+	tw.next = nil
+	tw.cascade = pendingCascade{}
+	tw.overflow.events = nil
+}
@@ -0,0 +1,58 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClear(t *testing.T) {
+	run := createBasicRun(t)
+	run.ScheduleKeyedEventAt("key", time.Unix(0, 50), func(*time.Time) { t.Error("Cleared event invoked") })
+	run.AdvanceTo(time.Unix(0, 20), 0)
+	remaining := run.Length()
+	run.Clear()
+	assertNowLength(t, run.TimerWheel, time.Unix(0, 20), 0)
+	if run.CancelKey("key") {
+		t.Error("Expected keyed event to have been cleared")
+	}
+	if stats := run.Stats(); stats.Cancelled != uint64(remaining) {
+		t.Errorf("Expected %v cancelled, but found %v", remaining, stats.Cancelled)
+	}
+	if count := run.AdvanceTo(run.end, 0); count != 0 {
+		t.Errorf("Expected nothing invoked, but got %v", count)
+	}
+	// still usable
+	fired := false
+	run.ScheduleEventIn(10, func(*time.Time) { fired = true })
+	run.AdvanceBy(10, 0)
+	if !fired {
+		t.Error("Expected event to be invoked after Clear")
+	}
+}
+
+func TestReset(t *testing.T) {
+	run := createBasicRun(t)
+	run.AdvanceTo(time.Unix(0, 200), 0)
+	ring := run.ring
+	start := time.Unix(0, 23)
+	run.Reset(start)
+	assertNowLength(t, run.TimerWheel, start, 0)
+	if stats := run.Stats(); stats.Scheduled != 0 || stats.Fired != 0 || stats.Cascades != 0 {
+		t.Errorf("Expected stats to be reset: %+v", stats)
+	}
+	if &ring[0] != &run.ring[0] {
+		t.Error("Expected ring to be reused")
+	}
+	// behaves like a new wheel starting at start
+	if err := run.ScheduleEventAt(time.Unix(0, 22), func(*time.Time) {}); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	fired := 0
+	for _, ns := range []int64{23, 30, 500} {
+		run.ScheduleEventAt(time.Unix(0, ns), func(*time.Time) { fired++ })
+	}
+	if count := run.AdvanceTo(time.Unix(0, 500), 0); count != 3 || fired != 3 {
+		t.Errorf("Expected 3 events invoked, but got %v", count)
+	}
+}
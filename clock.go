@@ -0,0 +1,126 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A source of the current wall-clock time. The Now method has the
+// same signature as those of the clocks of packages such as
+// benbjohnson/clock and jonboulle/clockwork, so their mock clocks can
+// be used directly.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Sets the Clock which AdvanceToNow advances to, and which
+// AdvanceToWithin measures its budget against. By default the
+// system's clock is used.
+func WithClock(clock Clock) Option {
+	return func(tw *TimerWheel) {
+		tw.clock = clock
+	}
+}
+
+func (tw *TimerWheel) wallClock() Clock {
+	if tw.clock == nil {
+		return systemClock{}
+	}
+	return tw.clock
+}
+
+// What AdvanceToNow does when the Clock's wall-clock time jumps, for
+// example when NTP steps the clock or the machine resumes from
+// suspend. Jumps are detected by comparing the time passed on the
+// wall clock with that passed on the monotonic clock, so only
+// readings carrying monotonic time, such as those of time.Now, can
+// reveal a jump. See WithClockJumpPolicy.
+type ClockJumpPolicy int
+
+const (
+	// The Timer Wheel's time follows the wall clock. After a forward
+	// jump, every event skipped over is invoked straight away; after
+	// a backward jump, nothing is invoked until the wall clock catches
+	// up again. This is the default.
+	ClockJumpFollow ClockJumpPolicy = iota
+	// The Timer Wheel's time advances by the monotonic time passed,
+	// ignoring jumps, so events are invoked at the intended intervals
+	// but the Timer Wheel's time drifts from the wall clock by the
+	// sum of the jumps.
+	ClockJumpIgnore
+	// As ClockJumpIgnore, but the Timer Wheel is then shifted by the
+	// jump with Rebase, so its time matches the wall clock again while
+	// every scheduled event keeps the delay it had left. Events
+	// scheduled for particular wall-clock times are shifted too.
+	ClockJumpShift
+)
+
+type clockDriving struct {
+	policy  ClockJumpPolicy
+	started bool
+	// The most recent reading of the Clock, and the Timer Wheel time
+	// it corresponds to.
+	last   time.Time
+	target int64
+	// Set between Pause and Resume.
+	paused   bool
+	pausedAt time.Time
+}
+
+// Sets how AdvanceToNow handles jumps of the Clock's wall-clock time.
+// See ClockJumpPolicy.
+func WithClockJumpPolicy(policy ClockJumpPolicy) Option {
+	return func(tw *TimerWheel) {
+		tw.driving.policy = policy
+	}
+}
+
+// Advances the Timer Wheel's current time to the Clock's current time
+// (see WithClock), subject to the ClockJumpPolicy (see
+// WithClockJumpPolicy). Does nothing while the Timer Wheel is paused
+// (see Pause). How far the Timer Wheel had fallen behind the Clock is
+// reported in Stats (see WithSkewAlarm). See AdvanceTo for the
+// semantics of limit and the returned value.
+func (tw *TimerWheel) AdvanceToNow(limit int) int {
+	d := &tw.driving
+	if d.paused {
+		return 0
+	}
+	reading := tw.wallClock().Now()
+	if d.policy == ClockJumpFollow {
+		tw.measureSkew(reading.UnixNano())
+		return tw.AdvanceTo(reading, limit)
+	}
+	if !d.started {
+		d.started = true
+		d.last = reading
+		d.target = reading.UnixNano()
+		tw.measureSkew(d.target)
+		return tw.AdvanceTo(reading, limit)
+	}
+	// Sub uses the monotonic readings when both times have them.
+	elapsed := reading.Sub(d.last)
+	wallElapsed := reading.Round(0).Sub(d.last.Round(0))
+	d.last = reading
+	return tw.followClock(elapsed, wallElapsed-elapsed, limit)
+}
+
+// Advances by the monotonic time elapsed since the previous reading,
+// then applies the policy to the jump of the wall clock over the same
+// period.
+func (tw *TimerWheel) followClock(elapsed, jump time.Duration, limit int) int {
+	d := &tw.driving
+	if elapsed > 0 {
+		d.target += int64(elapsed)
+	}
+	tw.measureSkew(d.target)
+	count := tw.AdvanceTo(tw.toTime(d.target), limit)
+	if jump != 0 && d.policy == ClockJumpShift {
+		tw.Rebase(jump)
+		d.target += int64(jump)
+	}
+	return count
+}
@@ -0,0 +1,83 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+type manualClock struct{ now time.Time }
+
+func (mc *manualClock) Now() time.Time { return mc.now }
+
+func TestAdvanceToNow(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &manualClock{now: start}
+	tw := NewTimerWheel(start, time.Millisecond, WithClock(clock))
+	invoked := 0
+	tw.ScheduleEventIn(10*time.Millisecond, func(*time.Time) { invoked++ })
+	if tw.AdvanceToNow(0) != 0 {
+		t.Error("Expected nothing to be invoked")
+	}
+	clock.now = start.Add(10 * time.Millisecond)
+	if tw.AdvanceToNow(0) != 1 || invoked != 1 || !tw.Now().Equal(clock.now) {
+		t.Errorf("Expected the event to be invoked at %v, but got %v at %v", clock.now, invoked, tw.Now())
+	}
+}
+
+func TestAdvanceToWithinClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &manualClock{now: start}
+	tw := NewTimerWheel(start, time.Millisecond, WithClock(clock))
+	// each event takes 2ms of the clock's time
+	for idx := 0; idx < 10; idx++ {
+		tw.ScheduleEventAt(start, func(*time.Time) { clock.now = clock.now.Add(2 * time.Millisecond) })
+	}
+	if count := tw.AdvanceToWithin(start, 5*time.Millisecond); count != 3 {
+		t.Errorf("Expected 3 events invoked within the budget, but got %v", count)
+	}
+}
+
+func TestClockJumpPolicy(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, policy := range []ClockJumpPolicy{ClockJumpFollow, ClockJumpIgnore, ClockJumpShift} {
+		clock := &manualClock{now: start}
+		tw := NewTimerWheel(start, time.Millisecond, WithClock(clock), WithClockJumpPolicy(policy))
+		invoked := 0
+		for idx := 1; idx <= 10; idx++ {
+			tw.ScheduleEventIn(time.Duration(idx)*time.Second, func(*time.Time) { invoked++ })
+		}
+		tw.AdvanceToNow(0)
+		// The manual clock has no monotonic readings, so to fake a
+		// forward jump of an hour, advance the clock and tell the
+		// Timer Wheel that only 1s of monotonic time passed.
+		clock.now = start.Add(time.Hour + time.Second)
+		if policy == ClockJumpFollow {
+			tw.AdvanceToNow(0)
+		} else {
+			tw.driving.last = clock.now
+			tw.followClock(time.Second, time.Hour, 0)
+		}
+		switch policy {
+		case ClockJumpFollow:
+			if invoked != 10 || !tw.Now().Equal(clock.now) {
+				t.Errorf("Follow: expected every event invoked at %v, but got %v at %v", clock.now, invoked, tw.Now())
+			}
+		case ClockJumpIgnore:
+			if invoked != 1 || !tw.Now().Equal(start.Add(time.Second)) {
+				t.Errorf("Ignore: expected 1 event invoked at 1s, but got %v at %v", invoked, tw.Now())
+			}
+		case ClockJumpShift:
+			next, _ := tw.NextEventAt()
+			if invoked != 1 || !tw.Now().Equal(clock.now) || !next.Equal(clock.now.Add(time.Second)) {
+				t.Errorf("Shift: expected 1 event invoked at %v and the next 1s later, but got %v at %v and %v", clock.now, invoked, tw.Now(), next)
+			}
+		}
+		// without jumps, each policy follows the clock's readings
+		before := tw.Now()
+		clock.now = clock.now.Add(2 * time.Second)
+		tw.AdvanceToNow(0)
+		if !tw.Now().Equal(before.Add(2 * time.Second)) {
+			t.Errorf("Policy %v: expected to advance by 2s to %v, but got %v", policy, before.Add(2*time.Second), tw.Now())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package gotimerwheel
+
+// Returns an independent copy of the Timer Wheel: the same current
+// time, options, statistics and scheduled events (including keys and
+// tags). Scheduling, cancelling or advancing one has no effect on the
+// other, though the Event function values themselves are shared. This
+// makes it possible to branch a simulation at some point and explore
+// different futures. Recurring and chained events reschedule
+// themselves into whichever Timer Wheel invokes them. Events
+// belonging to helpers such as DeadlineTree and StagedTimeout refer
+// back to the helper, and so to the original Timer Wheel.
+func (tw *TimerWheel) Clone() *TimerWheel {
+	tw.completeCascades()
+	var keys map[string]*eventNode
+	if len(tw.keys) > 0 {
+		keys = make(map[string]*eventNode, len(tw.keys))
+	}
+	clone := tw.cloneLevel(nil, keys)
+	clone.keys = keys
+	clone.panics.collected = append([]*EventPanic(nil), tw.panics.collected...)
+	clone.errors = append([]*EventError(nil), tw.errors...)
+	clone.lag.counts = append([]uint64(nil), tw.lag.counts...)
+	clone.arena.block = nil
+	clone.registration = nil
+	clone.readView = nil
+	clone.waiters = newTimeWaiters(clone.now)
+	clone.intakes = nil
+	clone.namespaces = nil
+	if tw.pool != nil {
+		clone.pool = &workerPool{workers: tw.pool.workers}
+	}
+	return clone
+}
+
+func (tw *TimerWheel) cloneLevel(root *TimerWheel, keys map[string]*eventNode) *TimerWheel {
+	clone := new(TimerWheel)
+	*clone = *tw
+	if root == nil {
+		root = clone
+		if tw.duplicates.enabled {
+			clone.duplicates.events = make(map[duplicateKey]*eventNode, len(tw.duplicates.events))
+		}
+		if len(tw.ids) > 0 {
+			clone.ids = make(map[EventID]*eventNode, len(tw.ids))
+		}
+		clone.settled = tw.settled.clone()
+	}
+	clone.root = root
+	clone.ring = make([]bucket, len(tw.ring))
+	for idx, b := range tw.ring {
+		clone.ring[idx].count = b.count
+		clone.ring[idx].unsorted = b.unsorted
+		clone.ring[idx].tieBreak = b.tieBreak
+		if b.sliced {
+			clone.ring[idx].sliced = true
+			for _, entry := range b.live() {
+				clone.ring[idx].entries = append(clone.ring[idx].entries, sliceEntry{at: entry.at, event: tw.cloneEvent(entry.event, root, keys)})
+			}
+			continue
+		}
+		tail := &clone.ring[idx].eventNodeContainer
+		for event := b.eventNode; event != nil; event = event.next.eventNode {
+			copied := tw.cloneEvent(event, root, keys)
+			tail.eventNode = copied
+			tail = &copied.next
+			clone.ring[idx].tail = copied
+		}
+	}
+	if tw.root == tw {
+		clone.overflow.events = make(overflowHeap, len(tw.overflow.events))
+		for idx, entry := range tw.overflow.events {
+			clone.overflow.events[idx] = overflowEntry{event: tw.cloneEvent(entry.event, root, keys), seq: entry.seq}
+		}
+	}
+	if tw.next != nil {
+		clone.next = tw.next.cloneLevel(root, keys)
+	}
+	return clone
+}
+
+// Copies the event, pointing the clone's keys and duplicate tracking
+// at the copy where they pointed at the event.
+func (tw *TimerWheel) cloneEvent(event *eventNode, root *TimerWheel, keys map[string]*eventNode) *eventNode {
+	copied := *event
+	copied.next.eventNode = nil
+	copied.deferred = false
+	// Handles belong to the original's events.
+	copied.handle = nil
+	if event.keyed && tw.root.keys[event.key] == event {
+		keys[event.key] = &copied
+	}
+	if event.id != 0 && tw.root.ids[event.id] == event {
+		root.ids[event.id] = &copied
+	}
+	if tw.root.duplicates.enabled && event.tag != "" {
+		if key := keyForDuplicates(event); tw.root.duplicates.events[key] == event {
+			root.duplicates.events[key] = &copied
+		}
+	}
+	return &copied
+}
@@ -0,0 +1,86 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	run := createBasicRun(t)
+	run.AdvanceTo(time.Unix(0, 14), 0)
+	keyedFired := 0
+	run.ScheduleKeyedEventAt("key", time.Unix(0, 250), func(*time.Time) { keyedFired++ })
+	clone := run.Clone()
+	assertNowLength(t, clone, run.Now(), run.Length())
+	if clone.Stats().Fired != run.Stats().Fired {
+		t.Errorf("Expected stats to be copied")
+	}
+
+	// the clone's future diverges: cancel the key and add an event
+	if !clone.CancelKey("key") {
+		t.Error("Expected key to be cloned")
+	}
+	fired := 0
+	clone.ScheduleEventAt(time.Unix(0, 300), func(*time.Time) { fired++ })
+	assertNowLength(t, run.TimerWheel, time.Unix(0, 14), 11)
+	assertNowLength(t, clone, time.Unix(0, 14), 11)
+
+	// advancing the clone invokes the shared events, but leaves the
+	// original untouched
+	clone.Drain(0)
+	if fired != 1 || keyedFired != 0 {
+		t.Errorf("Expected only the clone's own events: %v %v", fired, keyedFired)
+	}
+	assertNowLength(t, run.TimerWheel, time.Unix(0, 14), 11)
+	if !run.CancelKey("key") {
+		t.Error("Expected original key to be unaffected")
+	}
+}
+
+func TestCloneOrder(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	for idx := 0; idx < 200; idx++ {
+		tw.ScheduleEventAt(time.Unix(0, int64(idx%7)*20), nil)
+	}
+	original, cloned := []time.Time{}, []time.Time{}
+	tw.ForEach(func(at time.Time, e Event) bool { original = append(original, at); return true })
+	tw.Clone().ForEach(func(at time.Time, e Event) bool { cloned = append(cloned, at); return true })
+	if len(original) != len(cloned) {
+		t.Fatalf("Expected %v events cloned, but got %v", len(original), len(cloned))
+	}
+	for idx := range original {
+		if !original[idx].Equal(cloned[idx]) {
+			t.Fatalf("Clone order differs at %v", idx)
+		}
+	}
+}
+
+func TestCloneRecurring(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	invocations := 0
+	tw.ScheduleRecurringEventIn(10, func(now time.Time) (time.Time, bool) {
+		invocations++
+		return now.Add(10), true
+	})
+	tw.ScheduleChainedEventIn(15, func(now time.Time) NextSchedule {
+		invocations++
+		return NextSchedule{In: 10}
+	})
+	clone := tw.Clone()
+	// both events reschedule themselves into the clone, and the
+	// original is untouched
+	for idx := 0; idx < 3; idx++ {
+		clone.AdvanceToNextEvent()
+	}
+	if invocations != 3 {
+		t.Errorf("Expected 3 invocations, but got %v", invocations)
+	}
+	assertNowLength(t, clone, time.Unix(0, 20), 2)
+	assertNowLength(t, tw, time.Unix(0, 0), 2)
+	if at, _ := clone.NextEventAt(); !at.Equal(time.Unix(0, 25)) {
+		t.Errorf("Expected the clone's next event at 25, but got %v", at)
+	}
+	if at, _ := tw.NextEventAt(); !at.Equal(time.Unix(0, 10)) {
+		t.Errorf("Expected the original's next event at 10, but got %v", at)
+	}
+}
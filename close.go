@@ -0,0 +1,51 @@
+package gotimerwheel
+
+import (
+	"errors"
+)
+
+var (
+	Closed = errors.New("The Timer Wheel has been closed")
+)
+
+// What Close does with the events still scheduled.
+type ClosePolicy int
+
+const (
+	// The events are cancelled without being invoked.
+	CloseDrop ClosePolicy = iota
+	// The events are invoked straight away, as by Drain.
+	CloseDrain
+)
+
+// Closes the Timer Wheel, after which every attempt to schedule an
+// event returns Closed, including those of recurring and chained
+// events rescheduling themselves and those of events being drained.
+// Events which are still scheduled are dealt with according to
+// policy. Any worker pool (see WithWorkerPool) is then stopped, once
+// the events it is running have finished. Returns the number of
+// events invoked. Closing a Timer Wheel which is already closed does
+// nothing.
+func (tw *TimerWheel) Close(policy ClosePolicy) int {
+	if tw.closed {
+		return 0
+	}
+	tw.closed = true
+	tw.drainIntakes()
+	count := 0
+	if policy == CloseDrain {
+		count = tw.Drain(0)
+	} else {
+		tw.Clear()
+		tw.PublishStats()
+	}
+	tw.arena.release()
+	tw.pool.stop()
+	tw.collectPoolPanics()
+	return count
+}
+
+// Returns true if the Timer Wheel has been closed.
+func (tw *TimerWheel) IsClosed() bool {
+	return tw.closed
+}
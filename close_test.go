@@ -0,0 +1,133 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCloseDrop(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { t.Error("Dropped event invoked") })
+	tw.ScheduleEventIn(time.Hour, func(*time.Time) { t.Error("Dropped event invoked") })
+	if count := tw.Close(CloseDrop); count != 0 || !tw.IsEmpty() || !tw.IsClosed() {
+		t.Errorf("Expected nothing invoked and nothing left, but got %v and %v", count, tw.Length())
+	}
+	if err := tw.ScheduleEventIn(time.Millisecond, nil); err != Closed {
+		t.Errorf("Expected Closed, but got %v", err)
+	}
+	if err := tw.ScheduleKeyedEventIn("key", time.Millisecond, nil); err != Closed {
+		t.Errorf("Expected Closed, but got %v", err)
+	}
+	if err := tw.ScheduleEvents([]ScheduledEvent{{At: start, Event: nil}}); err != Closed {
+		t.Errorf("Expected Closed, but got %v", err)
+	}
+	if _, err := tw.NewStagedTimeout(Stage{After: time.Second}); err != Closed {
+		t.Errorf("Expected Closed, but got %v", err)
+	}
+	if stats := tw.Stats(); stats.Cancelled != 2 {
+		t.Errorf("Expected 2 cancelled, but got %v", stats.Cancelled)
+	}
+	if tw.AdvanceBy(time.Hour, 0) != 0 || tw.Close(CloseDrain) != 0 {
+		t.Error("Expected nothing more to be invoked")
+	}
+}
+
+func TestCloseDrain(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	invoked := 0
+	for _, in := range []time.Duration{time.Millisecond, time.Hour} {
+		tw.ScheduleEventIn(in, func(*time.Time) {
+			invoked++
+			if err := tw.ScheduleEventIn(time.Millisecond, nil); err != Closed {
+				t.Errorf("Expected Closed, but got %v", err)
+			}
+		})
+	}
+	tw.ScheduleRecurringEventIn(time.Millisecond, func(now time.Time) (time.Time, bool) {
+		invoked++
+		return now.Add(time.Millisecond), true
+	})
+	if count := tw.Close(CloseDrain); count != 3 || invoked != 3 || !tw.IsEmpty() {
+		t.Errorf("Expected 3 events invoked and none left, but got %v and %v", count, tw.Length())
+	}
+}
+
+func TestCloseHandles(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	timer := tw.AfterFunc(time.Second, func() { t.Error("Dropped Timer fired") })
+	tree := NewDeadlineTree(tw)
+	deadline, _ := tree.NewDeadline(start.Add(time.Second), nil)
+	other, _ := tree.NewDeadline(start.Add(time.Second), nil)
+	st, _ := tw.NewStagedTimeout(Stage{After: time.Second}, Stage{After: time.Minute})
+	h, _ := tw.ScheduleHandleIn(time.Second, func(*time.Time) { t.Error("Dropped event invoked") })
+	tw.Close(CloseDrop)
+	// Every call is a no-op however often it is made.
+	for idx := 0; idx < 2; idx++ {
+		if h.State() != EventCancelled || h.Cancel() {
+			t.Errorf("Expected the EventHandle's event to be cancelled, but got %v", h.State())
+		}
+	}
+	if timer.Stop() || timer.Stop() {
+		t.Error("Expected Stop to find the Timer already stopped")
+	}
+	if !deadline.Done() || deadline.Cancel() {
+		t.Error("Expected the Deadline to be done")
+	}
+	if err := other.Reset(start.Add(time.Minute)); err != Closed || !other.Done() {
+		t.Errorf("Expected Closed from Reset, but got %v", err)
+	}
+	if _, err := other.NewChild(start.Add(time.Minute), nil); err != Closed {
+		t.Errorf("Expected Closed from NewChild, but got %v", err)
+	}
+	if st.Active() || st.Cancel() {
+		t.Error("Expected the StagedTimeout to be inactive")
+	}
+	if err := st.Reset(); err != Closed || st.Active() {
+		t.Errorf("Expected Closed from Reset, but got %v", err)
+	}
+}
+
+func TestClearHandles(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	fired := 0
+	timer := tw.AfterFunc(time.Second, func() { fired++ })
+	deadline, _ := NewDeadlineTree(tw).NewDeadline(start.Add(time.Second), nil)
+	st, _ := tw.NewStagedTimeout(Stage{After: time.Second})
+	tw.Clear()
+	if timer.Stop() || deadline.Cancel() || !deadline.Done() || st.Active() || st.Cancel() {
+		t.Error("Expected every handle to find its event gone")
+	}
+	if err := deadline.Reset(start.Add(time.Minute)); err != ScheduledInPast {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	// Handles are reusable once rearmed.
+	if timer.Reset(time.Second) || st.Reset() != nil || !st.Active() {
+		t.Error("Expected the Timer and StagedTimeout to be rearmed")
+	}
+	if tw.AdvanceBy(time.Second, 0) != 2 || fired != 1 || st.Active() {
+		t.Errorf("Expected both to fire, but got %v", fired)
+	}
+}
+
+func TestCloseDrainHandles(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	fired := 0
+	timer := tw.AfterFunc(time.Second, func() { fired++ })
+	deadline, _ := NewDeadlineTree(tw).NewDeadline(start.Add(time.Second), func(*time.Time) { fired++ })
+	st, _ := tw.NewStagedTimeout(Stage{After: time.Second, Fire: func(*time.Time) bool { fired++; return false }})
+	h, _ := tw.ScheduleHandleIn(time.Second, func(*time.Time) { fired++ })
+	if count := tw.Close(CloseDrain); count != 4 || fired != 4 {
+		t.Fatalf("Expected every event to be drained, but got %v", count)
+	}
+	if timer.Stop() || !deadline.Done() || deadline.Cancel() || st.Active() || st.Cancel() || h.State() != EventFired || h.Cancel() {
+		t.Error("Expected every handle to report its event fired")
+	}
+	if timer.Reset(time.Second) || timer.Err() != Closed || deadline.Reset(start.Add(time.Minute)) != Closed || st.Reset() != Closed {
+		t.Error("Expected resetting to be refused with Closed")
+	}
+}
@@ -0,0 +1,26 @@
+// Command wheelbench runs a workload against both a TimerWheel and
+// the standard library's timers and reports throughput, allocations
+// and lateness for each, so the two can be compared on your own
+// machine and workload.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/msackman/gotimerwheel/wheelbench"
+)
+
+func main() {
+	var w wheelbench.Workload
+	flag.IntVar(&w.Timers, "timers", 100000, "number of timers to create")
+	flag.DurationVar(&w.MaxDelay, "max-delay", time.Second, "timer delays are uniformly distributed up to this")
+	flag.DurationVar(&w.BucketSize, "bucket", time.Millisecond, "bucket size of the timer wheel")
+	flag.DurationVar(&w.Tick, "tick", time.Millisecond, "how often the timer wheel is advanced")
+	flag.Int64Var(&w.Seed, "seed", 1, "random seed for the timer delays")
+	flag.Parse()
+
+	fmt.Println(wheelbench.RunWheel(w))
+	fmt.Println(wheelbench.RunStdlib(w))
+}
@@ -0,0 +1,128 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Which time a Coalescer gives the single event that events merged
+// together are invoked as.
+type CoalescePolicy int
+
+const (
+	// The merged event is invoked at the earliest of the times.
+	CoalesceEarliest CoalescePolicy = iota
+	// The merged event is invoked at the latest of the times.
+	CoalesceLatest
+)
+
+// A Coalescer merges events scheduled for the same key at nearby
+// times, so that only one of them is invoked: for example, many
+// requests to flush the same cache entry become a single flush. The
+// events for a key form a group starting with the first of them, and
+// any later event for a time within the window of the group's first
+// event joins the group rather than being scheduled separately. Once
+// the group's event has been invoked, the next event for the key
+// starts a new group, as does an event outside the window, in which
+// case the existing group is left alone to be invoked. Like a
+// DeadlineTree, a Coalescer loses track of its events if they are
+// removed from the Timer Wheel by other means, such as Clear.
+type Coalescer struct {
+	tw     *TimerWheel
+	window int64
+	policy CoalescePolicy
+	groups map[string]*coalesced
+}
+
+type coalesced struct {
+	// The time of the group's first event.
+	anchor int64
+	event  *eventNode
+	fun    Event
+}
+
+// Creates a new Coalescer backed by the supplied Timer Wheel. Window
+// must not be negative.
+func NewCoalescer(tw *TimerWheel, window time.Duration, policy CoalescePolicy) *Coalescer {
+	if window < 0 {
+		panic("Coalescer window must not be negative")
+	}
+	return &Coalescer{tw: tw, window: int64(window), policy: policy, groups: make(map[string]*coalesced)}
+}
+
+// Schedules an event for key to be invoked at the indicated time, or
+// merges it with the key's group if at is within the window of the
+// group's first event. A merged group is invoked, at the time chosen
+// by the Coalescer's policy, by calling the most recently supplied
+// event. Returns true if the event was merged into an existing group.
+// Errors are as for TimerWheel.ScheduleEventAt; a merge which would
+// move the group's event to a time the Timer Wheel refuses leaves the
+// group unchanged.
+func (c *Coalescer) ScheduleEventAt(key string, at time.Time, e Event) (bool, error) {
+	if e == nil && !c.tw.nilEvents {
+		return false, NilEvent
+	}
+	ns := at.UnixNano()
+	if g, found := c.groups[key]; found {
+		if ns-g.anchor <= c.window && g.anchor-ns <= c.window {
+			return true, c.merge(key, g, ns, e)
+		}
+		delete(c.groups, key)
+	}
+	g := &coalesced{anchor: ns, fun: e}
+	// Recorded first, as with PastInvoke the event may be invoked,
+	// and so forgotten, by scheduleEvent.
+	c.groups[key] = g
+	if err := c.schedule(key, g, ns); err != nil {
+		if c.groups[key] == g {
+			delete(c.groups, key)
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// Schedules an event for key to be invoked at the Timer Wheel's
+// current time plus the supplied duration. See ScheduleEventAt.
+func (c *Coalescer) ScheduleEventIn(key string, in time.Duration, e Event) (bool, error) {
+	return c.ScheduleEventAt(key, c.tw.after(in), e)
+}
+
+func (c *Coalescer) merge(key string, g *coalesced, at int64, e Event) error {
+	current := g.event.at
+	if (c.policy == CoalesceEarliest && at < current) || (c.policy == CoalesceLatest && at > current) {
+		old := g.event
+		if err := c.schedule(key, g, at); err != nil {
+			g.event = old
+			return err
+		}
+		c.tw.cancelEvent(old)
+	}
+	g.fun = e
+	return nil
+}
+
+func (c *Coalescer) schedule(key string, g *coalesced, at int64) error {
+	event := c.tw.newEvent(eventNode{at: at})
+	event.fun = func(now *time.Time) {
+		if c.groups[key] == g {
+			delete(c.groups, key)
+		}
+		if g.fun != nil {
+			g.fun(now)
+		}
+	}
+	g.event = event
+	return c.tw.scheduleEvent(event)
+}
+
+// Cancels the group of events for key, so that it will never be
+// invoked. Returns true if there was such a group waiting to be
+// invoked.
+func (c *Coalescer) Cancel(key string) bool {
+	g, found := c.groups[key]
+	if !found {
+		return false
+	}
+	delete(c.groups, key)
+	return c.tw.cancelEvent(g.event)
+}
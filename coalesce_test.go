@@ -0,0 +1,65 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoalescer(t *testing.T) {
+	for _, policy := range []CoalescePolicy{CoalesceEarliest, CoalesceLatest} {
+		start := time.Unix(0, 0)
+		tw := NewTimerWheel(start, time.Millisecond)
+		c := NewCoalescer(tw, 10*time.Millisecond, policy)
+		var invoked []string
+		var invokedAt []time.Time
+		flush := func(name string) Event {
+			return func(*time.Time) {
+				invoked = append(invoked, name)
+				invokedAt = append(invokedAt, tw.Now())
+			}
+		}
+		expectMerged := func(merged bool, err error, expected bool) {
+			t.Helper()
+			if err != nil || merged != expected {
+				t.Errorf("Expected merged %v, but got %v, %v", expected, merged, err)
+			}
+		}
+		merged, err := c.ScheduleEventIn("a", 5*time.Millisecond, flush("a1"))
+		expectMerged(merged, err, false)
+		merged, err = c.ScheduleEventIn("a", 12*time.Millisecond, flush("a2"))
+		expectMerged(merged, err, true)
+		merged, err = c.ScheduleEventIn("a", 2*time.Millisecond, flush("a3"))
+		expectMerged(merged, err, true)
+		// beyond the window of the group's first event, at 5ms
+		merged, err = c.ScheduleEventIn("a", 16*time.Millisecond, flush("a4"))
+		expectMerged(merged, err, false)
+		merged, err = c.ScheduleEventIn("b", 12*time.Millisecond, flush("b1"))
+		expectMerged(merged, err, false)
+		if tw.Length() != 3 {
+			t.Errorf("Expected 3 events to be scheduled, but got %v", tw.Length())
+		}
+
+		for !tw.IsEmpty() {
+			tw.AdvanceToNextEvent()
+		}
+		var expected []string
+		var expectedAt []time.Duration
+		if policy == CoalesceEarliest {
+			expected, expectedAt = []string{"a3", "b1", "a4"}, []time.Duration{2, 12, 16}
+		} else {
+			expected, expectedAt = []string{"a3", "b1", "a4"}, []time.Duration{12, 12, 16}
+		}
+		for idx := range expected {
+			if idx >= len(invoked) || invoked[idx] != expected[idx] || !invokedAt[idx].Equal(start.Add(expectedAt[idx]*time.Millisecond)) {
+				t.Fatalf("Policy %v: expected %v at %v ms, but got %v at %v", policy, expected, expectedAt, invoked, invokedAt)
+			}
+		}
+
+		// once invoked, the next event for a key starts a new group
+		merged, err = c.ScheduleEventIn("b", time.Millisecond, flush("b2"))
+		expectMerged(merged, err, false)
+		if !c.Cancel("b") || c.Cancel("b") || !tw.IsEmpty() {
+			t.Error("Expected Cancel to cancel the group once")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Passed to a ContextEvent when it is invoked.
+type EventContext struct {
+	// The time the event was scheduled for.
+	ScheduledAt time.Time
+	// The time the Timer Wheel was advanced to when the event was
+	// invoked. This is never before ScheduledAt.
+	FiredAt time.Time
+	// The metadata supplied when the event was scheduled. The map is
+	// not copied.
+	Metadata map[string]string
+}
+
+// Returns how late the event was invoked: how far FiredAt is after
+// ScheduledAt.
+func (ec *EventContext) Lateness() time.Duration {
+	return ec.FiredAt.Sub(ec.ScheduledAt)
+}
+
+// An event which is told when it was scheduled for and when it was
+// invoked, along with the metadata it was scheduled with.
+type ContextEvent func(*EventContext)
+
+// Schedules an event to be invoked at the indicated time, with
+// metadata which is passed back to the event in its EventContext.
+// Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) ScheduleContextEventAt(at time.Time, metadata map[string]string, e ContextEvent) error {
+	if e == nil {
+		return tw.ScheduleEventAt(at, nil)
+	}
+	return tw.ScheduleEventAt(at, func(now *time.Time) {
+		e(&EventContext{ScheduledAt: at, FiredAt: *now, Metadata: metadata})
+	})
+}
+
+// Schedules an event to be invoked at the current Timer Wheel's time
+// plus the supplied duration. See ScheduleContextEventAt.
+func (tw *TimerWheel) ScheduleContextEventIn(in time.Duration, metadata map[string]string, e ContextEvent) error {
+	return tw.ScheduleContextEventAt(tw.after(in), metadata, e)
+}
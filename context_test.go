@@ -0,0 +1,30 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContextEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	var contexts []*EventContext
+	record := func(ec *EventContext) { contexts = append(contexts, ec) }
+	tw.ScheduleContextEventIn(3*time.Millisecond, map[string]string{"name": "a"}, record)
+	tw.ScheduleContextEventIn(7*time.Millisecond, nil, record)
+	tw.AdvanceBy(10*time.Millisecond, 0)
+
+	if len(contexts) != 2 {
+		t.Fatalf("Expected 2 events to be invoked, but got %v", len(contexts))
+	}
+	first, second := contexts[0], contexts[1]
+	if !first.ScheduledAt.Equal(start.Add(3*time.Millisecond)) || !first.FiredAt.Equal(start.Add(10*time.Millisecond)) {
+		t.Errorf("Unexpected times %v and %v", first.ScheduledAt, first.FiredAt)
+	}
+	if first.Lateness() != 7*time.Millisecond || second.Lateness() != 3*time.Millisecond {
+		t.Errorf("Unexpected lateness %v and %v", first.Lateness(), second.Lateness())
+	}
+	if first.Metadata["name"] != "a" || second.Metadata != nil {
+		t.Errorf("Unexpected metadata %v and %v", first.Metadata, second.Metadata)
+	}
+}
@@ -0,0 +1,163 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A parsed cron expression. See ParseCron.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Set if the day of the month or week is restricted, in which
+	// case, as in cron, a day matches if either field does.
+	domRestricted, dowRestricted bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parses a standard five-field cron expression: minute (0-59), hour
+// (0-23), day of the month (1-31), month (1-12) and day of the week
+// (0-7, where both 0 and 7 are Sunday). Each field is * or a comma
+// separated list of values and ranges (such as 1-5), any of which may
+// be followed by a step (such as */15 or 0-30/10). The macros
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are also accepted. Names of months and days are not.
+func ParseCron(spec string) (*CronSchedule, error) {
+	expanded := spec
+	if macro, found := cronMacros[strings.TrimSpace(spec)]; found {
+		expanded = macro
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Cron spec %q must have 5 fields", spec)
+	}
+	cs := &CronSchedule{}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&cs.minute, 0, 59},
+		{&cs.hour, 0, 23},
+		{&cs.dom, 1, 31},
+		{&cs.month, 1, 12},
+		{&cs.dow, 0, 7},
+	}
+	for idx, b := range bounds {
+		bits, err := parseCronField(fields[idx], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("Cron spec %q: %v", spec, err)
+		}
+		*b.field = bits
+	}
+	if cs.dow&(1<<7) != 0 {
+		cs.dow |= 1
+	}
+	cs.domRestricted = fields[2] != "*"
+	cs.dowRestricted = fields[4] != "*"
+	return cs, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, step := part, 1
+		if slash := strings.IndexByte(part, '/'); slash >= 0 {
+			var err error
+			rangeSpec = part[:slash]
+			if step, err = strconv.Atoi(part[slash+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("Invalid step in %q", part)
+			}
+		}
+		low, high := min, max
+		if rangeSpec != "*" {
+			var err error
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("Invalid value in %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("Invalid range in %q", part)
+				}
+			} else if step > 1 {
+				// As in cron, a single value with a step runs to the
+				// end of the field's range.
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of the range %v-%v", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (cs *CronSchedule) dayMatches(t time.Time) bool {
+	dom := cs.dom&(1<<uint(t.Day())) != 0
+	dow := cs.dow&(1<<uint(t.Weekday())) != 0
+	if cs.domRestricted && cs.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Returns the first time strictly after t which matches the
+// schedule, in t's location. If nothing matches within the next five
+// years, as for 30 February, then false is returned.
+func (cs *CronSchedule) Next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case cs.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !cs.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case cs.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+		case cs.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Schedules e to be invoked at every time matching the cron
+// expression spec (see ParseCron), in the location of the Timer
+// Wheel's start time, from the Timer Wheel's current time onwards.
+// Each time e is invoked, it is rescheduled for the next matching
+// time after the time it was invoked with, so occurrences missed by
+// a large advance are skipped rather than invoked late, as in cron.
+// Returns an error if spec cannot be parsed or never matches, or any
+// error from scheduling the first occurrence.
+func (tw *TimerWheel) ScheduleCron(spec string, e Event) error {
+	cs, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	first, found := cs.Next(tw.Now())
+	if !found {
+		return fmt.Errorf("Cron spec %q never matches", spec)
+	}
+	return tw.ScheduleRecurringEventAt(first, func(now time.Time) (time.Time, bool) {
+		e(&now)
+		return cs.Next(now.In(tw.root.loc))
+	})
+}
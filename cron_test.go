@@ -0,0 +1,66 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("Expected %q to be refused", spec)
+		}
+	}
+	from := time.Date(2024, time.January, 31, 10, 7, 30, 0, time.UTC)
+	for spec, expected := range map[string]time.Time{
+		"* * * * *":        time.Date(2024, time.January, 31, 10, 8, 0, 0, time.UTC),
+		"*/15 * * * *":     time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC),
+		"5 9-17/4 * * *":   time.Date(2024, time.January, 31, 13, 5, 0, 0, time.UTC),
+		"0 0 29 2 *":       time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"30 6 * * 7":       time.Date(2024, time.February, 4, 6, 30, 0, 0, time.UTC),
+		"0 0 15 * 1":       time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC),
+		"@monthly":         time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0,45 10 31 1,3 *": time.Date(2024, time.January, 31, 10, 45, 0, 0, time.UTC),
+	} {
+		cs, err := ParseCron(spec)
+		if err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+		if next, found := cs.Next(from); !found || !next.Equal(expected) {
+			t.Errorf("%q: expected %v, but got %v", spec, expected, next)
+		}
+	}
+	cs, _ := ParseCron("0 0 30 2 *")
+	if _, found := cs.Next(from); found {
+		t.Error("Expected 30 February never to match")
+	}
+}
+
+func TestScheduleCron(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tw := NewTimerWheel(start, time.Second)
+	var invokedAt []time.Time
+	if err := tw.ScheduleCron("0 */6 * * *", func(now *time.Time) { invokedAt = append(invokedAt, *now) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.ScheduleCron("0 0 31 4 *", nil); err == nil {
+		t.Error("Expected a spec which never matches to be refused")
+	}
+	for idx := 0; idx < 3; idx++ {
+		tw.AdvanceToNextEvent()
+	}
+	// missed occurrences are skipped
+	tw.AdvanceTo(start.Add(40*time.Hour), 0)
+	expected := []time.Duration{6, 12, 18, 40}
+	if len(invokedAt) != len(expected) {
+		t.Fatalf("Expected invocations at %v hours, but got %v", expected, invokedAt)
+	}
+	for idx, hours := range expected {
+		if !invokedAt[idx].Equal(start.Add(hours * time.Hour)) {
+			t.Errorf("Expected invocations at %v hours, but got %v", expected, invokedAt)
+		}
+	}
+	if next, _ := tw.NextEventAt(); !next.Equal(start.Add(42 * time.Hour)) {
+		t.Errorf("Expected the next occurrence at %v, but got %v", start.Add(42*time.Hour), next)
+	}
+}
@@ -0,0 +1,163 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A DeadlineTree manages hierarchical deadlines, such as the
+// timeouts of nested RPC calls, in a single Timer Wheel. The
+// effective deadline of a child is always the earlier of its own
+// deadline and its parent's effective deadline. Changing a parent's
+// deadline changes its children's effective deadlines, and
+// cancelling a parent cancels all its descendants. Deadlines belong
+// to the goroutine driving the Timer Wheel (see Intake.Do).
+type DeadlineTree struct {
+	tw *TimerWheel
+}
+
+// A single deadline within a DeadlineTree.
+type Deadline struct {
+	tree      *DeadlineTree
+	parent    *Deadline
+	children  map[*Deadline]struct{}
+	own       time.Time
+	effective time.Time
+	fun       Event
+	event     *eventNode
+	done      bool
+	// The Timer Wheel's count of Clears when the event was scheduled.
+	clears uint64
+}
+
+// Create a new DeadlineTree backed by the supplied Timer Wheel.
+func NewDeadlineTree(tw *TimerWheel) *DeadlineTree {
+	return &DeadlineTree{tw: tw}
+}
+
+// Creates a new top-level deadline: e will be invoked at the
+// indicated time unless the deadline is cancelled first. E may be
+// nil. Returns ScheduledInPast if at is in the past of the Timer
+// Wheel's current time.
+func (dt *DeadlineTree) NewDeadline(at time.Time, e Event) (*Deadline, error) {
+	return dt.newDeadline(nil, at, e)
+}
+
+// Creates a new deadline which is a child of d. Its effective
+// deadline is the earlier of at and d's effective deadline. Returns
+// Closed if the Timer Wheel has been closed, and ScheduledInPast if
+// its effective deadline is in the past of the Timer Wheel's current
+// time, or if d has already expired or been cancelled.
+func (d *Deadline) NewChild(at time.Time, e Event) (*Deadline, error) {
+	if d.tree.tw.closed {
+		return nil, Closed
+	}
+	if d.Done() {
+		return nil, ScheduledInPast
+	}
+	return d.tree.newDeadline(d, at, e)
+}
+
+func (dt *DeadlineTree) newDeadline(parent *Deadline, at time.Time, e Event) (*Deadline, error) {
+	d := &Deadline{tree: dt, parent: parent, own: at, fun: e}
+	if err := d.schedule(); err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		if parent.children == nil {
+			parent.children = make(map[*Deadline]struct{})
+		}
+		parent.children[d] = struct{}{}
+	}
+	return d, nil
+}
+
+// Returns the effective deadline: the earlier of this deadline's own
+// time and its parent's effective deadline.
+func (d *Deadline) Deadline() time.Time {
+	return d.effective
+}
+
+// Returns true once the deadline has either expired or been
+// cancelled, including by Clear, Reset or Close of the Timer Wheel.
+func (d *Deadline) Done() bool {
+	return d.done || d.clears != d.tree.tw.clears
+}
+
+// Changes the deadline's own time, and recalculates the effective
+// deadlines of it and all its descendants. Returns Closed if the
+// Timer Wheel has been closed. Otherwise returns ScheduledInPast,
+// changing nothing, if the new effective deadline is in the past of
+// the Timer Wheel's current time or if the deadline is already done.
+func (d *Deadline) Reset(at time.Time) error {
+	if d.tree.tw.closed {
+		return Closed
+	}
+	if d.Done() {
+		return ScheduledInPast
+	}
+	effective := d.effectiveFor(at)
+	if effective.UnixNano() < d.tree.tw.now {
+		return d.tree.tw.pastError(effective.UnixNano())
+	}
+	d.own = at
+	// Descendants' effective deadlines are never earlier than ours,
+	// so none of them can end up in the past either.
+	d.reschedule()
+	return nil
+}
+
+// Cancels the deadline and all its descendants, so none of their
+// events will be invoked. Returns false if the deadline was already
+// done.
+func (d *Deadline) Cancel() bool {
+	if d.Done() {
+		return false
+	}
+	d.cancel()
+	if d.parent != nil {
+		delete(d.parent.children, d)
+	}
+	return true
+}
+
+func (d *Deadline) cancel() {
+	d.done = true
+	d.tree.tw.cancelEvent(d.event)
+	for child := range d.children {
+		child.cancel()
+	}
+	d.children = nil
+}
+
+func (d *Deadline) effectiveFor(at time.Time) time.Time {
+	if d.parent != nil && d.parent.effective.Before(at) {
+		return d.parent.effective
+	}
+	return at
+}
+
+func (d *Deadline) schedule() error {
+	d.effective = d.effectiveFor(d.own)
+	effective := d.effective
+	d.event = d.tree.tw.newEvent(eventNode{at: effective.UnixNano(), fun: d.expire})
+	d.clears = d.tree.tw.clears
+	return d.tree.tw.scheduleEvent(d.event)
+}
+
+func (d *Deadline) reschedule() {
+	d.tree.tw.cancelEvent(d.event)
+	d.schedule()
+	for child := range d.children {
+		child.reschedule()
+	}
+}
+
+func (d *Deadline) expire(now *time.Time) {
+	d.done = true
+	if d.parent != nil {
+		delete(d.parent.children, d)
+	}
+	if d.fun != nil {
+		d.fun(now)
+	}
+}
@@ -0,0 +1,112 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadlineTree(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	dt := NewDeadlineTree(tw)
+	fired := []string{}
+	record := func(name string) Event {
+		return func(*time.Time) { fired = append(fired, name) }
+	}
+	parent, _ := dt.NewDeadline(time.Unix(0, 100), record("parent"))
+	// a child can't outlive its parent
+	late, _ := parent.NewChild(time.Unix(0, 200), record("late"))
+	early, _ := parent.NewChild(time.Unix(0, 50), record("early"))
+	grandchild, _ := late.NewChild(time.Unix(0, 300), record("grandchild"))
+	if !late.Deadline().Equal(time.Unix(0, 100)) || !grandchild.Deadline().Equal(time.Unix(0, 100)) {
+		t.Errorf("Expected children limited by parent: %v %v", late.Deadline(), grandchild.Deadline())
+	}
+	if !early.Deadline().Equal(time.Unix(0, 50)) {
+		t.Errorf("Expected child's own earlier deadline: %v", early.Deadline())
+	}
+	assertNowLength(t, tw, start, 4)
+
+	// bringing the parent forward cascades to its descendants
+	if err := parent.Reset(time.Unix(0, 40)); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []*Deadline{parent, late, early, grandchild} {
+		if !d.Deadline().Equal(time.Unix(0, 40)) {
+			t.Errorf("Expected deadline of 40, but found %v", d.Deadline())
+		}
+	}
+	// and pushing it back restores the children's own deadlines
+	if err := parent.Reset(time.Unix(0, 150)); err != nil {
+		t.Fatal(err)
+	}
+	if !early.Deadline().Equal(time.Unix(0, 50)) || !grandchild.Deadline().Equal(time.Unix(0, 150)) {
+		t.Errorf("Expected children's deadlines restored: %v %v", early.Deadline(), grandchild.Deadline())
+	}
+	assertNowLength(t, tw, start, 4)
+
+	tw.AdvanceTo(time.Unix(0, 60), 0)
+	if len(fired) != 1 || fired[0] != "early" || !early.Done() {
+		t.Errorf("Expected early to have expired, but found %v", fired)
+	}
+	if _, err := early.NewChild(time.Unix(0, 70), nil); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast from an expired deadline, but got %v", err)
+	}
+
+	// cancelling a child cancels its descendants, but not its parent
+	if !late.Cancel() || !grandchild.Done() || parent.Done() {
+		t.Error("Expected late and grandchild to be cancelled")
+	}
+	if late.Cancel() || grandchild.Cancel() {
+		t.Error("Expected cancelling twice to do nothing")
+	}
+	assertNowLength(t, tw, time.Unix(0, 60), 1)
+	tw.AdvanceTo(time.Unix(0, 1000), 0)
+	if len(fired) != 2 || fired[1] != "parent" {
+		t.Errorf("Expected parent to have expired, but found %v", fired)
+	}
+	if parent.Cancel() {
+		t.Error("Expected cancelling an expired deadline to do nothing")
+	}
+}
+
+func TestDeadlineTreeInPast(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 10), 1)
+	dt := NewDeadlineTree(tw)
+	if _, err := dt.NewDeadline(time.Unix(0, 5), nil); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	d, _ := dt.NewDeadline(time.Unix(0, 20), nil)
+	if err := d.Reset(time.Unix(0, 5)); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	if !d.Deadline().Equal(time.Unix(0, 20)) {
+		t.Errorf("Expected deadline unchanged, but found %v", d.Deadline())
+	}
+}
+
+// Once done, by expiry or cancellation, every method of a Deadline is
+// a no-op that reports as much.
+func TestDeadlineAfterDone(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	tree := NewDeadlineTree(tw)
+	expired := 0
+	fired, _ := tree.NewDeadline(time.Unix(0, 5), func(*time.Time) { expired++ })
+	cancelled, _ := tree.NewDeadline(time.Unix(0, 5), func(*time.Time) { expired++ })
+	if !cancelled.Cancel() {
+		t.Fatal("Expected first cancel to succeed")
+	}
+	tw.AdvanceTo(time.Unix(0, 5), 0)
+	for _, d := range []*Deadline{fired, cancelled} {
+		for idx := 0; idx < 2; idx++ {
+			if !d.Done() || d.Cancel() || !errors.Is(d.Reset(time.Unix(0, 50)), ScheduledInPast) {
+				t.Errorf("Expected done deadline to ignore Cancel and Reset")
+			}
+		}
+	}
+	tw.AdvanceTo(time.Unix(0, 100), 0)
+	if expired != 1 {
+		t.Errorf("Expected exactly 1 expiry, but got %v", expired)
+	}
+	assertNowLength(t, tw, time.Unix(0, 100), 0)
+}
@@ -0,0 +1,65 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A Debouncer invokes an event once its triggers have stopped: each
+// call to Trigger pushes the event back to delay after the Timer
+// Wheel's current time, so the event is invoked only after delay has
+// passed without a trigger. See NewDebouncer.
+type Debouncer struct {
+	tw    *TimerWheel
+	delay time.Duration
+	f     Event
+	event *eventNode
+}
+
+// Creates a Debouncer which invokes f delay after the most recent
+// call to its Trigger. Nothing is scheduled until Trigger is first
+// called. Delay must not be negative, and f must not be nil.
+func (tw *TimerWheel) NewDebouncer(delay time.Duration, f Event) *Debouncer {
+	if delay < 0 {
+		panic("Debouncer delay must not be negative")
+	}
+	if f == nil {
+		panic("Debouncer event must not be nil")
+	}
+	return &Debouncer{tw: tw, delay: delay, f: f}
+}
+
+// Schedules the Debouncer's event for delay after the Timer Wheel's
+// current time, replacing any earlier trigger still waiting to be
+// invoked. Returns any error from scheduling the event (for example
+// Closed), in which case an earlier trigger is left in place.
+func (d *Debouncer) Trigger() error {
+	event := d.tw.newEvent(eventNode{at: d.tw.after(d.delay).UnixNano()})
+	event.fun = func(now *time.Time) {
+		d.event = nil
+		d.f(now)
+	}
+	if err := d.tw.scheduleEvent(event); err != nil {
+		return err
+	}
+	d.Cancel()
+	d.event = event
+	return nil
+}
+
+// Returns true if the Debouncer has been triggered and its event is
+// still waiting to be invoked.
+func (d *Debouncer) Pending() bool {
+	return d.event != nil
+}
+
+// Prevents the Debouncer's event from being invoked until Trigger is
+// next called. Returns true if there was a trigger waiting to be
+// invoked.
+func (d *Debouncer) Cancel() bool {
+	if d.event == nil {
+		return false
+	}
+	d.tw.cancelEvent(d.event)
+	d.event = nil
+	return true
+}
@@ -0,0 +1,49 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	var invokedAt []time.Time
+	d := tw.NewDebouncer(10*time.Millisecond, func(*time.Time) { invokedAt = append(invokedAt, tw.Now()) })
+	if d.Pending() || d.Cancel() {
+		t.Error("Expected nothing to be pending before the first Trigger")
+	}
+	// triggers every 5ms keep pushing the event back
+	for idx := 0; idx < 4; idx++ {
+		if err := d.Trigger(); err != nil {
+			t.Fatal(err)
+		}
+		tw.AdvanceBy(5*time.Millisecond, 0)
+	}
+	if len(invokedAt) != 0 || tw.Length() != 1 || !d.Pending() {
+		t.Fatalf("Expected a single pending event, but got %v invoked and %v scheduled", len(invokedAt), tw.Length())
+	}
+	tw.AdvanceToNextEvent()
+	if len(invokedAt) != 1 || !invokedAt[0].Equal(start.Add(25*time.Millisecond)) || d.Pending() {
+		t.Errorf("Expected the event to be invoked once at 25ms, but got %v", invokedAt)
+	}
+
+	d.Trigger()
+	if !d.Cancel() || !tw.IsEmpty() {
+		t.Error("Expected Cancel to remove the pending event")
+	}
+	tw.Close(CloseDrop)
+	if err := d.Trigger(); err != Closed {
+		t.Errorf("Expected Closed, but got %v", err)
+	}
+}
+
+func TestDebouncerNil(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a nil event to be refused")
+		}
+	}()
+	tw.NewDebouncer(time.Millisecond, nil)
+}
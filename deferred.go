@@ -0,0 +1,63 @@
+package gotimerwheel
+
+type deferral struct {
+	// Set by WithDeferredScheduling.
+	enabled   bool
+	advancing bool
+	// The events scheduled by the advance under way, or the last one,
+	// for the time being advanced to.
+	events []*eventNode
+}
+
+// Schedules an event to be invoked at the Timer Wheel's current time.
+// Outside of an advance, the event is invoked by the next call to
+// AdvanceTo (or AdvanceBy and so on). From within an event being
+// invoked, the current time is already the time being advanced to, so
+// the event is invoked by the same call to AdvanceTo, after every
+// event already due, unless WithDeferredScheduling was given.
+func (tw *TimerWheel) ScheduleNow(e Event) error {
+	return tw.ScheduleEventAt(tw.Now(), e)
+}
+
+// Leaves events which are scheduled by events being invoked, for the
+// time being advanced to, to the next call to AdvanceTo (or AdvanceBy
+// and so on), rather than the same call invoking them. This makes
+// chains of events, each of which schedules the next with ScheduleNow
+// or for the current time, move one step per advance instead of
+// running to completion within one. Events already scheduled for the
+// time being advanced to are all invoked first, except for any which
+// a deferred event is ordered before, by priority or by a TieBreak
+// (see WithTieBreak), which are deferred along with it. Events
+// scheduled for later times are unaffected.
+func WithDeferredScheduling() Option {
+	return func(tw *TimerWheel) {
+		tw.deferral.enabled = true
+	}
+}
+
+// Marks an event being scheduled during an advance, for the time
+// being advanced to, so that the advance will not invoke it.
+func (tw *TimerWheel) deferIfDue(event *eventNode) {
+	d := &tw.root.deferral
+	if d.advancing && event.at <= tw.root.now {
+		event.deferred = true
+		d.events = append(d.events, event)
+	}
+}
+
+// Called as each advance begins, making the events deferred by the
+// last one due as usual.
+func (d *deferral) release() {
+	for idx, event := range d.events {
+		event.deferred = false
+		d.events[idx] = nil
+	}
+	d.events = d.events[:0]
+}
+
+// Marks the start and end of invoking events within an advance.
+func (d *deferral) invoking(advancing bool) {
+	if d.enabled {
+		d.advancing = advancing
+	}
+}
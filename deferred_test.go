@@ -0,0 +1,76 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNow(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, deferred := range []bool{false, true} {
+		var options []Option
+		if deferred {
+			options = append(options, WithDeferredScheduling())
+		}
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		var steps []int
+		var step func(n int) Event
+		step = func(n int) Event {
+			return func(*time.Time) {
+				steps = append(steps, n)
+				if n%10 < 3 {
+					tw.ScheduleNow(step(n + 1))
+				}
+			}
+		}
+		tw.ScheduleNow(step(1))
+		target := start.Add(5 * time.Millisecond)
+		tw.ScheduleEventAt(target, step(11))
+		tw.ScheduleEventAt(target, func(*time.Time) { steps = append(steps, 20) })
+
+		if count := tw.AdvanceTo(start, 0); deferred && count != 1 || !deferred && count != 3 {
+			t.Fatalf("Expected the chain scheduled now to be invoked in %v steps, but invoked %v", map[bool]int{false: 1, true: 3}[deferred], count)
+		}
+		for tw.Length() > 2 {
+			tw.AdvanceTo(start, 0)
+		}
+		count := tw.AdvanceTo(target, 0)
+		if deferred {
+			// both already due, but not the event scheduled by the first
+			if count != 2 || tw.Length() != 1 {
+				t.Fatalf("Expected 2 events invoked with 1 deferred, but invoked %v with %v left", count, tw.Length())
+			}
+			if tw.AdvanceTo(target, 0) != 1 || tw.AdvanceTo(target, 0) != 1 || !tw.IsEmpty() {
+				t.Fatal("Expected each deferred event to be invoked by the next advance")
+			}
+		} else if count != 4 || !tw.IsEmpty() {
+			t.Fatalf("Expected every event to be invoked by one advance, but invoked %v", count)
+		}
+		expected := []int{1, 2, 3, 11, 20, 12, 13}
+		if len(steps) != len(expected) {
+			t.Fatalf("Expected %v, but got %v", expected, steps)
+		}
+		for idx := range expected {
+			if steps[idx] != expected[idx] {
+				t.Fatalf("Expected %v, but got %v", expected, steps)
+			}
+		}
+	}
+}
+
+func TestDeferredSchedulingCancel(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithDeferredScheduling())
+	var h *EventHandle
+	tw.ScheduleEventAt(start.Add(time.Millisecond), func(now *time.Time) {
+		h, _ = tw.ScheduleHandleAt(*now, func(*time.Time) { t.Error("Expected the cancelled event not to be invoked") })
+	})
+	tw.AdvanceBy(time.Millisecond, 0)
+	if tw.Length() != 1 || !h.Cancel() || !tw.IsEmpty() {
+		t.Fatal("Expected the deferred event to be pending until cancelled")
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	if err := tw.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
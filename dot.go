@@ -0,0 +1,59 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes a Graphviz (DOT) diagram of the Timer Wheel's internal
+// state: each level of the hierarchy as a ring of buckets,
+// labelled with the number of events in each, with the ring position
+// of each level marked and the already-passed buckets greyed out. The
+// overflow heap (see WithOverflowHeap), if in use, is shown after the
+// last level. Render it with, for example, dot -Tsvg.
+func (tw *TimerWheel) WriteDOT(w io.Writer) error {
+	d := tw.dump()
+	var str strings.Builder
+	str.WriteString("digraph TimerWheel {\n")
+	str.WriteString("\trankdir=TB;\n")
+	str.WriteString("\tnode [shape=plaintext, fontname=\"monospace\"];\n")
+	fmt.Fprintf(&str, "\tlabel=%q;\n", fmt.Sprintf("now: %v, length: %v", d.Now, d.Length))
+	for _, ld := range d.Levels {
+		counts := make([]int, ld.RingLength)
+		for _, bd := range ld.Buckets {
+			counts[bd.Index] = bd.Count
+		}
+		fmt.Fprintf(&str, "\tlevel%d [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">\n", ld.Level)
+		fmt.Fprintf(&str, "\t\t<tr><td colspan=\"%d\">level %d: bucket size %v, %v to %v</td></tr>\n\t\t<tr>",
+			len(counts), ld.Level, ld.BucketSize, ld.Start, ld.End)
+		for idx, count := range counts {
+			attrs := ""
+			switch {
+			case idx < ld.RingIdx:
+				attrs = " bgcolor=\"grey\""
+			case idx == ld.RingIdx:
+				attrs = " bgcolor=\"lightblue\""
+			case count > 0:
+				attrs = " bgcolor=\"lightyellow\""
+			}
+			label := ""
+			if count > 0 {
+				label = fmt.Sprint(count)
+			}
+			fmt.Fprintf(&str, "<td%s>%s</td>", attrs, label)
+		}
+		str.WriteString("</tr>\n\t</table>>];\n")
+		if ld.Level > 0 {
+			fmt.Fprintf(&str, "\tlevel%d -> level%d [label=\"next\"];\n", ld.Level-1, ld.Level)
+		}
+	}
+	if len(d.Overflow) > 0 {
+		fmt.Fprintf(&str, "\toverflow [shape=box, label=%q];\n",
+			fmt.Sprintf("overflow heap: %v events from %v", len(d.Overflow), d.Overflow[0]))
+		fmt.Fprintf(&str, "\tlevel%d -> overflow;\n", len(d.Levels)-1)
+	}
+	str.WriteString("}\n")
+	_, err := io.WriteString(w, str.String())
+	return err
+}
@@ -0,0 +1,38 @@
+package gotimerwheel
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteDOT(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond, WithOverflowHeap(2))
+	nop := func(*time.Time) {}
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(time.Second, nop)
+	tw.ScheduleEventIn(time.Hour, nop)
+	tw.AdvanceBy(time.Millisecond, 0)
+
+	var out strings.Builder
+	if err := tw.WriteDOT(&out); err != nil {
+		t.Fatal(err)
+	}
+	dot := out.String()
+	for _, expected := range []string{
+		"digraph TimerWheel {",
+		"level0 [label=<",
+		"<td bgcolor=\"grey\"></td><td bgcolor=\"lightblue\"></td><td></td><td bgcolor=\"lightyellow\">2</td>",
+		"level0 -> level1",
+		"level1 -> overflow",
+		"overflow heap: 1 events",
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, dot)
+		}
+	}
+	if strings.Contains(dot, "level2") {
+		t.Errorf("Expected only 2 levels in:\n%s", dot)
+	}
+}
@@ -0,0 +1,34 @@
+package gotimerwheel
+
+// Invokes scheduled events immediately, regardless of the time they
+// are scheduled for, in time order. This is intended for shutting
+// down: pending expirations still get to run. Each event is given
+// its own scheduled time as its argument. The Timer Wheel's current
+// time is not changed. Limit works as for AdvanceTo: set to 0 to
+// invoke every event, or a positive limit to invoke at most limit
+// events, earliest first. Events scheduled by the events being
+// drained are not themselves drained, and events cancelled by the
+// events being drained are not invoked. Each event stays scheduled
+// until just before it is invoked, so if an event panics (with
+// PanicPropagate), the events after it remain scheduled. Returns the
+// number of events invoked.
+func (tw *TimerWheel) Drain(limit int) int {
+	events := make([]*eventNode, 0, tw.Length())
+	tw.forEachEvent(func(event *eventNode) bool {
+		events = append(events, event)
+		return limit <= 0 || len(events) < limit
+	})
+	count := 0
+	for _, event := range events {
+		// An earlier event may have cancelled this one.
+		if !tw.removeEvent(event) {
+			continue
+		}
+		count++
+		at := tw.toTime(event.at)
+		tw.fire(event, &at)
+	}
+	tw.notifyAggregate(tw.Now())
+	tw.PublishStats()
+	return count
+}
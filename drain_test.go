@@ -0,0 +1,98 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	run := createBasicRun(t)
+	// each event sees its own scheduled time
+	run.ScheduleEventAt(time.Unix(0, 1000), func(at *time.Time) {
+		run.assertExecCount(14)
+		if !at.Equal(time.Unix(0, 1000)) {
+			t.Errorf("Expected scheduled time as argument, but got %v", at)
+		}
+	})
+	if count := run.Drain(0); count != run.targetExecCount+1 {
+		t.Errorf("Expected %v callbacks to be invoked, but got %v", run.targetExecCount+1, count)
+	}
+	assertNowLength(t, run.TimerWheel, run.start, 0)
+	run.assertExecCount(run.targetExecCount + 1)
+}
+
+func TestDrainLimited(t *testing.T) {
+	run := createBasicRun(t)
+	total := 0
+	for count := run.Drain(4); count > 0; count = run.Drain(4) {
+		if count > 4 {
+			t.Errorf("Expected batch no greater than 4, but got %v", count)
+		}
+		total += count
+		assertNowLength(t, run.TimerWheel, run.start, run.targetExecCount-total)
+	}
+	if total != run.targetExecCount {
+		t.Errorf("Expected %v callbacks to be invoked, but got %v", run.targetExecCount, total)
+	}
+	run.assertExecCount(run.targetExecCount)
+	if stats := run.Stats(); stats.Fired != uint64(total) || stats.Cancelled != 0 {
+		t.Errorf("Expected drained events to count as fired: %+v", stats)
+	}
+}
+
+func TestDrainReschedule(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	var periodic Event
+	periodic = func(at *time.Time) { tw.ScheduleEventAt(at.Add(10), periodic) }
+	tw.ScheduleEventAt(time.Unix(0, 5), periodic)
+	if count := tw.Drain(0); count != 1 {
+		t.Errorf("Expected 1 callback to be invoked, but got %v", count)
+	}
+	// the rescheduled event is left alone
+	assertNowLength(t, tw, time.Unix(0, 0), 1)
+}
+
+func TestDrainCancel(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	invoked := []string{}
+	tw.ScheduleKeyedEventAt("a", time.Unix(0, 5), func(*time.Time) {
+		invoked = append(invoked, "a")
+		if !tw.CancelKey("b") {
+			t.Error("Expected to cancel b during drain")
+		}
+	})
+	tw.ScheduleKeyedEventAt("b", time.Unix(0, 6), func(*time.Time) { invoked = append(invoked, "b") })
+	tw.ScheduleKeyedEventAt("c", time.Unix(0, 1000), func(*time.Time) { invoked = append(invoked, "c") })
+	if count := tw.Drain(0); count != 2 {
+		t.Errorf("Expected 2 callbacks to be invoked, but got %v", count)
+	}
+	if len(invoked) != 2 || invoked[0] != "a" || invoked[1] != "c" {
+		t.Errorf("Expected a and c to be invoked, but got %v", invoked)
+	}
+	if stats := tw.Stats(); stats.Fired != 2 || stats.Cancelled != 1 {
+		t.Errorf("Expected 2 fired and 1 cancelled: %+v", stats)
+	}
+	assertNowLength(t, tw, time.Unix(0, 0), 0)
+}
+
+func TestDrainPanic(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	invoked := 0
+	tw.ScheduleEventAt(time.Unix(0, 5), func(*time.Time) { panic("boom") })
+	for idx := int64(1); idx <= 3; idx++ {
+		tw.ScheduleEventAt(time.Unix(0, 5+idx*100), func(*time.Time) { invoked++ })
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		tw.Drain(0)
+	}()
+	// the later events are still there, and drain as normal
+	assertNowLength(t, tw, time.Unix(0, 0), 3)
+	if count := tw.Drain(0); count != 3 || invoked != 3 {
+		t.Errorf("Expected 3 remaining events to be drained, but got %v", count)
+	}
+}
@@ -0,0 +1,92 @@
+package gotimerwheel
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+type wheelDump struct {
+	Now    time.Time   `json:"now"`
+	Length int         `json:"length"`
+	Levels []levelDump `json:"levels"`
+	// Events held in the overflow heap (see WithOverflowHeap).
+	Overflow []time.Time `json:"overflow,omitempty"`
+}
+
+type levelDump struct {
+	Level      int           `json:"level"`
+	Start      time.Time     `json:"start"`
+	End        time.Time     `json:"end"`
+	BucketSize time.Duration `json:"bucketSize"`
+	RingIdx    int           `json:"ringIdx"`
+	RingLength int           `json:"ringLength"`
+	Length     int           `json:"length"`
+	// Only the buckets with events in them.
+	Buckets []bucketDump `json:"buckets"`
+}
+
+type bucketDump struct {
+	Index  int         `json:"index"`
+	Start  time.Time   `json:"start"`
+	End    time.Time   `json:"end"`
+	Count  int         `json:"count"`
+	Events []time.Time `json:"events"`
+}
+
+// Returns a JSON description of the Timer Wheel's internal state, for
+// debugging and tooling: every level of the hierarchy, the time range
+// it covers and its position in its ring, and the count and times of
+// the events in each of its non-empty buckets. Bucket sizes are in
+// nanoseconds. The format is not covered by any promise of
+// compatibility.
+func (tw *TimerWheel) DumpJSON() ([]byte, error) {
+	return json.Marshal(tw.dump())
+}
+
+func (tw *TimerWheel) dump() wheelDump {
+	tw.completeCascades()
+	d := wheelDump{Now: tw.Now(), Length: tw.Length(), Levels: []levelDump{}}
+	for level := tw; level != nil; level = level.next {
+		d.Levels = append(d.Levels, level.dumpLevel())
+	}
+	for _, event := range tw.overflow.sorted() {
+		d.Overflow = append(d.Overflow, tw.toTime(event.at))
+	}
+	return d
+}
+
+func (tw *TimerWheel) dumpLevel() levelDump {
+	li := tw.levelInfo()
+	ld := levelDump{
+		Level:      li.Level,
+		Start:      li.Start,
+		End:        li.End,
+		BucketSize: li.BucketSize,
+		RingIdx:    li.RingIdx,
+		RingLength: len(li.Counts),
+		Length:     li.Length,
+		Buckets:    []bucketDump{},
+	}
+	for idx := tw.ringIdx; idx < len(tw.ring); idx++ {
+		b := &tw.ring[idx]
+		if b.count == 0 {
+			continue
+		}
+		start := tw.start + tw.bucketSize*int64(idx)
+		bd := bucketDump{
+			Index: idx,
+			Start: tw.toTime(start),
+			End:   tw.toTime(start + tw.bucketSize),
+			Count: b.count,
+		}
+		b.each(func(event *eventNode) bool {
+			bd.Events = append(bd.Events, tw.toTime(event.at))
+			return true
+		})
+		// Buckets of next wheels are not kept in order.
+		sort.Slice(bd.Events, func(a, b int) bool { return bd.Events[a].Before(bd.Events[b]) })
+		ld.Buckets = append(ld.Buckets, bd)
+	}
+	return ld
+}
@@ -0,0 +1,44 @@
+package gotimerwheel
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDumpJSON(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(time.Second, nop)
+	tw.AdvanceBy(time.Millisecond, 0)
+
+	data, err := tw.DumpJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d wheelDump
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Now.Equal(start.Add(time.Millisecond)) || d.Length != 3 || len(d.Levels) != 2 {
+		t.Fatalf("Unexpected dump %s", data)
+	}
+	root := d.Levels[0]
+	if root.RingIdx != 1 || root.BucketSize != time.Millisecond || root.Length != 2 || len(root.Buckets) != 1 {
+		t.Fatalf("Unexpected root level %+v", root)
+	}
+	b := root.Buckets[0]
+	if b.Index != 3 || !b.Start.Equal(start.Add(3*time.Millisecond)) || !b.End.Equal(start.Add(4*time.Millisecond)) || b.Count != 2 || len(b.Events) != 2 {
+		t.Errorf("Unexpected bucket %+v", b)
+	}
+	next := d.Levels[1]
+	if next.Level != 1 || next.BucketSize != 32*time.Millisecond || next.Length != 1 || len(next.Buckets) != 1 {
+		t.Errorf("Unexpected next level %+v", next)
+	}
+	if !next.Buckets[0].Events[0].Equal(start.Add(time.Second)) {
+		t.Errorf("Expected the next level's event at %v, but got %v", start.Add(time.Second), next.Buckets[0].Events)
+	}
+}
@@ -0,0 +1,131 @@
+package gotimerwheel
+
+import (
+	"errors"
+)
+
+var (
+	DuplicateEvent = errors.New("An event with the same tag and time is already scheduled")
+)
+
+// What to do when an event is scheduled with the same tag and time as
+// an event that is already scheduled (see WithDuplicateDetection).
+type DuplicatePolicy int
+
+const (
+	// Duplicates are scheduled as normal, and counted in
+	// Stats.Duplicates.
+	DuplicateCount DuplicatePolicy = iota
+	// Duplicates are refused with DuplicateEvent, and counted in
+	// Stats.Duplicates.
+	DuplicateReject
+)
+
+type duplicateKey struct {
+	tag string
+	at  int64
+}
+
+type duplicates struct {
+	enabled bool
+	policy  DuplicatePolicy
+	events  map[duplicateKey]*eventNode
+}
+
+// Detects events being scheduled with the same tag and time as an
+// event that is already scheduled and waiting to be invoked, which
+// usually means a timeout has accidentally been armed twice. Only
+// tagged events are checked: untagged events are never considered
+// duplicates. Events moved in by Merge are counted but never
+// rejected.
+func WithDuplicateDetection(policy DuplicatePolicy) Option {
+	return func(tw *TimerWheel) {
+		tw.duplicates = duplicates{
+			enabled: true,
+			policy:  policy,
+			events:  make(map[duplicateKey]*eventNode),
+		}
+	}
+}
+
+func keyForDuplicates(event *eventNode) duplicateKey {
+	return duplicateKey{tag: event.tag, at: event.at}
+}
+
+// Returns true if the event is a duplicate which should be rejected.
+func (tw *TimerWheel) rejectDuplicate(tag string, at int64) bool {
+	found := tw.isDuplicate(tag, at)
+	if found {
+		tw.stats.duplicates++
+	}
+	return found
+}
+
+// Returns true if duplicates are being rejected and an event with the
+// same tag and time is already scheduled.
+func (tw *TimerWheel) isDuplicate(tag string, at int64) bool {
+	d := &tw.duplicates
+	if !d.enabled || d.policy != DuplicateReject || tag == "" {
+		return false
+	}
+	_, found := d.events[duplicateKey{tag: tag, at: at}]
+	return found
+}
+
+// Returns true if any of the events is a duplicate, either of an
+// event already scheduled or of another of the events, which should
+// be rejected.
+func (tw *TimerWheel) rejectDuplicates(events []ScheduledEvent) bool {
+	d := &tw.duplicates
+	if !d.enabled || d.policy != DuplicateReject {
+		return false
+	}
+	seen := make(map[duplicateKey]bool)
+	for idx := range events {
+		event := &events[idx]
+		if event.Tag == "" {
+			continue
+		}
+		key := duplicateKey{tag: event.Tag, at: event.At.UnixNano()}
+		if tw.rejectDuplicate(key.tag, key.at) {
+			return true
+		}
+		if seen[key] {
+			tw.stats.duplicates++
+			return true
+		}
+		seen[key] = true
+	}
+	return false
+}
+
+// Records that the event has been scheduled.
+func (tw *TimerWheel) trackDuplicate(event *eventNode) {
+	d := &tw.duplicates
+	if !d.enabled || event.tag == "" {
+		return
+	}
+	key := keyForDuplicates(event)
+	if _, found := d.events[key]; found {
+		tw.stats.duplicates++
+	}
+	d.events[key] = event
+}
+
+// Records that the event is no longer scheduled.
+func (tw *TimerWheel) untrackDuplicate(event *eventNode) {
+	d := &tw.duplicates
+	if !d.enabled || event.tag == "" {
+		return
+	}
+	key := keyForDuplicates(event)
+	if d.events[key] == event {
+		delete(d.events, key)
+	}
+}
+
+func (d *duplicates) clear() {
+	for key := range d.events {
+		delete(d.events, key)
+	}
+}
@@ -0,0 +1,67 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuplicateReject(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1, WithDuplicateDetection(DuplicateReject))
+	nop := func(*time.Time) {}
+	at := time.Unix(0, 10)
+	if err := tw.ScheduleTaggedEventAt("timeout", at, nop); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.ScheduleTaggedEventAt("timeout", at, nop); err != DuplicateEvent {
+		t.Errorf("Expected DuplicateEvent, but got %v", err)
+	}
+	// different tag, different time, and untagged are all fine
+	if tw.ScheduleTaggedEventAt("other", at, nop) != nil ||
+		tw.ScheduleTaggedEventAt("timeout", at.Add(1), nop) != nil ||
+		tw.ScheduleEventAt(at, nop) != nil || tw.ScheduleEventAt(at, nop) != nil {
+		t.Error("Expected non-duplicates to be scheduled")
+	}
+	err := tw.ScheduleEvents([]ScheduledEvent{
+		{At: time.Unix(0, 20), Event: nop, Tag: "batch"},
+		{At: time.Unix(0, 20), Event: nop, Tag: "batch"},
+	})
+	if err != DuplicateEvent {
+		t.Errorf("Expected DuplicateEvent within batch, but got %v", err)
+	}
+	assertNowLength(t, tw, start, 5)
+
+	// once fired, the same pair can be scheduled again
+	tw.AdvanceTo(at, 0)
+	if err := tw.ScheduleTaggedEventAt("timeout", at, nop); err != nil {
+		t.Errorf("Expected reschedule after firing to succeed, but got %v", err)
+	}
+	// and likewise once cancelled
+	tw.Clear()
+	if err := tw.ScheduleTaggedEventAt("timeout", at.Add(1), nop); err != nil {
+		t.Errorf("Expected reschedule after clearing to succeed, but got %v", err)
+	}
+	if duplicates := tw.Stats().Duplicates; duplicates != 2 {
+		t.Errorf("Expected 2 duplicates, but got %v", duplicates)
+	}
+
+	clone := tw.Clone()
+	if err := clone.ScheduleTaggedEventAt("timeout", at.Add(1), nop); err != DuplicateEvent {
+		t.Errorf("Expected clone to detect duplicate, but got %v", err)
+	}
+}
+
+func TestDuplicateCount(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1, WithDuplicateDetection(DuplicateCount))
+	nop := func(*time.Time) {}
+	for idx := 0; idx < 3; idx++ {
+		if err := tw.ScheduleTaggedEventIn("timeout", 5000, nop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertNowLength(t, tw, start, 3)
+	if duplicates := tw.Stats().Duplicates; duplicates != 2 {
+		t.Errorf("Expected 2 duplicates, but got %v", duplicates)
+	}
+}
@@ -0,0 +1,59 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"time"
+)
+
+// An event which can fail. Errors returned by an EventE are kept by
+// the Timer Wheel and retrieved with Errors or AdvanceToE.
+type EventE func(*time.Time) error
+
+// An error returned by an EventE.
+type EventError struct {
+	// The time the event was scheduled for.
+	At time.Time
+	// The error returned by the event.
+	Err error
+}
+
+func (ee *EventError) Error() string {
+	return fmt.Sprintf("Event scheduled at %v failed: %v", ee.At, ee.Err)
+}
+
+func (ee *EventError) Unwrap() error {
+	return ee.Err
+}
+
+// Schedules an event which can fail to be invoked at the indicated
+// time. Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) ScheduleEventAtE(at time.Time, e EventE) error {
+	event := tw.newEvent(eventNode{at: at.UnixNano(), funE: e})
+	if e != nil {
+		// fun is only used to present the event through ForEach.
+		event.fun = func(now *time.Time) { e(now) }
+	}
+	return tw.scheduleEvent(event)
+}
+
+// Schedules an event which can fail to be invoked at the current
+// Timer Wheel's time plus the supplied duration.
+func (tw *TimerWheel) ScheduleEventInE(in time.Duration, e EventE) error {
+	return tw.ScheduleEventAtE(tw.after(in), e)
+}
+
+// Returns the errors returned by events since the last call to Errors
+// or AdvanceToE, in the order the events were invoked.
+func (tw *TimerWheel) Errors() []*EventError {
+	errors := tw.errors
+	tw.errors = nil
+	return errors
+}
+
+// Just the same as AdvanceTo, but also returns the errors returned by
+// events since the last call to Errors or AdvanceToE. Each batch of a
+// limited advance therefore returns the errors of its own events.
+func (tw *TimerWheel) AdvanceToE(now time.Time, limit int) (int, []*EventError) {
+	count := tw.AdvanceTo(now, limit)
+	return count, tw.Errors()
+}
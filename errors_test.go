@@ -0,0 +1,43 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventErrors(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	failure := errors.New("failure")
+	for idx := int64(1); idx <= 6; idx++ {
+		idx := idx
+		tw.ScheduleEventAtE(time.Unix(0, idx), func(*time.Time) error {
+			if idx%2 == 0 {
+				return failure
+			}
+			return nil
+		})
+	}
+	tw.ScheduleEventInE(1000, func(*time.Time) error { return failure })
+
+	count, errs := tw.AdvanceToE(time.Unix(0, 10), 3)
+	if count != 3 || len(errs) != 1 || !errs[0].At.Equal(time.Unix(0, 2)) {
+		t.Fatalf("Expected 3 events and 1 error, but got %v and %v", count, errs)
+	}
+	count, errs = tw.AdvanceToE(time.Unix(0, 10), 0)
+	if count != 3 || len(errs) != 2 || !errs[0].At.Equal(time.Unix(0, 4)) || !errs[1].At.Equal(time.Unix(0, 6)) {
+		t.Fatalf("Expected 3 events and 2 errors, but got %v and %v", count, errs)
+	}
+	if !errors.Is(errs[0], failure) {
+		t.Errorf("Expected error to unwrap to %v", failure)
+	}
+
+	tw.AdvanceTo(time.Unix(0, 1000), 0)
+	if errs := tw.Errors(); len(errs) != 1 || !errs[0].At.Equal(time.Unix(0, 1000)) {
+		t.Errorf("Expected error from plain AdvanceTo to be kept, but got %v", errs)
+	}
+	if errs := tw.Errors(); len(errs) != 0 {
+		t.Errorf("Expected errors to be cleared, but got %v", errs)
+	}
+}
@@ -0,0 +1,97 @@
+package gotimerwheel
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type namedEvent struct {
+	name    string
+	payload []byte
+}
+
+type exportedEvent struct {
+	At      time.Time `json:"at"`
+	Name    string    `json:"name"`
+	Payload []byte    `json:"payload,omitempty"`
+}
+
+// Sets the Handlers through which events scheduled by name (see
+// ScheduleNamedEventAt and ImportJSON) are built.
+func WithHandlers(handlers Handlers) Option {
+	return func(tw *TimerWheel) {
+		tw.handlers = handlers
+	}
+}
+
+// Schedules the event built by the named Handler (see WithHandlers)
+// from payload to be invoked at the indicated time. Unlike an Event,
+// a name and payload can be written out, so events scheduled this way
+// are included by ExportJSON. Returns UnknownHandler if there is no
+// such Handler. Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) ScheduleNamedEventAt(at time.Time, name string, payload []byte) error {
+	e, err := tw.handlers.event(name, payload)
+	if err != nil {
+		return err
+	}
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: at.UnixNano(), fun: e, named: &namedEvent{name: name, payload: payload}}))
+}
+
+// Schedules the event built by the named Handler from payload to be
+// invoked at the current Timer Wheel's time plus the supplied
+// duration. See ScheduleNamedEventAt.
+func (tw *TimerWheel) ScheduleNamedEventIn(in time.Duration, name string, payload []byte) error {
+	return tw.ScheduleNamedEventAt(tw.after(in), name, payload)
+}
+
+// Returns the events scheduled by name which are waiting to be
+// invoked, as a JSON array of their times, names and payloads in the
+// order in which they would be invoked, for ImportJSON to schedule in
+// another Timer Wheel, perhaps in another process. Events not
+// scheduled by name are left out. The events stay scheduled in this
+// Timer Wheel: to move them, Clear or Close it once the export has
+// been imported.
+func (tw *TimerWheel) ExportJSON() ([]byte, error) {
+	exported := []exportedEvent{}
+	tw.forEachEvent(func(event *eventNode) bool {
+		if event.named != nil {
+			exported = append(exported, exportedEvent{
+				At:      tw.toTime(event.at),
+				Name:    event.named.name,
+				Payload: event.named.payload,
+			})
+		}
+		return true
+	})
+	return json.Marshal(exported)
+}
+
+// Schedules the events exported by ExportJSON, building them through
+// this Timer Wheel's Handlers. Events whose time has passed are
+// scheduled for the Timer Wheel's current time instead, so they are
+// invoked by its next advance. Returns the number of events
+// scheduled, and UnknownHandler if an exported name has no Handler,
+// in which case nothing is scheduled. Any other error scheduling an
+// event (for example WheelFull) stops the import, leaving the events
+// before it scheduled.
+func (tw *TimerWheel) ImportJSON(data []byte) (int, error) {
+	var exported []exportedEvent
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return 0, err
+	}
+	for _, ee := range exported {
+		if _, found := tw.handlers[ee.Name]; !found {
+			return 0, UnknownHandler
+		}
+	}
+	for idx, ee := range exported {
+		at := ee.At
+		if at.UnixNano() < tw.now {
+			at = tw.Now()
+		}
+		if err := tw.ScheduleNamedEventAt(at, ee.Name, ee.Payload); err != nil {
+			return idx, err
+		}
+	}
+	return len(exported), nil
+}
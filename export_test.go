@@ -0,0 +1,59 @@
+package gotimerwheel
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	start := time.Unix(0, 0)
+	var invoked []string
+	handlers := Handlers{
+		"send": func(payload []byte) Event {
+			return func(*time.Time) { invoked = append(invoked, string(payload)) }
+		},
+	}
+	blue := NewTimerWheel(start, time.Millisecond, WithHandlers(handlers))
+	if err := blue.ScheduleNamedEventIn(time.Millisecond, "nope", nil); err != UnknownHandler {
+		t.Errorf("Expected UnknownHandler, but got %v", err)
+	}
+	blue.ScheduleNamedEventIn(time.Hour, "send", []byte("later"))
+	blue.ScheduleNamedEventIn(time.Millisecond, "send", []byte("soon"))
+	blue.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	data, err := blue.ExportJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blue.Length() != 3 {
+		t.Errorf("Expected the events to stay scheduled, but got %v", blue.Length())
+	}
+
+	// the new process starts a little later, so the first event is
+	// overdue
+	green := NewTimerWheel(start.Add(time.Second), time.Millisecond, WithHandlers(handlers))
+	if count, err := green.ImportJSON(data); count != 2 || err != nil {
+		t.Fatalf("Expected 2 events imported, but got %v, %v", count, err)
+	}
+	green.AdvanceBy(0, 0)
+	if len(invoked) != 1 || invoked[0] != "soon" {
+		t.Errorf("Expected the overdue event invoked, but got %v", invoked)
+	}
+	green.AdvanceTo(start.Add(time.Hour), 0)
+	if len(invoked) != 2 || invoked[1] != "later" {
+		t.Errorf("Expected the later event invoked at its time, but got %v", invoked)
+	}
+	// exporting again carries what is left
+	green.ScheduleNamedEventIn(time.Minute, "send", nil)
+	var exported []exportedEvent
+	data, _ = green.ExportJSON()
+	if err := json.Unmarshal(data, &exported); err != nil || len(exported) != 1 ||
+		!exported[0].At.Equal(start.Add(time.Hour+time.Minute)) || exported[0].Name != "send" {
+		t.Errorf("Unexpected export %s", data)
+	}
+
+	empty := NewTimerWheel(start, time.Millisecond)
+	if _, err := empty.ImportJSON(data); err != UnknownHandler || !empty.IsEmpty() {
+		t.Errorf("Expected UnknownHandler scheduling nothing, but got %v", err)
+	}
+}
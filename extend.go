@@ -0,0 +1,63 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	NotPending = errors.New("The event is no longer waiting to be invoked")
+)
+
+// Moves the event tracked by h to the indicated time, typically a
+// little later than before, as when a lease is renewed or a session
+// sees a heartbeat. This is much cheaper than cancelling the event
+// and scheduling a new one: the event's node is reused, so nothing is
+// allocated, and if the new time falls in the same bucket of a next
+// wheel as the old one, the event is not even moved. Returns
+// NotPending if the event is no longer waiting to be invoked, a
+// *ScheduledInPastError if at is in the past of the Timer Wheel's
+// current time (whatever the past policy), and TooFarInFuture if at
+// is beyond the horizon (see WithMaxLevels). In each case nothing is
+// changed.
+func (tw *TimerWheel) ExtendDeadline(h *EventHandle, at time.Time) error {
+	if h.tw != tw || h.State() != EventPending {
+		return NotPending
+	}
+	ns := at.UnixNano()
+	if ns < tw.now {
+		return tw.pastError(ns)
+	}
+	if tw.refuseBeyond(ns) {
+		return TooFarInFuture
+	}
+	event := h.event
+	// Buckets of next wheels are unordered, so within one the event
+	// need only have its time changed.
+	if level, idx := tw.locate(event.at); level != nil && level != tw && !level.ring[idx].sliced {
+		if newLevel, newIdx := tw.locate(ns); newLevel == level && newIdx == idx {
+			event.at = ns
+			return nil
+		}
+	}
+	if !tw.removeEvent(event) {
+		return NotPending
+	}
+	event.at = ns
+	// Unlinked from its old bucket, but still pointing into it.
+	event.next.eventNode = nil
+	tw.placeEvent(event)
+	return nil
+}
+
+// Returns the wheel of the hierarchy, and the index in its ring, of
+// the bucket which holds events for at. Returns nil if no wheel yet
+// covers at.
+func (tw *TimerWheel) locate(at int64) (*TimerWheel, int) {
+	for level := tw; level != nil; level = level.next {
+		if idx := int((at - level.start) / level.bucketSize); idx < len(level.ring) {
+			return level, idx
+		}
+	}
+	return nil, 0
+}
@@ -0,0 +1,77 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtendDeadline(t *testing.T) {
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}, {WithOverflowHeap(1)}} {
+		start := time.Unix(0, 0)
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		var invokedAt []time.Time
+		record := func(now *time.Time) { invokedAt = append(invokedAt, *now) }
+		nearby, _ := tw.ScheduleHandleIn(2*time.Millisecond, record)
+		tw.ScheduleEventIn(2*time.Millisecond, record)
+		tw.ScheduleEventIn(3*time.Millisecond, record)
+		lease, _ := tw.ScheduleHandleIn(time.Second, record)
+		// within the root wheel, past the other event
+		if err := tw.ExtendDeadline(nearby, start.Add(4*time.Millisecond)); err != nil {
+			t.Fatal(err)
+		}
+		// heartbeats push the lease on, within and across buckets
+		for _, extra := range []time.Duration{time.Microsecond, 2 * time.Microsecond, time.Minute, time.Hour} {
+			if err := tw.ExtendDeadline(lease, start.Add(time.Second+extra)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !lease.At().Equal(start.Add(time.Second+time.Hour)) || tw.Length() != 4 {
+			t.Errorf("Expected the lease extended to %v, but got %v", start.Add(time.Second+time.Hour), lease.At())
+		}
+		tw.AdvanceTo(start.Add(time.Hour), 0)
+		if len(invokedAt) != 3 || !invokedAt[0].Equal(start.Add(time.Hour)) {
+			t.Errorf("Expected the other events invoked, but got %v", invokedAt)
+		}
+		tw.AdvanceTo(start.Add(time.Second+time.Hour), 0)
+		if len(invokedAt) != 4 || lease.State() != EventFired {
+			t.Errorf("Expected the lease invoked at its extended time, but got %v", invokedAt)
+		}
+		if stats := tw.Stats(); stats.Scheduled != 4 || stats.Cancelled != 0 || stats.Fired != 4 {
+			t.Errorf("Expected extensions not to count as cancellations, but got %+v", stats)
+		}
+		if err := tw.ExtendDeadline(lease, start.Add(2*time.Hour)); err != NotPending {
+			t.Errorf("Expected NotPending, but got %v", err)
+		}
+		pending, _ := tw.ScheduleHandleIn(time.Millisecond, record)
+		if err := tw.ExtendDeadline(pending, start); !errors.Is(err, ScheduledInPast) || !pending.At().Equal(tw.Now().Add(time.Millisecond)) {
+			t.Errorf("Expected ScheduledInPast changing nothing, but got %v", err)
+		}
+	}
+}
+
+// Extending a deadline reuses the event's node.
+func TestExtendDeadlineAllocs(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	h, _ := tw.ScheduleHandleIn(time.Minute, func(*time.Time) {})
+	at := start.Add(time.Minute)
+	if allocs := testing.AllocsPerRun(1000, func() {
+		at = at.Add(100 * time.Microsecond)
+		tw.ExtendDeadline(h, at)
+	}); allocs != 0 {
+		t.Errorf("Expected ExtendDeadline not to allocate, but got %v", allocs)
+	}
+}
+
+func BenchmarkExtendDeadline(b *testing.B) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	h, _ := tw.ScheduleHandleIn(time.Minute, func(*time.Time) {})
+	at := start.Add(time.Minute)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		at = at.Add(time.Microsecond)
+		tw.ExtendDeadline(h, at)
+	}
+}
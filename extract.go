@@ -0,0 +1,91 @@
+package gotimerwheel
+
+// Removes every scheduled event for which filter returns true, and
+// returns them in the order in which they would have been invoked,
+// ready to be moved into another Timer Wheel with ScheduleExtracted,
+// as when timers are rebalanced across shards. Extracted events count
+// as cancelled in the Timer Wheel's Stats, and keyed events lose
+// their keys here. Groups lose track of extracted events, but
+// EventHandles follow them into the Timer Wheel they are scheduled
+// into. Filter must not schedule or cancel events in the Timer Wheel.
+func (tw *TimerWheel) Extract(filter func(PendingEvent) bool) []PendingEvent {
+	var extracted []PendingEvent
+	tw.forEachEvent(func(event *eventNode) bool {
+		if pe := tw.pendingEvent(event); filter(pe) {
+			pe.event = event
+			extracted = append(extracted, pe)
+		}
+		return true
+	})
+	for idx := range extracted {
+		event := extracted[idx].event
+		tw.removeEvent(event)
+		tw.forgetKey(event)
+		tw.forgetID(event)
+		tw.untrackDuplicate(event)
+		event.group.forget(event)
+		event.group = nil
+		tw.stats.cancelled++
+	}
+	return extracted
+}
+
+// Schedules events returned by Extract, typically from another Timer
+// Wheel. Each event keeps its scheduled time, and everything else
+// about it: recurring and chained events carry on recurring and
+// chaining, named events can still be exported, and so on. Keyed
+// events keep their keys, replacing any event with the same key. As
+// with Merge, if any of the events is in the past of the current time
+// then ScheduledInPast is returned and nothing is scheduled, unless
+// another policy has been set with WithPastPolicy; likewise WheelFull
+// and TooFarInFuture. Events which did not come from Extract, such as
+// those returned by PeekNext, are scheduled afresh from their At,
+// Event, Key, Tag and Priority. Each event returned by Extract must be
+// scheduled only once.
+func (tw *TimerWheel) ScheduleExtracted(events []PendingEvent) error {
+	if tw.closed {
+		return Closed
+	}
+	nodes := make([]*eventNode, len(events))
+	for idx := range events {
+		pe := &events[idx]
+		event := pe.event
+		if event == nil {
+			if pe.Event == nil && !tw.nilEvents {
+				return NilEvent
+			}
+			event = tw.newEvent(eventNode{at: pe.At.UnixNano(), fun: pe.Event, key: pe.Key, keyed: pe.Key != "", tag: pe.Tag, priority: pe.Priority})
+		}
+		nodes[idx] = event
+	}
+	sortEvents(nodes, tw.tieBreak)
+	if len(nodes) > 0 {
+		if err := tw.refusePast(nodes[0].at); err != nil {
+			return err
+		}
+		if tw.refuseBeyond(nodes[len(nodes)-1].at) {
+			return TooFarInFuture
+		}
+	}
+	if tw.full(len(nodes)) {
+		return WheelFull
+	}
+	for _, event := range nodes {
+		if event.keyed {
+			tw.CancelKey(event.key)
+			if tw.keys == nil {
+				tw.keys = make(map[string]*eventNode)
+			}
+			tw.keys[event.key] = event
+		}
+		if h := event.handle; h != nil {
+			h.tw, h.clears = tw, tw.clears
+		}
+	}
+	nodes, past := tw.splitPast(nodes)
+	tw.scheduleSortedEvents(nodes)
+	for _, event := range past {
+		tw.invokePast(event)
+	}
+	return nil
+}
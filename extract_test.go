@@ -0,0 +1,84 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	start := time.Unix(0, 0)
+	from := NewTimerWheel(start, time.Millisecond)
+	to := NewTimerWheel(start, time.Second)
+	fired := 0
+	e := func(*time.Time) { fired++ }
+	for idx := 0; idx < 1000; idx++ {
+		tag := "stay"
+		if idx%4 == 0 {
+			tag = "move"
+		}
+		from.ScheduleTaggedEventAt(tag, start.Add(time.Duration(idx)*time.Millisecond), e)
+	}
+	from.ScheduleKeyedEventAt("key", start.Add(time.Minute), e)
+	h, _ := from.ScheduleHandleAt(start.Add(time.Hour), e)
+	recurred := 0
+	from.ScheduleRecurringEventAt(start.Add(30*time.Second), func(now time.Time) (time.Time, bool) {
+		recurred++
+		return now.Add(time.Second), recurred < 3
+	})
+
+	events := from.Extract(func(pe PendingEvent) bool {
+		return pe.Tag == "move" || pe.Key == "key" || pe.At.After(start.Add(10*time.Second))
+	})
+	if len(events) != 253 || from.Length() != 750 || from.Stats().Cancelled != 253 {
+		t.Fatalf("Expected 253 events extracted and 750 left, but extracted %v and left %v", len(events), from.Length())
+	}
+	for idx := 1; idx < len(events); idx++ {
+		if events[idx].At.Before(events[idx-1].At) {
+			t.Fatalf("Expected extracted events in order, but %v came after %v", events[idx].At, events[idx-1].At)
+		}
+	}
+	if from.CancelKey("key") {
+		t.Error("Expected the extracted event to have lost its key")
+	}
+
+	if err := to.ScheduleExtracted(events); err != nil {
+		t.Fatal(err)
+	}
+	if to.Length() != 253 || h.State() != EventPending {
+		t.Fatalf("Expected 253 events scheduled, but got %v", to.Length())
+	}
+	if len(from.PeekNext(1000)) != 750 {
+		t.Error("Expected scheduling into another Timer Wheel to leave the first alone")
+	}
+	to.AdvanceTo(start.Add(2*time.Hour), 0)
+	// each advance invokes the recurring event once more
+	to.AdvanceBy(time.Second, 0)
+	to.AdvanceBy(time.Second, 0)
+	if fired != 252 || recurred != 3 || h.State() != EventFired || !to.IsEmpty() {
+		t.Errorf("Expected every moved event to fire in its new Timer Wheel, but %v fired and recurred %v times", fired, recurred)
+	}
+	if from.AdvanceTo(start.Add(2*time.Hour), 0) != 750 {
+		t.Error("Expected the events left behind to fire where they were")
+	}
+}
+
+func TestScheduleExtractedPast(t *testing.T) {
+	start := time.Unix(0, 0)
+	from := NewTimerWheel(start, time.Millisecond)
+	to := NewTimerWheel(start.Add(time.Minute), time.Millisecond)
+	from.ScheduleEventAt(start.Add(time.Hour), func(*time.Time) {})
+	from.ScheduleEventAt(start.Add(time.Second), func(*time.Time) {})
+	events := from.Extract(func(PendingEvent) bool { return true })
+	var past *ScheduledInPastError
+	if err := to.ScheduleExtracted(events); !errors.As(err, &past) || !to.IsEmpty() {
+		t.Fatalf("Expected ScheduledInPast with nothing scheduled, but got %v", err)
+	}
+	if err := to.ScheduleExtracted(events[1:]); err != nil || to.Length() != 1 {
+		t.Fatalf("Expected the later event to be scheduled, but got %v", err)
+	}
+	peeked := []PendingEvent{{At: start.Add(2 * time.Minute), Event: func(*time.Time) {}, Key: "key"}}
+	if err := to.ScheduleExtracted(peeked); err != nil || to.Length() != 2 || !to.CancelKey("key") {
+		t.Fatalf("Expected an event built afresh to be scheduled with its key, but got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Calls f for every scheduled event, in the order in which they would
+// be invoked, without invoking any of them. Iteration stops early if
+// f returns false. F must not schedule or cancel events in the Timer
+// Wheel.
+func (tw *TimerWheel) ForEach(f func(at time.Time, e Event) bool) {
+	tw.forEachEvent(func(event *eventNode) bool {
+		return f(tw.toTime(event.at), event.fun)
+	})
+}
+
+func (tw *TimerWheel) forEachEvent(f func(*eventNode) bool) {
+	tw.completeCascades()
+	// Buckets of the root wheel are kept sorted, so can be walked
+	// directly.
+	for idx := tw.ringIdx; idx < len(tw.ring); idx++ {
+		b := &tw.ring[idx]
+		b.sort()
+		if !b.each(f) {
+			return
+		}
+	}
+	// Buckets of next wheels are not sorted. Every bucket of a next
+	// wheel is later than every bucket of the wheel before it.
+	var events []*eventNode
+	for level := tw.next; level != nil; level = level.next {
+		for _, b := range level.ring[level.ringIdx:] {
+			events = events[:0]
+			for event := b.eventNode; event != nil; event = event.next.eventNode {
+				events = append(events, event)
+			}
+			// Back into order of insertion, so that events for the
+			// same time are walked in the order they would be invoked.
+			for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+				events[i], events[j] = events[j], events[i]
+			}
+			sortEvents(events, tw.tieBreak)
+			for _, event := range events {
+				if !f(event) {
+					return
+				}
+			}
+		}
+	}
+	overflowed := tw.overflow.sorted()
+	// The heap orders events by priority alone, leaving the tie-break
+	// to the buckets they are moved into.
+	sortEvents(overflowed, tw.tieBreak)
+	for _, event := range overflowed {
+		if !f(event) {
+			return
+		}
+	}
+}
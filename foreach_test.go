@@ -0,0 +1,37 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	run := createBasicRun(t)
+	var previous time.Time
+	count := 0
+	run.ForEach(func(at time.Time, e Event) bool {
+		if at.Before(previous) {
+			t.Errorf("Events out of order: %v before %v", at, previous)
+		}
+		previous = at
+		count++
+		return true
+	})
+	if count != run.targetExecCount {
+		t.Errorf("Expected %v events, but walked %v", run.targetExecCount, count)
+	}
+	if !previous.Equal(time.Unix(0, 331)) {
+		t.Errorf("Expected last event at 331, but found %v", previous)
+	}
+	// nothing was invoked
+	assertNowLength(t, run.TimerWheel, run.start, run.targetExecCount)
+
+	count = 0
+	run.ForEach(func(at time.Time, e Event) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("Expected iteration to stop after 3 events, but walked %v", count)
+	}
+}
@@ -0,0 +1,92 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A Future reports the completion of a single scheduled event, so
+// that another goroutine can wait until the event has actually been
+// invoked. See ScheduleFutureAt.
+type Future struct {
+	done chan struct{}
+	err  error
+}
+
+// Returns a channel which is closed once the event has been invoked.
+// If the event is cancelled or dropped without being invoked, for
+// example by Clear, the channel is never closed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Returns the error returned by the event, or an *EventPanic if the
+// event panicked. Err must only be called once Done is closed.
+func (f *Future) Err() error {
+	return f.err
+}
+
+// Schedules an event to be invoked at the indicated time, returning a
+// Future which is completed once the event has been invoked. If the
+// event cannot be scheduled, the error is returned and the Future is
+// nil. Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) ScheduleFutureAt(at time.Time, e Event) (*Future, error) {
+	if e == nil && !tw.nilEvents {
+		return nil, NilEvent
+	}
+	f := &Future{done: make(chan struct{})}
+	err := tw.ScheduleEventAt(at, func(now *time.Time) {
+		defer f.complete(at)
+		if e != nil {
+			e(now)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Schedules an event to be invoked at the current Timer Wheel's time
+// plus the supplied duration. See ScheduleFutureAt.
+func (tw *TimerWheel) ScheduleFutureIn(in time.Duration, e Event) (*Future, error) {
+	return tw.ScheduleFutureAt(tw.after(in), e)
+}
+
+// Just the same as ScheduleFutureAt, but for an event which can fail.
+// The error returned by the event is available from the Future's Err,
+// as well as being kept by the Timer Wheel as for ScheduleEventAtE.
+func (tw *TimerWheel) ScheduleFutureAtE(at time.Time, e EventE) (*Future, error) {
+	if e == nil && !tw.nilEvents {
+		return nil, NilEvent
+	}
+	f := &Future{done: make(chan struct{})}
+	err := tw.ScheduleEventAtE(at, func(now *time.Time) error {
+		defer f.complete(at)
+		if e != nil {
+			f.err = e(now)
+		}
+		return f.err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Schedules an event which can fail to be invoked at the current
+// Timer Wheel's time plus the supplied duration. See
+// ScheduleFutureAtE.
+func (tw *TimerWheel) ScheduleFutureInE(in time.Duration, e EventE) (*Future, error) {
+	return tw.ScheduleFutureAtE(tw.after(in), e)
+}
+
+// Completes the Future. Must be deferred by the event so that a panic
+// is recorded before it carries on to the Timer Wheel's panic policy.
+func (f *Future) complete(at time.Time) {
+	if recovered := recover(); recovered != nil {
+		f.err = &EventPanic{At: at, Recovered: recovered}
+		close(f.done)
+		panic(recovered)
+	}
+	close(f.done)
+}
@@ -0,0 +1,78 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFuture(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	fired := false
+	f, err := tw.ScheduleFutureIn(10*time.Millisecond, func(*time.Time) { fired = true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.AdvanceBy(5*time.Millisecond, 0)
+	select {
+	case <-f.Done():
+		t.Error("Expected the Future not to be done yet")
+	default:
+	}
+	tw.AdvanceBy(5*time.Millisecond, 0)
+	select {
+	case <-f.Done():
+	default:
+		t.Fatal("Expected the Future to be done")
+	}
+	if !fired || f.Err() != nil {
+		t.Errorf("Expected the event to have fired without error, but got %v, %v", fired, f.Err())
+	}
+	if _, err := tw.ScheduleFutureAt(start, func(*time.Time) {}); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+}
+
+func TestFutureE(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	failure := errors.New("failure")
+	f, err := tw.ScheduleFutureInE(time.Millisecond, func(*time.Time) error { return failure })
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	<-f.Done()
+	if f.Err() != failure {
+		t.Errorf("Expected %v, but got %v", failure, f.Err())
+	}
+	if errs := tw.Errors(); len(errs) != 1 || errs[0].Err != failure {
+		t.Errorf("Expected the Timer Wheel to keep the error, but got %v", errs)
+	}
+}
+
+func TestFuturePanic(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond, WithPanicPolicy(PanicCollect, nil))
+	f, _ := tw.ScheduleFutureIn(time.Millisecond, func(*time.Time) { panic("boom") })
+	tw.AdvanceBy(time.Millisecond, 0)
+	<-f.Done()
+	ep, ok := f.Err().(*EventPanic)
+	if !ok || ep.Recovered != "boom" {
+		t.Errorf("Expected an EventPanic, but got %v", f.Err())
+	}
+	if panics := tw.Panics(); len(panics) != 1 {
+		t.Errorf("Expected the panic to reach the panic policy, but got %v", panics)
+	}
+}
+
+func TestFutureWorkerPool(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond, WithWorkerPool(2))
+	defer tw.Close(CloseDrop)
+	f, _ := tw.ScheduleFutureIn(time.Millisecond, func(*time.Time) {})
+	tw.AdvanceBy(time.Millisecond, 0)
+	select {
+	case <-f.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the Future to be completed by the pool")
+	}
+}
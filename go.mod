@@ -1 +1,3 @@
-module "github.com/msackman/gotimerwheel"
+module github.com/msackman/gotimerwheel
+
+go 1.16
@@ -3,6 +3,7 @@ package gotimerwheel
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -20,32 +21,149 @@ var (
 type Event func(*time.Time)
 
 type TimerWheel struct {
-	ring       []eventNodeContainer
-	ringIdx    int
-	next       *TimerWheel
-	now        time.Time
-	start      time.Time
-	bucketSize time.Duration
+	ring    []bucket
+	ringIdx int
+	next    *TimerWheel
+	root    *TimerWheel
+	// Times are held as nanoseconds since the Unix epoch, in the
+	// location of the startAt time, so that the arithmetic on them is
+	// cheap.
+	now        int64
+	start      int64
+	loc        *time.Location
+	bucketSize int64
+	// The number of buckets at each level, if set by
+	// NewHierarchicalTimerWheel.
+	levelSizes []int
+	alignment  time.Duration
+	stats      counters
+	panics     panicRecovery
+	keys       map[string]*eventNode
+	aggregate  aggregation
+	errors     []*EventError
+	observer   Observer
+	middleware []Middleware
+	logger     Logger
+	handlers   Handlers
+	lateness   lateness
+	duplicates duplicates
+	overflow   overflow
+	jitter     jitter
+	pastPolicy PastPolicy
+	capacity   capacity
+	threshold  bucketThreshold
+	tieBreak   TieBreak
+	intakes    []*Intake
+	namespaces map[string]*Namespace
+	// The number of calls to Clear, so that EventHandles can tell
+	// their events have been cleared.
+	clears  uint64
+	closed  bool
+	pool    *workerPool
+	clock   Clock
+	driving clockDriving
+	// Set if the Timer Wheel has been registered (see Register).
+	registration *registration
+	readView     *ReadView
+	waiters      *timeWaiters
+	pull         pullSink
+	adaptive     adaptiveTuning
+	// See WithIncrementalCascade. The budget is only set on the root
+	// wheel, but every wheel may have events pending.
+	cascadeBudget int
+	cascade       pendingCascade
+	approximate   bool
+	nilEvents     bool
+	lag           lagHistogram
+	skew          skewAlarm
+	idleTrim      idleTrim
+	deferral      deferral
+	arena         arena
+	// Accessed atomically. See SetTracing.
+	tracing uint32
+	// See ScheduleIDAt.
+	ids     map[EventID]*eventNode
+	lastID  EventID
+	settled settledIDs
+}
+
+type bucket struct {
+	eventNodeContainer
+	// The last event in the bucket, so that events for the same or a
+	// later time than every other event in a root bucket, which is
+	// the common case, are appended in O(1).
+	tail  *eventNode
+	count int
+	// Set if the events of a root bucket, which are normally kept
+	// sorted, have been appended out of order, as they are when
+	// cascaded down from the next wheel. The bucket is sorted once it
+	// is needed, so the cost of sorting is spread across the advances
+	// that reach each bucket rather than all borne by the advance
+	// that cascades.
+	unsorted bool
+	// Set for buckets of the root wheel if WithSliceBuckets is used,
+	// in which case the events are in entries[head:] rather than in a
+	// list.
+	sliced  bool
+	entries []sliceEntry
+	head    int
+	// Set for buckets of the root wheel if WithTieBreak is used.
+	tieBreak TieBreak
 }
 
 type eventNodeContainer struct{ *eventNode }
 
 type eventNode struct {
-	at   *time.Time
+	at   int64
 	fun  Event
-	next eventNodeContainer
+	funE EventE
+	// Set for events which reschedule themselves, which is done by
+	// the Timer Wheel invoking them.
+	recurring RecurringEvent
+	chained   ChainedEvent
+	next      eventNodeContainer
+	key       string
+	keyed     bool
+	tag       string
+	group     *Group
+	priority  int
+	handle    *EventHandle
+	// Set for events scheduled by name, so that they can be exported.
+	named *namedEvent
+	ttl   *eventTTL
+	// See WithDeferredScheduling.
+	deferred bool
+	// Set for events scheduled by ScheduleIDAt.
+	id EventID
 }
 
 // Create a new Timer Wheel. The Timer Wheel considers the current
 // time to be the value of startAt. BucketSize should be chosen so
 // that you normally have no more than around 100 events within a
-// bucketSize-duration.
-func NewTimerWheel(startAt time.Time, bucketSize time.Duration) *TimerWheel {
+// bucketSize-duration. Options may be supplied to modify the
+// behaviour of the Timer Wheel.
+func NewTimerWheel(startAt time.Time, bucketSize time.Duration, options ...Option) *TimerWheel {
 	if bucketSize <= 0 {
 		panic("TimerWheel bucket size must be greater than 0")
 	}
+	tw := newTimerWheel(nil, startAt.UnixNano(), int64(bucketSize), ringLength)
+	tw.root = tw
+	tw.loc = startAt.Location()
+	tw.waiters = newTimeWaiters(tw.now)
+	tw.settled.limit = defaultSettledIDs
+	for _, option := range options {
+		option(tw)
+	}
+	if tw.alignment > 0 {
+		tw.start = alignTime(tw.start, tw.alignment)
+	}
+	return tw
+}
+
+func newTimerWheel(root *TimerWheel, startAt int64, bucketSize int64, buckets int) *TimerWheel {
 	return &TimerWheel{
-		ring:       make([]eventNodeContainer, ringLength),
+		ring:       make([]bucket, buckets),
+		root:       root,
 		bucketSize: bucketSize,
 		now:        startAt,
 		start:      startAt,
@@ -54,7 +172,12 @@ func NewTimerWheel(startAt time.Time, bucketSize time.Duration) *TimerWheel {
 
 // Returns the Timer Wheel's current time.
 func (tw *TimerWheel) Now() time.Time {
-	return tw.now
+	return tw.toTime(tw.now)
+}
+
+// Converts from the internal representation of times.
+func (tw *TimerWheel) toTime(ns int64) time.Time {
+	return time.Unix(0, ns).In(tw.root.loc)
 }
 
 // Returns the number of scheduled events in the Timer Wheel.
@@ -62,67 +185,121 @@ func (tw *TimerWheel) Length() int {
 	if tw == nil {
 		return 0
 	}
-	count := 0
-	for _, enContainer := range tw.ring[tw.ringIdx:] {
-		count += enContainer.length()
+	length := tw.levelLength() + tw.next.Length()
+	if tw.root == tw {
+		length += len(tw.overflow.events)
 	}
-	return count + tw.next.Length()
+	return length
 }
 
 // O(1) test on Timer Wheel having scheduled events
 func (tw *TimerWheel) IsEmpty() bool {
-	if tw.next != nil {
+	if tw.next != nil || len(tw.overflow.events) > 0 || tw.cascade.count > 0 {
 		return false
 	}
-	for _, enContainer := range tw.ring[tw.ringIdx:] {
-		if enContainer.eventNode != nil {
+	for idx := tw.ringIdx; idx < len(tw.ring); idx++ {
+		if tw.ring[idx].count > 0 {
 			return false
 		}
 	}
 	return true
 }
 
+// Returns the number of scheduled events held directly in this
+// level of the hierarchy, ignoring any next wheels.
+func (tw *TimerWheel) levelLength() int {
+	count := tw.cascade.count
+	for _, b := range tw.ring[tw.ringIdx:] {
+		count += b.count
+	}
+	return count
+}
+
 // Schedules an event to be invoked at the indicated time. If that
-// time is in the past of the Timer Wheel's current time then the
-// ScheduledInPast error is returned. The event is never invoked at
-// this point, even if the event is scheduled for the exact same time
-// as the Timer Wheel's current time (though it is enqueued).
+// time is in the past of the Timer Wheel's current time then a
+// *ScheduledInPastError, matching ScheduledInPast with errors.Is, is
+// returned (unless another policy has been set with WithPastPolicy).
+// If e is nil then NilEvent is returned (unless WithNilEvents was
+// given). The event is never invoked at this point, even if the event
+// is scheduled for the exact same time as the Timer Wheel's current
+// time (though it is enqueued). Events scheduled for the same time
+// are invoked in the order in which they were scheduled.
 func (tw *TimerWheel) ScheduleEventAt(at time.Time, e Event) error {
-	if at.Before(tw.now) {
-		return ScheduledInPast
-	}
-	idx := int((at.Sub(tw.start)) / tw.bucketSize)
-	if idx >= ringLength {
-		tw.ensureNext()
-		tw.next.scheduleEventAt(at, e)
-	} else {
-		event := &eventNode{at: &at, fun: e}
-		enContainer := &(tw.ring[idx])
-		enContainer.addEvent(event)
-	}
-	return nil
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: at.UnixNano(), fun: e}))
 }
 
 // Schedules an event to be invoked at the current Timer Wheel's time
-// plus the supplied duration.
+// plus the supplied duration (and any jitter: see WithJitter).
 func (tw *TimerWheel) ScheduleEventIn(in time.Duration, e Event) error {
-	return tw.ScheduleEventAt(tw.now.Add(in), e)
+	return tw.ScheduleEventAt(tw.after(in), e)
 }
 
-func (tw *TimerWheel) scheduleEventAt(at time.Time, e Event) {
-	idx := int((at.Sub(tw.start)) / tw.bucketSize)
-	if idx >= ringLength {
-		tw.ensureNext()
-		tw.next.scheduleEventAt(at, e)
-	} else {
-		// We don't care about sorting for non-root timer wheels, so
-		// this gets inserted right at the head, to keep it O(1).
-		enContainer := &(tw.ring[idx])
-		enContainer.eventNode = &eventNode{
-			at:   &at,
-			fun:  e,
-			next: eventNodeContainer{eventNode: enContainer.eventNode},
+func (tw *TimerWheel) scheduleEvent(event *eventNode) error {
+	if tw.closed {
+		return Closed
+	}
+	if tw.refuseNil(event) {
+		return NilEvent
+	}
+	if err := tw.refusePast(event.at); err != nil {
+		return err
+	}
+	if event.at < tw.now {
+		if tw.pastPolicy == PastInvoke {
+			tw.invokePast(event)
+			return nil
 		}
+		event.at = tw.now
+	}
+	if tw.refuseBeyond(event.at) {
+		return TooFarInFuture
+	}
+	if tw.rejectDuplicate(event.tag, event.at) {
+		return DuplicateEvent
+	}
+	// Last, as eviction cannot be undone.
+	if !tw.makeRoom(event.at) {
+		return WheelFull
+	}
+	tw.placeEvent(event)
+	tw.stats.scheduled++
+	if tw.tracingOn() {
+		tw.trace("schedule", event, "", "")
+	}
+	tw.trackDuplicate(event)
+	tw.observeScheduled(event.at)
+	return nil
+}
+
+// Puts an event, which must not be in the past, in the bucket of the
+// root wheel which holds it, or passes it on to the next wheel.
+func (tw *TimerWheel) placeEvent(event *eventNode) {
+	tw.deferIfDue(event)
+	idx := int((event.at - tw.start) / tw.bucketSize)
+	switch {
+	case idx >= len(tw.ring):
+		tw.scheduleBeyond(event)
+	case tw.cascade.head != nil:
+		// Behind the events still being cascaded, to keep the order.
+		tw.cascade.append(event)
+	case tw.approximate:
+		tw.ring[idx].appendEvent(event)
+		tw.noteOccupancy(idx, 1)
+	default:
+		tw.ring[idx].addEvent(event)
+		tw.noteOccupancy(idx, 1)
+	}
+}
+
+func (tw *TimerWheel) scheduleNestedEvent(event *eventNode) {
+	idx := int((event.at - tw.start) / tw.bucketSize)
+	switch {
+	case idx >= len(tw.ring):
+		tw.scheduleBeyond(event)
+	case tw.cascade.head != nil:
+		tw.cascade.append(event)
+	default:
+		tw.ring[idx].pushEvent(event)
 	}
 }
 
@@ -136,125 +313,570 @@ func (tw *TimerWheel) scheduleEventAt(at time.Time, e Event) {
 // maximum of limit events are invoked, at which point the Timer
 // Wheel's current time is set to the time of the most recently
 // invoked event. Returns the number of events invoked.
+//
+// Events may schedule and cancel other events, and call methods such
+// as Length, while they are being invoked. While events are being
+// invoked the Timer Wheel's current time is already the time being
+// advanced to. So an event scheduled by an event for exactly that
+// time is invoked by the same call to AdvanceTo, after every event
+// already due (unless WithDeferredScheduling was given); one
+// scheduled for any earlier time is refused with ScheduledInPast
+// (unless another policy has been set with WithPastPolicy); and one
+// scheduled for any later time is left for a later advance. Length
+// includes the events which are due but not yet invoked, and an
+// event cancelled before its turn is not invoked.
 func (tw *TimerWheel) AdvanceTo(now time.Time, limit int) int {
-	if now.Before(tw.now) {
-		return 0
+	count, _ := tw.advanceTo(now, limitTo(limit))
+	return count
+}
+
+// Returns a stop function for advanceTo which enforces limit, or nil
+// if limit is not positive.
+func limitTo(limit int) func(execCount int) bool {
+	if limit <= 0 {
+		return nil
 	}
-	tw.now = now
+	return func(execCount int) bool { return execCount == limit }
+}
+
+// Like AdvanceTo, but rather than limiting the number of events
+// invoked, stops invoking events once budget of real (wall-clock)
+// time, as measured by the Clock (see WithClock), has elapsed since
+// the call began. At least one event is invoked if any are due. If
+// the budget is exhausted before all due events have been invoked,
+// the Timer Wheel's current time is set to the time of the next due
+// event, so a later call to AdvanceTo or AdvanceToWithin resumes from
+// there. Set budget to 0 to allow all necessary events to be invoked.
+// Returns the number of events invoked.
+func (tw *TimerWheel) AdvanceToWithin(now time.Time, budget time.Duration) int {
+	if budget <= 0 {
+		count, _ := tw.advanceTo(now, nil)
+		return count
+	}
+	clock := tw.wallClock()
+	started := clock.Now()
+	count, _ := tw.advanceTo(now, func(execCount int) bool {
+		return execCount > 0 && clock.Now().Sub(started) >= budget
+	})
+	return count
+}
+
+// If stop is non-nil, it is consulted before each event is invoked
+// with the number of events invoked so far, and returns true if no
+// more events should be invoked. Returns the number of events invoked
+// and whether stop stopped the advance.
+func (tw *TimerWheel) advanceTo(now time.Time, stop func(execCount int) bool) (int, bool) {
+	return tw.advance(now, now.UnixNano(), stop)
+}
+
+// Invokes the events scheduled up to and including due, which is
+// either now or just before it, and moves the current time on to
+// now.
+func (tw *TimerWheel) advance(now time.Time, due int64, stop func(execCount int) bool) (int, bool) {
+	tw.drainIntakes()
+	nowNs := now.UnixNano()
+	if nowNs < tw.now {
+		return 0, false
+	}
+	tw.deferral.release()
+	budget := tw.cascadeBudget
+	if !tw.cascadeWithin(&budget) {
+		tw.advanced(now)
+		return 0, true
+	}
+	previous := tw.now
+	tw.now = nowNs
 	execCount := 0
-	limited := limit > 0
-	bucketStart := tw.start.Add(time.Duration(tw.ringIdx) * tw.bucketSize)
-	if now.Before(bucketStart) {
-		return 0
+	stopped := false
+	// Events are passed a pointer to now, which therefore escapes to
+	// the heap. Copying it only once an event is due means advances
+	// which invoke nothing allocate nothing.
+	var nowPtr *time.Time
+	bucketStart := tw.start + int64(tw.ringIdx)*tw.bucketSize
+	if due < bucketStart {
+		tw.advanced(now)
+		return 0, false
 	}
+	tw.deferral.invoking(true)
 	for {
-		enContainer := &(tw.ring[tw.ringIdx])
-		event := enContainer.eventNode
-		for ; event != nil && !now.Before(*event.at) && (!limited || execCount < limit); event = event.next.eventNode {
-			enContainer.eventNode = event.next.eventNode
+		b := &(tw.ring[tw.ringIdx])
+		if !tw.approximate {
+			b.sort()
+		} else if due < bucketStart+tw.bucketSize-1 {
+			// Not due until the end of the bucket.
+			break
+		}
+		event := b.first()
+		// The event may add or remove events in this bucket, so we
+		// always restart from the head of the bucket.
+		for ; event != nil && event.at <= due && !event.deferred; event = b.first() {
+			if stop != nil && stop(execCount) {
+				stopped = true
+				break
+			}
+			b.popFirst()
 			execCount++
-			event.fun(&now)
+			if nowPtr == nil {
+				nowCopy := now
+				nowPtr = &nowCopy
+			}
+			tw.fire(event, nowPtr)
 		}
 		if event == nil {
-			bucketStart = bucketStart.Add(tw.bucketSize)
-			if !now.Before(bucketStart) {
+			bucketStart += tw.bucketSize
+			if due >= bucketStart {
 				tw.ringIdx++
-				if tw.ringIdx == ringLength {
-					tw.fetchFromNext()
+				if tw.ringIdx == len(tw.ring) {
+					tw.wrap(due)
+					bucketStart = tw.start + int64(tw.ringIdx)*tw.bucketSize
+					if !tw.cascadeWithin(&budget) {
+						tw.now = bucketStart
+						stopped = true
+						break
+					}
 				}
 			} else {
 				break
 			}
 		} else {
-			if limited && limit == execCount {
-				tw.now = *event.at
+			if stopped {
+				tw.now = event.at
+				// With WithApproximateExpiry, the event may be earlier
+				// than the time already reached.
+				if tw.now < previous {
+					tw.now = previous
+				}
 			}
 			break
 		}
 	}
-	return execCount
+	tw.deferral.invoking(false)
+	tw.advanced(now)
+	return execCount, stopped
+}
+
+// Called at the end of every advance, however far it got.
+func (tw *TimerWheel) advanced(now time.Time) {
+	tw.retune()
+	tw.trimIfIdle()
+	tw.notifyAggregate(now)
+	tw.PublishStats()
+	tw.publishRequestedSnapshot()
+	tw.waiters.reach(tw.now)
 }
 
 // Advances the Timer Wheel's current time by the indicated
 // amount. See AdvanceTo for the semantics of the limit parameter and
 // returned value.
-func (tw *TimerWheel) AdvanceBy(interval time.Duration, limit int) {
-	tw.AdvanceTo(tw.now.Add(interval), limit)
+func (tw *TimerWheel) AdvanceBy(interval time.Duration, limit int) int {
+	return tw.AdvanceTo(tw.Now().Add(interval), limit)
+}
+
+// Invokes an event which has already been removed from its bucket.
+func (tw *TimerWheel) fire(event *eventNode, now *time.Time) {
+	tw.forgetKey(event)
+	event.group.forget(event)
+	tw.untrackDuplicate(event)
+	if tw.expire(event, now) {
+		tw.stats.expired++
+		if tw.tracingOn() {
+			tw.trace("expire", event, "lag", time.Duration(now.UnixNano()-event.at).String())
+		}
+		tw.settleID(event, EventExpired)
+		event.handle.settle(EventExpired)
+		tw.retire(event)
+		return
+	}
+	tw.stats.fired++
+	tw.lag.record(now.UnixNano() - event.at)
+	if tw.tracingOn() {
+		tw.trace("fire", event, "lag", time.Duration(now.UnixNano()-event.at).String())
+	}
+	tw.settleID(event, EventFired)
+	event.handle.settle(EventFired)
+	if tw.observer != nil {
+		at := tw.toTime(event.at)
+		tw.observer.OnFired(at, now.Sub(at))
+	}
+	if tw.aggregate.notify != nil {
+		tw.aggregate.add(event)
+	} else {
+		tw.invoke(event, now)
+	}
+	tw.retire(event)
+}
+
+func (tw *TimerWheel) invoke(event *eventNode, now *time.Time) {
+	if tw.deliver(event, now) || tw.dispatch(event, now) {
+		return
+	}
+	if tw.panics.policy == PanicPropagate {
+		tw.call(event, now)
+		return
+	}
+	defer tw.recoverFrom(event)
+	tw.call(event, now)
+}
+
+func (tw *TimerWheel) call(event *eventNode, now *time.Time) {
+	if len(tw.middleware) > 0 {
+		tw.intercept(func(now *time.Time) { tw.callEvent(event, now) })(now)
+		return
+	}
+	tw.callEvent(event, now)
+}
+
+func (tw *TimerWheel) callEvent(event *eventNode, now *time.Time) {
+	switch {
+	case event.funE != nil:
+		if err := event.funE(now); err != nil {
+			tw.errors = append(tw.errors, &EventError{At: tw.toTime(event.at), Err: err})
+		}
+	case event.recurring != nil:
+		tw.recur(event, now)
+	case event.chained != nil:
+		tw.chain(event, now)
+	case event.fun != nil:
+		event.fun(now)
+	}
 }
 
 func (tw *TimerWheel) ensureNext() {
 	if tw.next == nil {
-		ringWidth := time.Duration(tw.bucketSize * ringLength)
-		tw.next = NewTimerWheel(tw.start.Add(ringWidth), ringWidth)
+		ringWidth := tw.ringWidth()
+		tw.next = newTimerWheel(tw.root, tw.start+ringWidth, ringWidth, tw.root.levelBuckets(tw.level()+1))
+		if logger := tw.root.logger; logger != nil {
+			logger.Printf("gotimerwheel: created level %d, covering %v to %v",
+				tw.next.level(), tw.toTime(tw.next.start), tw.toTime(tw.next.start+tw.next.ringWidth()))
+		}
 	}
 }
 
 func (tw *TimerWheel) fetchFromNext() {
 	tw.ringIdx = 0
-	tw.start = tw.start.Add(time.Duration(tw.bucketSize * ringLength))
+	tw.start += tw.ringWidth()
+	tw.fetchFromOverflow()
 	if next := tw.next; next != nil {
-		enContainer := &(next.ring[next.ringIdx])
-		event := enContainer.eventNode
-		enContainer.eventNode = nil
+		// The next wheel's bucket must be complete before it is taken.
+		next.completeCascade()
+		b := &(next.ring[next.ringIdx])
+		// Reverse the bucket back into order of insertion, so that
+		// events for the same time keep their order.
+		last := b.eventNode
+		event := b.reverse()
+		count := b.count
+		*b = bucket{}
+		tw.root.stats.cascades++
+		if observer := tw.root.observer; observer != nil {
+			observer.OnCascade(next.level())
+		}
+		if tw.root.cascadeBudget > 0 {
+			if event != nil {
+				tw.deferCascade(event, last, count)
+			}
+			event = nil
+		} else {
+			count = 0
+		}
 		for event != nil {
 			// We have to capture the next early because addEvent will
 			// rewire event.next.
 			next := event.next.eventNode
 			tw.addEvent(event)
 			event = next
+			count++
+		}
+		if logger := tw.root.logger; logger != nil && count > 0 {
+			logger.Printf("gotimerwheel: cascaded %d events down from level %d", count, next.level())
 		}
 		next.ringIdx++
-		next.now = next.now.Add(next.bucketSize)
-		if next.IsEmpty() {
+		next.now += next.bucketSize
+		// The overflow heap relies on the last level staying put.
+		if next.IsEmpty() && len(tw.root.overflow.events) == 0 {
 			tw.next = nil
-		} else if next.ringIdx == ringLength {
+		} else if next.ringIdx == len(next.ring) {
 			next.fetchFromNext()
 		}
 	}
 }
 
+// Removes a scheduled event so that it will never be
+// invoked. Returns false if the event could not be found.
+func (tw *TimerWheel) cancelEvent(event *eventNode) bool {
+	return tw.cancelEventFor(event, "cancel")
+}
+
+// As cancelEvent, giving the reason for tracing (see SetTracing).
+func (tw *TimerWheel) cancelEventFor(event *eventNode, reason string) bool {
+	if tw.removeEvent(event) {
+		tw.cancelled(event, reason)
+		return true
+	}
+	return false
+}
+
+// Settles an event which has been removed from the hierarchy as
+// cancelled.
+func (tw *TimerWheel) cancelled(event *eventNode, reason string) {
+	if tw.tracingOn() {
+		tw.trace("cancel", event, "reason", reason)
+	}
+	tw.settleID(event, EventCancelled)
+	tw.untrackDuplicate(event)
+	event.group.forget(event)
+	event.handle.settle(EventCancelled)
+	tw.stats.cancelled++
+	tw.retire(event)
+}
+
+// Removes a scheduled event from whichever bucket in the hierarchy
+// holds it. Returns false if the event could not be found.
+func (tw *TimerWheel) removeEvent(event *eventNode) bool {
+	if event.at < tw.start {
+		return false
+	}
+	level, idx := tw.locate(event.at)
+	if level == nil {
+		return tw.overflow.removeEvent(event)
+	}
+	b := &level.ring[idx]
+	if !b.removeEvent(event) && !level.cascade.remove(event) {
+		return false
+	}
+	if level != tw && b.eventNode == nil {
+		tw.dropEmptyLevels()
+	}
+	return true
+}
+
+// Drops next wheels at the end of the hierarchy which no longer hold
+// any events, as fetchFromNext does, so that IsEmpty stays accurate
+// when events are removed.
+func (tw *TimerWheel) dropEmptyLevels() {
+	if len(tw.overflow.events) > 0 {
+		return
+	}
+	inUse := tw
+	for level := tw.next; level != nil; level = level.next {
+		if level.levelLength() > 0 {
+			inUse = level
+		}
+	}
+	inUse.next = nil
+}
+
 func (tw *TimerWheel) addEvent(event *eventNode) {
 	event.next.eventNode = nil
-	idx := int(event.at.Sub(tw.start) / tw.bucketSize)
-	enContainer := &(tw.ring[idx])
-	enContainer.addEvent(event)
+	idx := int((event.at - tw.start) / tw.bucketSize)
+	b := &(tw.ring[idx])
+	if tw.root == tw {
+		b.appendEvent(event)
+		tw.noteOccupancy(idx, 1)
+	} else {
+		b.pushEvent(event)
+	}
 }
 
 func (tw *TimerWheel) String() string {
 	return fmt.Sprintf("{TimerWheel start: %v, now: %v, bucketSize: %v, remainingEvents: %v, next: %v}",
-		tw.start, tw.now, tw.bucketSize, tw.ring[tw.ringIdx:], tw.next)
+		tw.toTime(tw.start), tw.Now(), time.Duration(tw.bucketSize), tw.ring[tw.ringIdx:], tw.next)
 }
 
-func (enContainer *eventNodeContainer) addEvent(event *eventNode) {
+func (b *bucket) addEvent(event *eventNode) {
 	switch {
-	case enContainer.eventNode == nil:
-		enContainer.eventNode = event
-	case event.at.Before(*enContainer.at) || event.at.Equal(*enContainer.at):
-		enContainer.eventNode, event.next = event, *enContainer
+	case b.sliced && b.unsorted:
+		b.appendEntry(event)
+		return
+	case b.sliced:
+		b.insertEntry(event)
+		return
+	case b.unsorted:
+		b.appendEvent(event)
+		return
+	case b.tail == nil:
+		b.eventNode = event
+		b.tail = event
+	case !before(event, b.tail, b.tieBreak):
+		b.tail.next.eventNode = event
+		b.tail = event
 	default:
-		enContainer.next.addEvent(event)
+		b.eventNodeContainer.addEvent(event, b.tieBreak)
 	}
+	b.count++
 }
 
-func (enContainer eventNodeContainer) length() int {
-	if enContainer.eventNode == nil {
-		return 0
+// Returns the first event in the bucket, or nil if it is empty.
+func (b *bucket) first() *eventNode {
+	if b.sliced {
+		if b.head < len(b.entries) {
+			return b.entries[b.head].event
+		}
+		return nil
 	}
-	return 1 + enContainer.eventNode.next.length()
+	return b.eventNode
 }
 
-func (enContainer eventNodeContainer) String() string {
-	str := ""
-	for event := enContainer.eventNode; event != nil; event = event.next.eventNode {
-		str += fmt.Sprintf(", %s", event.String())
+// Removes the first event from the bucket, which must not be empty.
+func (b *bucket) popFirst() {
+	if b.sliced {
+		b.popEntry()
+		return
+	}
+	b.eventNode = b.eventNode.next.eventNode
+	if b.eventNode == nil {
+		b.tail = nil
+	}
+	b.count--
+}
+
+// Calls f for each event in the bucket, in order, until f returns
+// false. Returns false if f did.
+func (b *bucket) each(f func(*eventNode) bool) bool {
+	if b.sliced {
+		for _, entry := range b.live() {
+			if !f(entry.event) {
+				return false
+			}
+		}
+		return true
+	}
+	for event := b.eventNode; event != nil; event = event.next.eventNode {
+		if !f(event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b bucket) String() string {
+	if !b.sliced {
+		return b.eventNodeContainer.String()
+	}
+	var str strings.Builder
+	str.WriteByte('[')
+	for idx, entry := range b.live() {
+		if idx > 0 {
+			str.WriteString(", ")
+		}
+		str.WriteString(entry.event.String())
 	}
-	if len(str) == 0 {
-		return "[]"
+	str.WriteByte(']')
+	return str.String()
+}
+
+// Adds the event to the end of the bucket, noting if it is now
+// unsorted.
+func (b *bucket) appendEvent(event *eventNode) {
+	event.next.eventNode = nil
+	if b.sliced {
+		b.appendEntry(event)
+		return
+	}
+	if b.tail == nil {
+		b.eventNode = event
 	} else {
-		return fmt.Sprintf("[%s]", str[2:])
+		if before(event, b.tail, b.tieBreak) {
+			b.unsorted = true
+		}
+		b.tail.next.eventNode = event
+	}
+	b.tail = event
+	b.count++
+}
+
+// Sorts the bucket if it is unsorted. Events for the same time keep
+// their order.
+func (b *bucket) sort() {
+	if !b.unsorted {
+		return
+	}
+	if b.sliced {
+		b.sortEntries()
+		return
+	}
+	events := make([]*eventNode, 0, b.count)
+	for event := b.eventNode; event != nil; event = event.next.eventNode {
+		events = append(events, event)
+	}
+	sortEvents(events, b.tieBreak)
+	enContainer := &b.eventNodeContainer
+	for _, event := range events {
+		enContainer.eventNode = event
+		enContainer = &event.next
+	}
+	enContainer.eventNode = nil
+	b.tail = nil
+	if len(events) > 0 {
+		b.tail = events[len(events)-1]
+	}
+	b.unsorted = false
+}
+
+// We don't care about sorting for non-root timer wheels, so events
+// get inserted right at the head, to keep it O(1). Consequently,
+// buckets of non-root timer wheels are in reverse order of insertion.
+func (b *bucket) pushEvent(event *eventNode) {
+	if b.eventNode == nil {
+		b.tail = event
+	}
+	event.next.eventNode = b.eventNode
+	b.eventNode = event
+	b.count++
+}
+
+func (b *bucket) removeEvent(event *eventNode) bool {
+	if b.sliced {
+		return b.removeEntry(event)
+	}
+	var prev *eventNode
+	for enContainer := &b.eventNodeContainer; enContainer.eventNode != nil; enContainer = &enContainer.eventNode.next {
+		if enContainer.eventNode == event {
+			enContainer.eventNode = event.next.eventNode
+			if b.tail == event {
+				b.tail = prev
+			}
+			b.count--
+			return true
+		}
+		prev = enContainer.eventNode
+	}
+	return false
+}
+
+// Reverses the list of events, returning the new head.
+func (enContainer eventNodeContainer) reverse() *eventNode {
+	var reversed *eventNode
+	for event := enContainer.eventNode; event != nil; {
+		next := event.next.eventNode
+		event.next.eventNode = reversed
+		reversed, event = event, next
+	}
+	return reversed
+}
+
+// Inserts the event after every event which it is not to be invoked
+// before.
+func (enContainer *eventNodeContainer) addEvent(event *eventNode, tieBreak TieBreak) {
+	for enContainer.eventNode != nil && !before(event, enContainer.eventNode, tieBreak) {
+		enContainer = &enContainer.eventNode.next
+	}
+	event.next.eventNode = enContainer.eventNode
+	enContainer.eventNode = event
+}
+
+func (enContainer eventNodeContainer) String() string {
+	var str strings.Builder
+	str.WriteByte('[')
+	for event := enContainer.eventNode; event != nil; event = event.next.eventNode {
+		if event != enContainer.eventNode {
+			str.WriteString(", ")
+		}
+		str.WriteString(event.String())
 	}
+	str.WriteByte(']')
+	return str.String()
 }
 
 func (e eventNode) String() string {
-	return fmt.Sprintf("{at: %v, event: %v}", e.at, e.fun)
+	return fmt.Sprintf("{at: %v, event: %v}", time.Unix(0, e.at), e.fun)
 }
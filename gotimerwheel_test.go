@@ -1,6 +1,8 @@
 package gotimerwheel
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -30,7 +32,7 @@ func TestAdvance(t *testing.T) {
 
 func TestSchedule(t *testing.T) {
 	start := time.Unix(0, 10)
-	tw := NewTimerWheel(start, 5)
+	tw := NewTimerWheel(start, 5, WithNilEvents())
 	assertNowLength(t, tw, start, 0)
 	// schedule at the current time should add
 	tw.ScheduleEventAt(start, nil)
@@ -146,6 +148,29 @@ func TestExecLimited(t *testing.T) {
 	run.assertExecCount(run.targetExecCount)
 }
 
+func TestExecWithinBudget(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	invoked := 0
+	for idx := int64(1); idx <= 10; idx++ {
+		tw.ScheduleEventIn(time.Duration(idx), func(*time.Time) {
+			invoked++
+			time.Sleep(2 * time.Millisecond)
+		})
+	}
+	end := time.Unix(0, 20)
+	count := tw.AdvanceToWithin(end, 5*time.Millisecond)
+	if count < 1 || count > 4 || count != invoked {
+		t.Fatalf("Expected budget to stop invocation early, but %v of %v invoked", count, invoked)
+	}
+	// progress is recorded so the next call picks up where we stopped
+	assertNowLength(t, tw, time.Unix(0, int64(count+1)), 10-count)
+	if count += tw.AdvanceToWithin(end, 0); count != 10 || invoked != 10 {
+		t.Fatalf("Expected 10 events invoked, but got %v", count)
+	}
+	assertNowLength(t, tw, end, 0)
+}
+
 func assertNowLength(t *testing.T, tw *TimerWheel, then time.Time, length int) {
 	if now := tw.Now(); !now.Equal(then) {
 		t.Errorf("Not equal now: %v vs %v", then, now)
@@ -159,3 +184,335 @@ func assertNowLength(t *testing.T, tw *TimerWheel, then time.Time, length int) {
 		t.Error("Expected not empty")
 	}
 }
+
+func TestFIFO(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	// a few times, spread across the root wheel and several next
+	// wheels, each with many events scheduled in an interleaved order
+	times := []int64{0, 5, 40, 40, 2000, 70000}
+	scheduled := []int{}
+	for idx := 0; idx < 300; idx++ {
+		idx := idx
+		at := time.Unix(0, times[idx%len(times)])
+		tw.ScheduleEventAt(at, func(*time.Time) { scheduled = append(scheduled, idx) })
+	}
+	walked := []time.Time{}
+	tw.ForEach(func(at time.Time, e Event) bool {
+		walked = append(walked, at)
+		return true
+	})
+	tw.AdvanceTo(time.Unix(0, 100000), 0)
+	if len(scheduled) != 300 || len(walked) != 300 {
+		t.Fatalf("Expected 300 events, but invoked %v and walked %v", len(scheduled), len(walked))
+	}
+	for idx := 1; idx < len(scheduled); idx++ {
+		prev, cur := scheduled[idx-1], scheduled[idx]
+		prevAt, curAt := times[prev%len(times)], times[cur%len(times)]
+		if prevAt > curAt || (prevAt == curAt && prev > cur) {
+			t.Fatalf("Event %v (at %v) invoked before event %v (at %v)", prev, prevAt, cur, curAt)
+		}
+		if !walked[idx].Equal(time.Unix(0, curAt)) {
+			t.Fatalf("ForEach order differs from invocation order at %v", idx)
+		}
+	}
+}
+
+func TestBucketTail(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 100)
+	nop := func(*time.Time) {}
+	// appends, inserts and removals of the last event, all in one
+	// bucket
+	for _, ns := range []int64{10, 20, 5, 30, 25} {
+		tw.ScheduleKeyedEventAt(fmt.Sprint(ns), time.Unix(0, ns), nop)
+	}
+	tw.ScheduleEventAt(time.Unix(0, 20), nop)
+	assertTail(t, tw)
+	// cancelling the tail makes the event before it the tail
+	if !tw.CancelKey("30") {
+		t.Fatal("Expected to cancel the event at 30")
+	}
+	assertTail(t, tw)
+	if tail := tw.ring[0].tail; tail == nil || tail.at != 25 {
+		t.Fatalf("Expected the event at 25 to be the tail, but got %v", tail)
+	}
+	// as does cancelling an event in the middle
+	if !tw.CancelKey("10") {
+		t.Fatal("Expected to cancel the event at 10")
+	}
+	assertTail(t, tw)
+	tw.ScheduleEventAt(time.Unix(0, 22), nop)
+	tw.ScheduleEventAt(time.Unix(0, 40), nop)
+	assertTail(t, tw)
+	tw.AdvanceTo(time.Unix(0, 21), 0)
+	assertTail(t, tw)
+	tw.AdvanceTo(time.Unix(0, 99), 0)
+	assertTail(t, tw)
+	// and the bucket works as normal after being emptied
+	tw.ScheduleEventAt(time.Unix(0, 99), nop)
+	assertTail(t, tw)
+	assertNowLength(t, tw, time.Unix(0, 99), 1)
+}
+
+func assertTail(t *testing.T, tw *TimerWheel) {
+	t.Helper()
+	for idx := range tw.ring {
+		b := &tw.ring[idx]
+		var last *eventNode
+		for event := b.eventNode; event != nil; event = event.next.eventNode {
+			last = event
+		}
+		if b.tail != last {
+			t.Fatalf("Bucket %v has tail %v, but its last event is %v", idx, b.tail, last)
+		}
+	}
+}
+
+func BenchmarkScheduleHotBucket(b *testing.B) {
+	start := time.Unix(0, 0)
+	for n := 0; n < b.N; n++ {
+		tw := NewTimerWheel(start, time.Second, WithNilEvents())
+		// all in the same bucket, in order
+		for idx := 0; idx < 10000; idx++ {
+			tw.ScheduleEventAt(start.Add(time.Duration(idx)), nil)
+		}
+	}
+}
+
+func TestHugeBucket(t *testing.T) {
+	const count = 100000
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 100)
+	invoked := 0
+	event := func(*time.Time) { invoked++ }
+	for idx := 0; idx < count; idx++ {
+		tw.ScheduleEventAt(time.Unix(0, 5), event)
+	}
+	tw.ScheduleEventAt(time.Unix(0, 20), event)
+	// after every event at 5 but before the event at 20, so this
+	// insert walks the whole bucket
+	tw.ScheduleEventAt(time.Unix(0, 10), event)
+	assertNowLength(t, tw, start, count+2)
+	assertTail(t, tw)
+	if str := tw.String(); len(str) < count {
+		t.Errorf("Expected String to include every event, but got %v bytes", len(str))
+	}
+	last := time.Unix(0, 0)
+	tw.ForEach(func(at time.Time, e Event) bool {
+		if at.Before(last) {
+			t.Fatalf("Events out of order: %v after %v", at, last)
+		}
+		last = at
+		return true
+	})
+	if !last.Equal(time.Unix(0, 20)) {
+		t.Errorf("Expected the event at 20 to be last, but got %v", last)
+	}
+	tw.AdvanceTo(time.Unix(0, 20), 0)
+	if invoked != count+2 {
+		t.Errorf("Expected %v events invoked, but got %v", count+2, invoked)
+	}
+	assertNowLength(t, tw, time.Unix(0, 20), 0)
+}
+
+func TestTimesKeepLocation(t *testing.T) {
+	loc := time.FixedZone("test", 3600)
+	start := time.Unix(0, 0).In(loc)
+	tw := NewTimerWheel(start, 5)
+	var invokedAt time.Time
+	tw.ScheduleEventAt(start.Add(12), func(now *time.Time) { invokedAt = *now })
+	if at, found := tw.NextEventAt(); !found || at.Location() != loc || !at.Equal(start.Add(12)) {
+		t.Errorf("Expected next event at %v, but got %v (%v)", start.Add(12), at, found)
+	}
+	tw.AdvanceBy(20, 0)
+	if now := tw.Now(); now.Location() != loc || !now.Equal(start.Add(20)) {
+		t.Errorf("Expected now to be %v, but got %v", start.Add(20), now)
+	}
+	if !invokedAt.Equal(start.Add(20)) {
+		t.Errorf("Expected event to be invoked with %v, but got %v", start.Add(20), invokedAt)
+	}
+}
+
+// Events cascaded from the next wheel are appended to the root
+// wheel's buckets, which are only sorted when they are reached.
+func TestLazyCascade(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 10)
+	invoked := []int64{}
+	// all beyond the root wheel, and in reverse order so that they
+	// are cascaded out of order
+	for at := int64(2*10*ringLength - 1); at >= 10*ringLength; at-- {
+		at := at
+		tw.ScheduleEventAt(time.Unix(0, at), func(*time.Time) { invoked = append(invoked, at) })
+	}
+	tw.ScheduleEventAt(time.Unix(0, 10*ringLength+5), func(*time.Time) { invoked = append(invoked, -1) })
+	tw.AdvanceTo(time.Unix(0, 10*ringLength-1), 0)
+	tw.AdvanceTo(time.Unix(0, 10*ringLength), 0)
+	unsorted := 0
+	for idx := range tw.ring {
+		if tw.ring[idx].unsorted {
+			unsorted++
+		}
+	}
+	if unsorted != ringLength-1 {
+		t.Errorf("Expected every bucket but the first to be left unsorted, but got %v", unsorted)
+	}
+	assertTail(t, tw)
+	// scheduled into an unsorted bucket
+	tw.ScheduleEventAt(time.Unix(0, 10*ringLength+15), func(*time.Time) { invoked = append(invoked, -2) })
+	tw.AdvanceTo(time.Unix(0, 2*10*ringLength), 0)
+	if len(invoked) != 10*ringLength+2 {
+		t.Fatalf("Expected %v events invoked, but got %v", 10*ringLength+2, len(invoked))
+	}
+	last := int64(0)
+	for _, at := range invoked {
+		switch at {
+		case -1:
+			if last != 10*ringLength+5 {
+				t.Errorf("Expected the FIFO event at %v, but got it after %v", 10*ringLength+5, last)
+			}
+		case -2:
+			if last != 10*ringLength+15 {
+				t.Errorf("Expected the late event at %v, but got it after %v", 10*ringLength+15, last)
+			}
+		default:
+			if at < last {
+				t.Fatalf("Events out of order: %v after %v", at, last)
+			}
+			last = at
+		}
+	}
+}
+
+// Event times are held by value, so scheduling allocates nothing but
+// the event's node.
+func TestScheduleAllocs(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	at := time.Unix(0, 0).Add(5 * time.Millisecond)
+	event := func(*time.Time) {}
+	if allocs := testing.AllocsPerRun(1000, func() { tw.ScheduleEventAt(at, event) }); allocs != 1 {
+		t.Errorf("Expected 1 allocation per ScheduleEventAt, but got %v", allocs)
+	}
+}
+
+// Invoking events allocates nothing but the copy of now that they are
+// passed, once per advance.
+func TestFireAllocs(t *testing.T) {
+	const count = 100
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	event := func(*time.Time) {}
+	at := start
+	allocs := testing.AllocsPerRun(100, func() {
+		// within the root wheel, which would otherwise grow a next
+		// wheel
+		for idx := 0; idx < count; idx++ {
+			tw.ScheduleEventAt(at.Add(time.Duration(idx)), event)
+		}
+		at = at.Add(time.Millisecond)
+		tw.AdvanceTo(at, 0)
+	})
+	if allocs != count+1 {
+		t.Errorf("Expected %v allocations, but got %v", count+1, allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { tw.AdvanceBy(time.Second, 0) }); allocs != 0 {
+		t.Errorf("Expected advancing without invoking events not to allocate, but got %v", allocs)
+	}
+}
+
+func BenchmarkScheduleEventAt(b *testing.B) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	event := func(*time.Time) {}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		tw.ScheduleEventAt(start.Add(time.Duration(n)*time.Microsecond), event)
+	}
+}
+
+func BenchmarkFire(b *testing.B) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	event := func(*time.Time) {}
+	for n := 0; n < b.N; n++ {
+		tw.ScheduleEventAt(start.Add(time.Duration(n)*time.Microsecond), event)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	tw.AdvanceBy(time.Duration(b.N)*time.Microsecond, 0)
+}
+
+// Every event starts two levels up, so is cascaded twice before it is
+// invoked.
+func BenchmarkCascade(b *testing.B) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	event := func(*time.Time) {}
+	offset := time.Duration(ringLength*ringLength) * time.Millisecond
+	for n := 0; n < b.N; n++ {
+		tw.ScheduleEventAt(start.Add(offset+time.Duration(n)*time.Microsecond), event)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	tw.AdvanceBy(offset+time.Duration(b.N)*time.Microsecond, 0)
+}
+
+func BenchmarkScheduleFarFuture(b *testing.B) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	event := func(*time.Time) {}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		tw.ScheduleEventAt(start.Add(365*24*time.Hour+time.Duration(n)*time.Microsecond), event)
+	}
+}
+
+// Events scheduled and cancelled by events being invoked, as
+// specified by AdvanceTo.
+func TestReentrantScheduling(t *testing.T) {
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}} {
+		start := time.Unix(0, 0)
+		tw := NewTimerWheel(start, time.Millisecond, options...)
+		target := start.Add(time.Second)
+		var invoked []string
+		record := func(name string) Event { return func(*time.Time) { invoked = append(invoked, name) } }
+		var cancelled *eventNode
+		tw.ScheduleEventAt(start.Add(time.Millisecond), func(now *time.Time) {
+			invoked = append(invoked, "first")
+			if !tw.Now().Equal(target) || !now.Equal(target) {
+				t.Errorf("Expected the current time to be %v, but got %v", target, tw.Now())
+			}
+			if tw.Length() != 3 {
+				t.Errorf("Expected the due events to be counted, but got %v", tw.Length())
+			}
+			if err := tw.ScheduleEventAt(start.Add(2*time.Millisecond), record("past")); !errors.Is(err, ScheduledInPast) {
+				t.Errorf("Expected ScheduledInPast, but got %v", err)
+			}
+			tw.ScheduleEventAt(target, record("target"))
+			tw.ScheduleEventAt(start.Add(time.Millisecond), record("past")) // refused
+			tw.ScheduleEventAt(target.Add(time.Nanosecond), record("later"))
+			tw.cancelEvent(cancelled)
+		})
+		tw.ScheduleEventAt(start.Add(time.Millisecond), record("same bucket"))
+		cancelled = &eventNode{at: start.Add(500 * time.Millisecond).UnixNano(), fun: record("cancelled")}
+		tw.scheduleEvent(cancelled)
+		tw.ScheduleEventAt(target, record("due at target"))
+
+		if count := tw.AdvanceTo(target, 0); count != 4 {
+			t.Errorf("Expected 4 events to be invoked, but got %v", count)
+		}
+		expected := []string{"first", "same bucket", "due at target", "target"}
+		if len(invoked) != len(expected) {
+			t.Fatalf("Expected %v, but got %v", expected, invoked)
+		}
+		for idx := range expected {
+			if invoked[idx] != expected[idx] {
+				t.Fatalf("Expected %v, but got %v", expected, invoked)
+			}
+		}
+		if tw.Length() != 1 || tw.AdvanceBy(time.Nanosecond, 0) != 1 {
+			t.Error("Expected the later event to be left for the next advance")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package gotimerwheel
+
+import (
+	"sort"
+	"time"
+)
+
+// A Group is a set of events in a Timer Wheel which can be cancelled
+// or drained together, such as every timer belonging to one
+// connection or one tenant. See NewGroup.
+type Group struct {
+	tw *TimerWheel
+	// The group's events waiting to be invoked, with the order in
+	// which they were scheduled.
+	members map[*eventNode]uint64
+	seq     uint64
+}
+
+// Creates a new, empty Group of events in the Timer Wheel. Like a
+// DeadlineTree, a Group loses track of its events if they are removed
+// from the Timer Wheel by other means, such as Clear or Merge.
+func (tw *TimerWheel) NewGroup() *Group {
+	return &Group{tw: tw, members: make(map[*eventNode]uint64)}
+}
+
+// Schedules an event in the Group to be invoked at the indicated
+// time. Otherwise, this is just the same as ScheduleEventAt.
+func (g *Group) ScheduleEventAt(at time.Time, e Event) error {
+	event := g.tw.newEvent(eventNode{at: at.UnixNano(), fun: e, group: g})
+	g.members[event] = g.seq
+	g.seq++
+	if err := g.tw.scheduleEvent(event); err != nil {
+		g.forget(event)
+		return err
+	}
+	return nil
+}
+
+// Schedules an event in the Group to be invoked at the Timer Wheel's
+// current time plus the supplied duration. See ScheduleEventAt.
+func (g *Group) ScheduleEventIn(in time.Duration, e Event) error {
+	return g.ScheduleEventAt(g.tw.after(in), e)
+}
+
+// Returns the number of the Group's events waiting to be invoked.
+func (g *Group) Length() int {
+	return len(g.members)
+}
+
+func (g *Group) forget(event *eventNode) {
+	if g != nil {
+		delete(g.members, event)
+	}
+}
+
+// Returns the Group's events in the order in which they would be
+// invoked.
+func (g *Group) sorted() []*eventNode {
+	events := make([]*eventNode, 0, len(g.members))
+	for event := range g.members {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(a, b int) bool {
+		ea, eb := events[a], events[b]
+		if before(ea, eb, g.tw.tieBreak) {
+			return true
+		}
+		if before(eb, ea, g.tw.tieBreak) {
+			return false
+		}
+		return g.members[events[a]] < g.members[events[b]]
+	})
+	return events
+}
+
+// Calls f for every one of the Group's events waiting to be invoked,
+// in the order in which they would be invoked, without invoking any
+// of them. Iteration stops early if f returns false. F must not
+// schedule or cancel events in the Timer Wheel.
+func (g *Group) ForEach(f func(at time.Time, e Event) bool) {
+	for _, event := range g.sorted() {
+		if !f(g.tw.toTime(event.at), event.fun) {
+			return
+		}
+	}
+}
+
+// Cancels every one of the Group's events, so that they will never
+// be invoked. Returns the number of events cancelled. The Group can
+// still be used to schedule more events.
+func (g *Group) Cancel() int {
+	count := 0
+	for event := range g.members {
+		if g.tw.cancelEvent(event) {
+			count++
+		}
+	}
+	g.members = make(map[*eventNode]uint64)
+	return count
+}
+
+// Invokes every one of the Group's events immediately, regardless of
+// the time they are scheduled for, in time order, just as Drain does
+// for a whole Timer Wheel. Events scheduled in the Group by the
+// events being drained are not themselves drained. Returns the number
+// of events invoked.
+func (g *Group) Drain() int {
+	count := 0
+	for _, event := range g.sorted() {
+		// An earlier event may have cancelled this one.
+		if !g.tw.removeEvent(event) {
+			continue
+		}
+		count++
+		at := g.tw.toTime(event.at)
+		g.tw.fire(event, &at)
+	}
+	g.tw.notifyAggregate(g.tw.Now())
+	return count
+}
@@ -0,0 +1,50 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithMaxLateness(time.Second, nil), WithNilEvents())
+	conn, tenant := tw.NewGroup(), tw.NewGroup()
+	var invoked []int
+	record := func(id int) Event { return func(*time.Time) { invoked = append(invoked, id) } }
+	for idx := 0; idx < 1000; idx++ {
+		conn.ScheduleEventIn(time.Duration(idx%50)*time.Hour, nil)
+	}
+	tenant.ScheduleEventIn(time.Hour, record(2))
+	tenant.ScheduleEventIn(time.Millisecond, record(0))
+	tenant.ScheduleEventIn(time.Hour, record(3))
+	tenant.ScheduleEventIn(time.Second, record(1))
+	tenant.ScheduleEventIn(2*time.Hour, record(4))
+	tw.ScheduleEventIn(time.Millisecond, record(-1))
+	if conn.Length() != 1000 || tenant.Length() != 5 || tw.Length() != 1006 {
+		t.Fatalf("Unexpected lengths %v, %v, %v", conn.Length(), tenant.Length(), tw.Length())
+	}
+
+	if count := conn.Cancel(); count != 1000 || conn.Length() != 0 || tw.Length() != 6 {
+		t.Errorf("Expected to cancel the whole group, but got %v, leaving %v", count, tw.Length())
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	if tenant.Length() != 4 {
+		t.Errorf("Expected the invoked event to leave the group, but got %v", tenant.Length())
+	}
+	invoked = nil
+	if count := tenant.Drain(); count != 4 || tenant.Length() != 0 || !tw.IsEmpty() {
+		t.Errorf("Expected to drain the whole group, but got %v, leaving %v", count, tw.Length())
+	}
+	for idx, id := range invoked {
+		if id != idx+1 {
+			t.Fatalf("Expected the group to be drained in order, but got %v", invoked)
+		}
+	}
+
+	// expired events leave the group too
+	tenant.ScheduleEventIn(time.Millisecond, record(5))
+	tw.AdvanceBy(time.Hour, 0)
+	if tenant.Length() != 0 || tenant.Cancel() != 0 {
+		t.Errorf("Expected the expired event to leave the group, but got %v", tenant.Length())
+	}
+}
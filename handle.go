@@ -0,0 +1,98 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// The state of an event scheduled with an EventHandle.
+type EventState int
+
+const (
+	// The event is waiting to be invoked.
+	EventPending EventState = iota
+	// The event has been invoked.
+	EventFired
+	// The event was cancelled, or removed by Clear, Reset or Close,
+	// before it could be invoked.
+	EventCancelled
+	// The event was too late to be invoked (see WithMaxLateness).
+	EventExpired
+)
+
+func (es EventState) String() string {
+	switch es {
+	case EventPending:
+		return "Pending"
+	case EventFired:
+		return "Fired"
+	case EventCancelled:
+		return "Cancelled"
+	case EventExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// An EventHandle tracks what became of a single scheduled event. See
+// ScheduleHandleAt. Once its event has fired, been cancelled or been
+// removed by Clear, Reset or Close, Cancel returns false however often
+// it is called. An EventHandle must only be used by the goroutine
+// driving its Timer Wheel: other goroutines can use it through
+// Intake.Do.
+type EventHandle struct {
+	tw    *TimerWheel
+	event *eventNode
+	state EventState
+	// The Timer Wheel's count of Clears when the event was scheduled.
+	clears uint64
+}
+
+// Schedules an event to be invoked at the indicated time, returning
+// an EventHandle with which to find out whether it has been invoked,
+// or to cancel it. Otherwise, this is just the same as
+// ScheduleEventAt. Like a DeadlineTree, an EventHandle loses track of
+// its event if the event is moved to another Timer Wheel by Merge.
+func (tw *TimerWheel) ScheduleHandleAt(at time.Time, e Event) (*EventHandle, error) {
+	h := &EventHandle{tw: tw, clears: tw.clears}
+	h.event = tw.newEvent(eventNode{at: at.UnixNano(), fun: e, handle: h})
+	if err := tw.scheduleEvent(h.event); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Schedules an event to be invoked at the current Timer Wheel's time
+// plus the supplied duration. See ScheduleHandleAt.
+func (tw *TimerWheel) ScheduleHandleIn(in time.Duration, e Event) (*EventHandle, error) {
+	return tw.ScheduleHandleAt(tw.after(in), e)
+}
+
+func (h *EventHandle) settle(state EventState) {
+	if h != nil {
+		h.state = state
+	}
+}
+
+// Returns the state of the event. An event is Fired from just before
+// it is invoked, so it is already Fired while it runs.
+func (h *EventHandle) State() EventState {
+	if h.state == EventPending && h.clears != h.tw.clears {
+		return EventCancelled
+	}
+	return h.state
+}
+
+// Returns the time the event is scheduled for.
+func (h *EventHandle) At() time.Time {
+	return h.tw.toTime(h.event.at)
+}
+
+// Cancels the event, so that it will never be invoked. Returns true
+// if the event was waiting to be invoked.
+func (h *EventHandle) Cancel() bool {
+	if h.State() != EventPending {
+		return false
+	}
+	return h.tw.cancelEvent(h.event)
+}
@@ -0,0 +1,58 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventHandle(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithMaxLateness(time.Second, nil), WithNilEvents())
+	var during EventState
+	var fired *EventHandle
+	fired, _ = tw.ScheduleHandleIn(time.Millisecond, func(*time.Time) { during = fired.State() })
+	cancelled, _ := tw.ScheduleHandleIn(time.Millisecond, nil)
+	expired, _ := tw.ScheduleHandleIn(2*time.Millisecond, nil)
+	cleared, _ := tw.ScheduleHandleIn(time.Hour, nil)
+	if !cleared.At().Equal(start.Add(time.Hour)) || cleared.State() != EventPending {
+		t.Errorf("Unexpected handle %v, %v", cleared.At(), cleared.State())
+	}
+	if !cancelled.Cancel() || cancelled.Cancel() {
+		t.Error("Expected Cancel to cancel the event once")
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.AdvanceBy(2*time.Second, 0)
+	tw.Clear()
+	for _, check := range []struct {
+		handle   *EventHandle
+		expected EventState
+	}{
+		{fired, EventFired},
+		{cancelled, EventCancelled},
+		{expired, EventExpired},
+		{cleared, EventCancelled},
+	} {
+		if state := check.handle.State(); state != check.expected {
+			t.Errorf("Expected %v, but got %v", check.expected, state)
+		}
+	}
+	if during != EventFired {
+		t.Errorf("Expected the event to be Fired while it runs, but got %v", during)
+	}
+	if cleared.Cancel() {
+		t.Error("Expected Cancel of a cleared event to return false")
+	}
+	if _, err := tw.ScheduleHandleAt(start, nil); err == nil {
+		t.Error("Expected scheduling in the past to be refused")
+	}
+}
+
+func TestEventHandleClone(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	h, _ := tw.ScheduleHandleIn(time.Millisecond, func(*time.Time) {})
+	clone := tw.Clone()
+	clone.AdvanceBy(time.Millisecond, 0)
+	if h.State() != EventPending {
+		t.Errorf("Expected the clone's event not to affect the handle, but got %v", h.State())
+	}
+}
@@ -0,0 +1,262 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A HashedWheel is an alternative to the hierarchical Timer Wheel: a
+// single ring of slots, in the style of Netty's HashedWheelTimer.
+// Events beyond the end of the ring are not held in nested wheels
+// but stay in their slot with a count of the remaining rounds of the
+// ring to go before they are due. This avoids ever moving events
+// between wheels, at the cost of every visit to a slot touching the
+// far-future events in it, so it suits workloads with many far-future
+// events which are mostly cancelled or replaced, such as idle
+// timeouts. Cancelling (see ScheduleHandleAt) is O(1): a cancelled
+// event is dropped from its slot the next time the slot is visited.
+type HashedWheel struct {
+	slots    [][]*hashedEvent
+	tick     int64
+	tickSize int64
+	start    int64
+	now      int64
+	loc      *time.Location
+	length   int
+	seq      uint64
+	// Reused between visits to slots.
+	due []*hashedEvent
+}
+
+type hashedEvent struct {
+	at     int64
+	rounds int64
+	seq    uint64
+	fun    Event
+	// Set once the event has been invoked or cancelled.
+	done bool
+}
+
+// A HashedHandle cancels a single event scheduled in a HashedWheel.
+// See ScheduleHandleAt.
+type HashedHandle struct {
+	hw    *HashedWheel
+	event *hashedEvent
+}
+
+// Creates a new HashedWheel with the indicated number of slots, each
+// covering tickSize. The HashedWheel considers the current time to
+// be startAt. Slots times tickSize should be chosen to cover most of
+// the events you expect to schedule.
+func NewHashedWheel(startAt time.Time, tickSize time.Duration, slots int) *HashedWheel {
+	if tickSize <= 0 {
+		panic("HashedWheel tick size must be greater than 0")
+	}
+	if slots <= 0 {
+		panic("HashedWheel must have at least 1 slot")
+	}
+	return &HashedWheel{
+		slots:    make([][]*hashedEvent, slots),
+		tickSize: int64(tickSize),
+		start:    startAt.UnixNano(),
+		now:      startAt.UnixNano(),
+		loc:      startAt.Location(),
+	}
+}
+
+// Returns the HashedWheel's current time.
+func (hw *HashedWheel) Now() time.Time {
+	return time.Unix(0, hw.now).In(hw.loc)
+}
+
+// Returns the number of scheduled events in the HashedWheel.
+func (hw *HashedWheel) Length() int {
+	return hw.length
+}
+
+// O(1) test on HashedWheel having scheduled events
+func (hw *HashedWheel) IsEmpty() bool {
+	return hw.length == 0
+}
+
+// Schedules an event to be invoked at the indicated time. See
+// TimerWheel.ScheduleEventAt: the same rules about the past and about
+// events for the same time apply, and a nil e is refused with
+// NilEvent.
+func (hw *HashedWheel) ScheduleEventAt(at time.Time, e Event) error {
+	_, err := hw.schedule(at, e)
+	return err
+}
+
+// Schedules an event to be invoked at the indicated time, returning a
+// HashedHandle with which to cancel it. Otherwise, this is just the
+// same as ScheduleEventAt.
+func (hw *HashedWheel) ScheduleHandleAt(at time.Time, e Event) (*HashedHandle, error) {
+	event, err := hw.schedule(at, e)
+	if err != nil {
+		return nil, err
+	}
+	return &HashedHandle{hw: hw, event: event}, nil
+}
+
+// Schedules an event to be invoked at the HashedWheel's current time
+// plus the supplied duration. See ScheduleHandleAt.
+func (hw *HashedWheel) ScheduleHandleIn(in time.Duration, e Event) (*HashedHandle, error) {
+	return hw.ScheduleHandleAt(hw.Now().Add(in), e)
+}
+
+func (hw *HashedWheel) schedule(at time.Time, e Event) (*hashedEvent, error) {
+	if e == nil {
+		return nil, NilEvent
+	}
+	ns := at.UnixNano()
+	if ns < hw.now {
+		return nil, &ScheduledInPastError{At: at, Now: hw.Now()}
+	}
+	tick := (ns - hw.start) / hw.tickSize
+	slots := int64(len(hw.slots))
+	event := &hashedEvent{at: ns, rounds: (tick - hw.tick) / slots, seq: hw.seq, fun: e}
+	hw.seq++
+	slot := &hw.slots[tick%slots]
+	*slot = append(*slot, event)
+	hw.length++
+	return event, nil
+}
+
+// Cancels the event, so that it will never be invoked. Returns true
+// if the event was waiting to be invoked.
+func (h *HashedHandle) Cancel() bool {
+	if h.event.done {
+		return false
+	}
+	h.event.done = true
+	// Not kept alive until the slot is next visited.
+	h.event.fun = nil
+	h.hw.length--
+	return true
+}
+
+// Schedules an event to be invoked at the HashedWheel's current time
+// plus the supplied duration.
+func (hw *HashedWheel) ScheduleEventIn(in time.Duration, e Event) error {
+	return hw.ScheduleEventAt(hw.Now().Add(in), e)
+}
+
+// Advances the HashedWheel's current time to the indicated time,
+// invoking every event scheduled up to and including it. See
+// TimerWheel.AdvanceTo for the semantics of limit and the returned
+// value.
+func (hw *HashedWheel) AdvanceTo(now time.Time, limit int) int {
+	nowNs := now.UnixNano()
+	if nowNs < hw.now {
+		return 0
+	}
+	hw.now = nowNs
+	target := (nowNs - hw.start) / hw.tickSize
+	slots := int64(len(hw.slots))
+	execCount := 0
+	for {
+		if hw.length == 0 {
+			hw.tick = target
+			return execCount
+		}
+		if hw.tick%slots == 0 && target-hw.tick >= slots {
+			hw.skipRounds((target - hw.tick) / slots)
+		}
+		slot := &hw.slots[hw.tick%slots]
+		// Events invoked may schedule more events for now, so keep
+		// visiting the slot until nothing more is due.
+		for {
+			due := hw.takeDue(slot, nowNs)
+			if len(due) == 0 {
+				break
+			}
+			for idx, event := range due {
+				// Cancelled by an event invoked before it.
+				if event.done {
+					continue
+				}
+				if limit > 0 && execCount == limit {
+					*slot = append(*slot, due[idx:]...)
+					hw.now = event.at
+					return execCount
+				}
+				hw.length--
+				execCount++
+				event.done = true
+				event.fun(&now)
+			}
+		}
+		if hw.tick == target {
+			return execCount
+		}
+		for _, event := range *slot {
+			event.rounds--
+		}
+		hw.tick++
+	}
+}
+
+// If no event is due within the next round of the ring, skips
+// forwards by as many whole rounds as possible, up to max, rather
+// than visiting every slot of every round.
+func (hw *HashedWheel) skipRounds(max int64) {
+	skip := max
+	for _, slot := range hw.slots {
+		for _, event := range slot {
+			if !event.done && event.rounds < skip {
+				skip = event.rounds
+			}
+		}
+	}
+	if skip <= 0 {
+		return
+	}
+	for _, slot := range hw.slots {
+		for _, event := range slot {
+			event.rounds -= skip
+		}
+	}
+	hw.tick += skip * int64(len(hw.slots))
+}
+
+// Removes from the slot the events which are due for the current
+// tick by now, returning them in the order in which they should be
+// invoked, and drops any which have been cancelled.
+func (hw *HashedWheel) takeDue(slot *[]*hashedEvent, now int64) []*hashedEvent {
+	due := hw.due[:0]
+	kept := (*slot)[:0]
+	for _, event := range *slot {
+		switch {
+		case event.done:
+		case event.rounds == 0 && event.at <= now:
+			due = append(due, event)
+		default:
+			kept = append(kept, event)
+		}
+	}
+	for idx := len(kept); idx < len(*slot); idx++ {
+		(*slot)[idx] = nil
+	}
+	*slot = kept
+	sort.Slice(due, func(a, b int) bool {
+		if due[a].at != due[b].at {
+			return due[a].at < due[b].at
+		}
+		return due[a].seq < due[b].seq
+	})
+	hw.due = due
+	return due
+}
+
+// Advances the HashedWheel's current time by the indicated amount.
+// See AdvanceTo.
+func (hw *HashedWheel) AdvanceBy(interval time.Duration, limit int) int {
+	return hw.AdvanceTo(hw.Now().Add(interval), limit)
+}
+
+func (hw *HashedWheel) String() string {
+	return fmt.Sprintf("{HashedWheel now: %v, tickSize: %v, slots: %v, length: %v}",
+		hw.Now(), time.Duration(hw.tickSize), len(hw.slots), hw.length)
+}
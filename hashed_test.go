@@ -0,0 +1,133 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestHashedWheel(t *testing.T) {
+	start := time.Unix(0, 0)
+	hw := NewHashedWheel(start, 10, 4)
+	var invoked []int
+	schedule := func(at int64, id int) {
+		if err := hw.ScheduleEventAt(time.Unix(0, at), func(*time.Time) { invoked = append(invoked, id) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 95 and 15 share a slot, 95 being two rounds further out
+	schedule(95, 0)
+	schedule(15, 1)
+	schedule(15, 2)
+	schedule(12, 3)
+	if err := hw.ScheduleEventAt(time.Unix(0, -1), func(*time.Time) {}); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	if count := hw.AdvanceTo(time.Unix(0, 94), 0); count != 3 || hw.Length() != 1 {
+		t.Errorf("Expected 3 events invoked leaving 1, but got %v leaving %v", count, hw.Length())
+	}
+	if count := hw.AdvanceTo(time.Unix(0, 95), 0); count != 1 || !hw.IsEmpty() {
+		t.Errorf("Expected 1 event invoked leaving none, but got %v leaving %v", count, hw.Length())
+	}
+	expected := []int{3, 1, 2, 0}
+	for idx, id := range expected {
+		if invoked[idx] != id {
+			t.Fatalf("Expected invocation order %v, but got %v", expected, invoked)
+		}
+	}
+	if !hw.Now().Equal(time.Unix(0, 95)) {
+		t.Errorf("Expected now to be 95, but got %v", hw.Now())
+	}
+}
+
+func TestHashedWheelLimit(t *testing.T) {
+	hw := NewHashedWheel(time.Unix(0, 0), 10, 4)
+	invoked := 0
+	for at := int64(0); at < 100; at += 5 {
+		hw.ScheduleEventAt(time.Unix(0, at), func(*time.Time) { invoked++ })
+	}
+	if count := hw.AdvanceTo(time.Unix(0, 200), 3); count != 3 || !hw.Now().Equal(time.Unix(0, 15)) {
+		t.Errorf("Expected 3 events invoked and now 15, but got %v and %v", count, hw.Now())
+	}
+	if count := hw.AdvanceTo(time.Unix(0, 200), 0); count != 17 || invoked != 20 || !hw.IsEmpty() {
+		t.Errorf("Expected 17 more events invoked, but got %v (%v in total)", count, invoked)
+	}
+}
+
+// Checks the HashedWheel invokes the same events in the same order
+// as a TimerWheel.
+func TestHashedWheelMatchesTimerWheel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	start := time.Unix(0, 0)
+	hw := NewHashedWheel(start, 7, 16)
+	tw := NewTimerWheel(start, 7)
+	var hwInvoked, twInvoked []int
+	now := int64(0)
+	id := 0
+	for step := 0; step < 200; step++ {
+		for count := rng.Intn(10); count > 0; count-- {
+			at := time.Unix(0, now+rng.Int63n(2000))
+			eventID := id
+			id++
+			hw.ScheduleEventAt(at, func(*time.Time) { hwInvoked = append(hwInvoked, eventID) })
+			tw.ScheduleEventAt(at, func(*time.Time) { twInvoked = append(twInvoked, eventID) })
+		}
+		now += rng.Int63n(100)
+		if hwCount, twCount := hw.AdvanceTo(time.Unix(0, now), 0), tw.AdvanceTo(time.Unix(0, now), 0); hwCount != twCount {
+			t.Fatalf("At %v, HashedWheel invoked %v events but TimerWheel %v", now, hwCount, twCount)
+		}
+	}
+	hw.AdvanceBy(time.Hour, 0)
+	tw.AdvanceBy(time.Hour, 0)
+	if len(hwInvoked) != id || len(twInvoked) != id {
+		t.Fatalf("Expected %v invocations, but got %v and %v", id, len(hwInvoked), len(twInvoked))
+	}
+	for idx := range hwInvoked {
+		if hwInvoked[idx] != twInvoked[idx] {
+			t.Fatalf("Invocation %v differs: %v vs %v", idx, hwInvoked[idx], twInvoked[idx])
+		}
+	}
+}
+
+func TestHashedWheelCancel(t *testing.T) {
+	hw := NewHashedWheel(time.Unix(0, 0), 10, 4)
+	var invoked []int
+	handles := make([]*HashedHandle, 6)
+	for idx := range handles {
+		idx := idx
+		handles[idx], _ = hw.ScheduleHandleIn(time.Duration(idx%3*100+5), func(*time.Time) {
+			invoked = append(invoked, idx)
+			if idx == 0 {
+				// cancels an event due in the same tick
+				handles[3].Cancel()
+			}
+		})
+	}
+	if !handles[1].Cancel() || handles[1].Cancel() || hw.Length() != 5 {
+		t.Errorf("Expected Cancel to cancel the event once, leaving 5, but got %v", hw.Length())
+	}
+	// the far-future event is dropped from its slot once visited
+	handles[5].Cancel()
+	if count := hw.AdvanceTo(time.Unix(0, 1000), 0); count != 3 || !hw.IsEmpty() {
+		t.Errorf("Expected 3 events invoked leaving none, but got %v leaving %v", count, hw.Length())
+	}
+	expected := []int{0, 4, 2}
+	if len(invoked) != len(expected) || invoked[0] != 0 || invoked[1] != 4 || invoked[2] != 2 {
+		t.Errorf("Expected %v, but got %v", expected, invoked)
+	}
+	for _, slot := range hw.slots {
+		if len(slot) != 0 {
+			t.Errorf("Expected cancelled events to have been dropped, but got %v", slot)
+		}
+	}
+	if handles[0].Cancel() {
+		t.Error("Expected Cancel of an invoked event to return false")
+	}
+	if _, err := hw.ScheduleHandleIn(time.Second, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if err := hw.ScheduleEventIn(time.Second, nil); err != NilEvent || !hw.IsEmpty() {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+}
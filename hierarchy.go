@@ -0,0 +1,78 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"time"
+)
+
+// Describes one level of the hierarchy of a Timer Wheel. See
+// NewHierarchicalTimerWheel.
+type LevelSpec struct {
+	// The width of each of the level's buckets.
+	BucketSize time.Duration
+	// The number of buckets in the level's ring.
+	Buckets int
+}
+
+// Creates a Timer Wheel whose hierarchy is laid out explicitly,
+// rather than every level having 32 buckets, each 32 times wider than
+// those of the level before. Each level's buckets must be as wide as
+// the whole ring of the level before it, so for example
+//
+//	[]LevelSpec{{time.Millisecond, 256}, {256 * time.Millisecond, 64}, {16384 * time.Millisecond, 64}}
+//
+// gives a root wheel of a quarter of a second at millisecond
+// resolution, as in the kernel's multi-resolution timers: events due
+// soon never cascade at all, and those due later cascade less often.
+// Levels beyond the last LevelSpec have as many buckets as it does.
+// As with NewTimerWheel, the levels are only created as events need
+// them. Panics if levels is empty, if any BucketSize is not greater
+// than 0 or Buckets is less than 2 (with a single bucket, each level
+// would be no wider than the last), or if a level's BucketSize does
+// not follow on from the level before it.
+func NewHierarchicalTimerWheel(startAt time.Time, levels []LevelSpec, options ...Option) *TimerWheel {
+	if len(levels) == 0 {
+		panic("TimerWheel hierarchy must have at least 1 level")
+	}
+	sizes := make([]int, len(levels))
+	for idx, spec := range levels {
+		if spec.BucketSize <= 0 || spec.Buckets < 2 {
+			panic(fmt.Sprintf("TimerWheel level %d must have a bucket size greater than 0 and at least 2 buckets", idx))
+		}
+		if idx > 0 {
+			if width := levels[idx-1].BucketSize * time.Duration(levels[idx-1].Buckets); spec.BucketSize != width {
+				panic(fmt.Sprintf("TimerWheel level %d bucket size must be %v, the width of level %d", idx, width, idx-1))
+			}
+		}
+		sizes[idx] = spec.Buckets
+	}
+	return NewTimerWheel(startAt, levels[0].BucketSize, append([]Option{withLevelSizes(sizes)}, options...)...)
+}
+
+// Applied before any other option, so that options which set up the
+// buckets find the root wheel's ring already the right length.
+func withLevelSizes(sizes []int) Option {
+	return func(tw *TimerWheel) {
+		tw.levelSizes = sizes
+		tw.ring = make([]bucket, sizes[0])
+	}
+}
+
+// Returns the number of buckets in the ring of the nth level of the
+// hierarchy, whether or not that level has been created yet.
+func (tw *TimerWheel) levelBuckets(n int) int {
+	sizes := tw.root.levelSizes
+	switch {
+	case len(sizes) == 0:
+		return ringLength
+	case n < len(sizes):
+		return sizes[n]
+	default:
+		return sizes[len(sizes)-1]
+	}
+}
+
+// Returns the time covered by this wheel's ring.
+func (tw *TimerWheel) ringWidth() int64 {
+	return tw.bucketSize * int64(len(tw.ring))
+}
@@ -0,0 +1,81 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestHierarchicalTimerWheel(t *testing.T) {
+	start := time.Unix(0, 0)
+	levels := []LevelSpec{{time.Millisecond, 256}, {256 * time.Millisecond, 64}, {16384 * time.Millisecond, 64}}
+	tw := NewHierarchicalTimerWheel(start, levels, WithSliceBuckets())
+	tw.ScheduleEventAt(start.Add(200*time.Millisecond), func(*time.Time) {})
+	if stats := tw.Stats(); len(stats.Levels) != 1 {
+		t.Errorf("Expected 200ms to fit in the root wheel, but got %v levels", stats.Levels)
+	}
+	tw.ScheduleEventAt(start.Add(24*time.Hour), func(*time.Time) {})
+	info := tw.Levels()
+	if len(info) != 5 || len(info[0].Counts) != 256 || len(info[1].Counts) != 64 ||
+		len(info[2].Counts) != 64 || len(info[4].Counts) != 64 {
+		t.Fatalf("Unexpected levels %+v", info)
+	}
+	if info[3].BucketSize != 64*16384*time.Millisecond || !info[0].End.Equal(start.Add(256*time.Millisecond)) {
+		t.Errorf("Unexpected level widths %v and %v", info[3].BucketSize, info[0].End)
+	}
+	if count := tw.AdvanceBy(24*time.Hour, 0); count != 2 || !tw.IsEmpty() {
+		t.Errorf("Expected both events invoked, but got %v", count)
+	}
+
+	// the horizon follows the layout
+	limited := NewHierarchicalTimerWheel(start, levels, WithMaxLevels(2))
+	if err := limited.ScheduleEventAt(start.Add(256*65*time.Millisecond-1), func(*time.Time) {}); err != nil {
+		t.Errorf("Expected the end of level 1 to be schedulable, but got %v", err)
+	}
+	if err := limited.ScheduleEventAt(start.Add(256*65*time.Millisecond), func(*time.Time) {}); err != TooFarInFuture {
+		t.Errorf("Expected TooFarInFuture, but got %v", err)
+	}
+
+	for _, bad := range [][]LevelSpec{nil, {{0, 32}}, {{time.Millisecond, 1}}, {{time.Millisecond, 32}, {16 * time.Millisecond, 32}}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %v to panic", bad)
+				}
+			}()
+			NewHierarchicalTimerWheel(start, bad)
+		}()
+	}
+}
+
+func TestHierarchicalTimerWheelOrder(t *testing.T) {
+	start := time.Unix(0, 0)
+	rng := rand.New(rand.NewSource(1087))
+	layouts := [][]LevelSpec{
+		{{time.Millisecond, 2}},
+		{{time.Millisecond, 3}, {3 * time.Millisecond, 5}},
+		{{time.Millisecond, 256}, {256 * time.Millisecond, 64}},
+	}
+	for _, layout := range layouts {
+		tw := NewHierarchicalTimerWheel(start, layout)
+		var expected, invoked []time.Duration
+		for idx := 0; idx < 1000; idx++ {
+			at := time.Duration(rng.Int63n(int64(time.Minute)))
+			expected = append(expected, at)
+			tw.ScheduleEventAt(start.Add(at), func(now *time.Time) { invoked = append(invoked, at) })
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		for !tw.IsEmpty() {
+			tw.AdvanceBy(time.Duration(rng.Int63n(int64(time.Second))), 0)
+		}
+		if len(invoked) != len(expected) {
+			t.Fatalf("Expected %v events invoked with %v, but got %v", len(expected), layout, len(invoked))
+		}
+		for idx := range expected {
+			if invoked[idx] != expected[idx] {
+				t.Fatalf("Expected %v at %v with %v, but got %v", expected[idx], idx, layout, invoked[idx])
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Identifies an event scheduled with ScheduleIDAt. IDs are unique
+// within a Timer Wheel, and are never 0.
+type EventID uint64
+
+// The number of settled EventIDs a Timer Wheel remembers by default.
+// See WithSettledIDs.
+const defaultSettledIDs = 1024
+
+// Sets how many settled EventIDs are remembered: EventIDs of events
+// which have been invoked, have expired or have been cancelled. Once
+// more than n events with EventIDs have settled, Lookup forgets the
+// oldest. N may be 0, in which case Lookup only knows about events
+// waiting to be invoked. The default is 1024. Panics if n is
+// negative.
+func WithSettledIDs(n int) Option {
+	if n < 0 {
+		panic("TimerWheel settled EventIDs must not be negative")
+	}
+	return func(tw *TimerWheel) {
+		tw.settled = settledIDs{limit: n}
+	}
+}
+
+// Remembers the most recently settled EventIDs, oldest first from
+// next in a ring of limit EventIDs.
+type settledIDs struct {
+	limit  int
+	events map[EventID]settledID
+	order  []EventID
+	next   int
+}
+
+type settledID struct {
+	event PendingEvent
+	state EventState
+}
+
+// Schedules an event to be invoked at the indicated time, returning
+// an EventID by which it can be looked up or cancelled, for example
+// from a log line or an RPC, without holding on to an EventHandle.
+// Otherwise, this is just the same as ScheduleEventAt. Once the event
+// has been invoked, has expired or has been cancelled, Lookup still
+// reports what became of it, until enough later events have settled
+// (see WithSettledIDs), so that a Timer Wheel which schedules events
+// for ever does not grow without bound. Events moved to another Timer
+// Wheel, by Merge or Extract, lose their EventIDs.
+func (tw *TimerWheel) ScheduleIDAt(at time.Time, e Event) (EventID, error) {
+	tw.lastID++
+	id := tw.lastID
+	event := tw.newEvent(eventNode{at: at.UnixNano(), fun: e, id: id})
+	if tw.ids == nil {
+		tw.ids = make(map[EventID]*eventNode)
+	}
+	// Registered first, as the event may be invoked straight away (see
+	// WithPastPolicy).
+	tw.ids[id] = event
+	if err := tw.scheduleEvent(event); err != nil {
+		delete(tw.ids, id)
+		return 0, err
+	}
+	return id, nil
+}
+
+// Schedules an event to be invoked at the current Timer Wheel's time
+// plus the supplied duration. See ScheduleIDAt.
+func (tw *TimerWheel) ScheduleIDIn(in time.Duration, e Event) (EventID, error) {
+	return tw.ScheduleIDAt(tw.after(in), e)
+}
+
+// Returns the time, metadata and state of the event with the given
+// EventID. The state is EventPending if the event is waiting to be
+// invoked, and otherwise says what became of it, in which case the
+// PendingEvent's Event is nil, so that no closure is kept alive.
+// Returns false if there is no such EventID, or if it has settled and
+// been forgotten (see WithSettledIDs).
+func (tw *TimerWheel) Lookup(id EventID) (PendingEvent, EventState, bool) {
+	if event, found := tw.ids[id]; found {
+		return tw.pendingEvent(event), EventPending, true
+	}
+	if settled, found := tw.settled.events[id]; found {
+		return settled.event, settled.state, true
+	}
+	return PendingEvent{}, EventPending, false
+}
+
+// Cancels the event with the given EventID, so that it will never be
+// invoked. Returns true if there was such an event waiting to be
+// invoked.
+func (tw *TimerWheel) CancelID(id EventID) bool {
+	event, found := tw.ids[id]
+	if !found {
+		return false
+	}
+	tw.forgetKey(event)
+	return tw.cancelEvent(event)
+}
+
+// Forgets the event's EventID without remembering what became of it,
+// as for events moved to another Timer Wheel.
+func (tw *TimerWheel) forgetID(event *eventNode) {
+	if event.id != 0 {
+		delete(tw.ids, event.id)
+		event.id = 0
+	}
+}
+
+// Forgets the event's EventID, remembering that the event settled in
+// state.
+func (tw *TimerWheel) settleID(event *eventNode, state EventState) {
+	if event.id == 0 {
+		return
+	}
+	pe := tw.pendingEvent(event)
+	pe.Event = nil
+	tw.settled.add(event.id, settledID{event: pe, state: state})
+	tw.forgetID(event)
+}
+
+func (s *settledIDs) add(id EventID, settled settledID) {
+	if s.limit == 0 {
+		return
+	}
+	if s.events == nil {
+		s.events = make(map[EventID]settledID)
+		s.order = make([]EventID, s.limit)
+	}
+	if oldest := s.order[s.next]; oldest != 0 {
+		delete(s.events, oldest)
+	}
+	s.order[s.next] = id
+	s.next = (s.next + 1) % s.limit
+	s.events[id] = settled
+}
+
+// Returns a copy which shares nothing with s.
+func (s settledIDs) clone() settledIDs {
+	if s.events == nil {
+		return s
+	}
+	events := make(map[EventID]settledID, len(s.events))
+	for id, settled := range s.events {
+		events[id] = settled
+	}
+	s.events = events
+	s.order = append([]EventID(nil), s.order...)
+	return s
+}
+
+func (s *settledIDs) reset() {
+	*s = settledIDs{limit: s.limit}
+}
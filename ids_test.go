@@ -0,0 +1,107 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventIDs(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	fired := 0
+	e := func(*time.Time) { fired++ }
+	first, err := tw.ScheduleIDAt(start.Add(time.Second), e)
+	if err != nil || first == 0 {
+		t.Fatalf("Expected an EventID, but got %v (%v)", first, err)
+	}
+	second, _ := tw.ScheduleIDIn(time.Minute, e)
+	third, _ := tw.ScheduleIDAt(start.Add(time.Hour), e)
+	if second == first || third == second || third == first {
+		t.Fatal("Expected every EventID to be unique")
+	}
+	if _, err := tw.ScheduleIDAt(start.Add(-time.Second), e); err == nil {
+		t.Fatal("Expected an event in the past to be refused")
+	}
+
+	pe, state, found := tw.Lookup(second)
+	if !found || state != EventPending || pe.ID != second || pe.Event == nil || !pe.At.Equal(start.Add(time.Minute)) {
+		t.Fatalf("Expected to find the event pending at %v, but got %v %v (%v)", start.Add(time.Minute), pe, state, found)
+	}
+	if events := tw.PeekNext(1); events[0].ID != first {
+		t.Errorf("Expected PeekNext to report the EventID %v, but got %v", first, events[0].ID)
+	}
+	if !tw.CancelID(second) || tw.CancelID(second) {
+		t.Error("Expected CancelID to cancel the event only once")
+	}
+	if pe, state, found := tw.Lookup(second); !found || state != EventCancelled || pe.Event != nil || !pe.At.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected the cancelled event to be remembered, but got %v %v (%v)", pe, state, found)
+	}
+	if _, _, found := tw.Lookup(third + 1); found {
+		t.Error("Expected an EventID never issued to be unknown")
+	}
+
+	clone := tw.Clone()
+	tw.AdvanceBy(2*time.Second, 0)
+	if _, state, _ := tw.Lookup(first); state != EventFired || fired != 1 {
+		t.Errorf("Expected the invoked event to be Fired, but got %v", state)
+	}
+	if _, state, _ := clone.Lookup(first); state != EventPending || !clone.CancelID(first) || !tw.CancelID(third) {
+		t.Error("Expected a clone to keep its own EventIDs")
+	}
+	if clone.Length() != 1 || tw.Length() != 0 {
+		t.Error("Expected cancelling by EventID in a clone to leave the original alone")
+	}
+	if _, state, _ := tw.Lookup(first); state != EventFired {
+		t.Errorf("Expected cancelling in the clone to leave the original alone, but got %v", state)
+	}
+	clone.Clear()
+	if _, state, _ := clone.Lookup(third); state != EventCancelled || len(clone.ids) != 0 {
+		t.Errorf("Expected Clear to cancel every event, but got %v", state)
+	}
+	if err := clone.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEventIDsInvokedInPast(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithPastPolicy(PastInvoke))
+	fired := false
+	id, err := tw.ScheduleIDAt(start.Add(-time.Second), func(*time.Time) { fired = true })
+	if err != nil || id == 0 || !fired {
+		t.Fatalf("Expected the event to be invoked straight away, but got %v", err)
+	}
+	if _, state, _ := tw.Lookup(id); state != EventFired {
+		t.Errorf("Expected the invoked event to be Fired, but got %v", state)
+	}
+	if err := tw.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSettledIDs(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithSettledIDs(2), WithMaxLateness(time.Millisecond, nil))
+	first, _ := tw.ScheduleIDIn(time.Millisecond, func(*time.Time) {})
+	second, _ := tw.ScheduleIDIn(time.Millisecond, func(*time.Time) {})
+	tw.AdvanceBy(time.Second, 0)
+	if _, state, _ := tw.Lookup(second); state != EventExpired {
+		t.Errorf("Expected the late event to be Expired, but got %v", state)
+	}
+	third, _ := tw.ScheduleIDIn(time.Millisecond, func(*time.Time) {})
+	tw.CancelID(third)
+	if _, _, found := tw.Lookup(first); found {
+		t.Error("Expected the oldest settled EventID to be forgotten")
+	}
+	for _, id := range []EventID{second, third} {
+		if _, _, found := tw.Lookup(id); !found {
+			t.Errorf("Expected EventID %v to be remembered", id)
+		}
+	}
+	none := NewTimerWheel(start, time.Millisecond, WithSettledIDs(0))
+	id, _ := none.ScheduleIDIn(time.Millisecond, func(*time.Time) {})
+	none.CancelID(id)
+	if _, _, found := none.Lookup(id); found || len(none.settled.events) != 0 {
+		t.Error("Expected no settled EventIDs to be remembered")
+	}
+}
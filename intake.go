@@ -0,0 +1,108 @@
+package gotimerwheel
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// An Intake lets goroutines other than the one driving a Timer Wheel
+// schedule events on it. Its methods are safe for concurrent use, and
+// never block: events are pushed onto a lock-free queue, which the
+// Timer Wheel takes in at the start of every AdvanceTo (and the other
+// methods which advance). So events scheduled through an Intake are
+// not visible to the Timer Wheel, for example to Length, until then.
+type Intake struct {
+	tw *TimerWheel
+	// The most recently pushed *intakeNode.
+	head     unsafe.Pointer
+	rejected func(at time.Time, e Event, err error)
+}
+
+type intakeNode struct {
+	at       time.Time
+	in       time.Duration
+	relative bool
+	e        Event
+	// Set for calls pushed by Do.
+	do   func()
+	next *intakeNode
+}
+
+// Creates an Intake for the Timer Wheel. Events scheduled through the
+// Intake which the Timer Wheel refuses when it takes them in, for
+// example with ScheduledInPast, are passed to rejected (on the
+// goroutine driving the Timer Wheel) along with the error. Rejected
+// may be nil. NewIntake must be called from the goroutine which
+// drives the Timer Wheel.
+func (tw *TimerWheel) NewIntake(rejected func(at time.Time, e Event, err error)) *Intake {
+	in := &Intake{tw: tw, rejected: rejected}
+	tw.intakes = append(tw.intakes, in)
+	return in
+}
+
+// Schedules an event to be invoked at the indicated time. See
+// TimerWheel.ScheduleEventAt.
+func (in *Intake) ScheduleEventAt(at time.Time, e Event) {
+	in.push(&intakeNode{at: at, e: e})
+}
+
+// Schedules an event to be invoked the supplied duration after the
+// Timer Wheel's current time at the point it takes the event in.
+func (in *Intake) ScheduleEventIn(d time.Duration, e Event) {
+	in.push(&intakeNode{in: d, relative: true, e: e})
+}
+
+// Arranges for f to be called on the goroutine driving the Timer
+// Wheel when it next takes in the Intake, in order with the events
+// scheduled through the Intake. This is how other goroutines use the
+// handles of a Timer Wheel, such as EventHandles, Timers, Deadlines
+// and StagedTimeouts, none of which are safe for concurrent use
+// themselves: f may call their methods, and send the results back on
+// a channel. F is still called once the Timer Wheel has been closed,
+// if it is advanced, and handles then report that their events have
+// gone.
+func (in *Intake) Do(f func()) {
+	in.push(&intakeNode{do: f})
+}
+
+func (in *Intake) push(node *intakeNode) {
+	for {
+		head := atomic.LoadPointer(&in.head)
+		node.next = (*intakeNode)(head)
+		if atomic.CompareAndSwapPointer(&in.head, head, unsafe.Pointer(node)) {
+			return
+		}
+	}
+}
+
+// Schedules every event pushed onto the Intake so far, in the order
+// in which they were pushed.
+func (in *Intake) drain() {
+	node := (*intakeNode)(atomic.SwapPointer(&in.head, nil))
+	var reversed *intakeNode
+	for node != nil {
+		next := node.next
+		node.next = reversed
+		reversed, node = node, next
+	}
+	for node = reversed; node != nil; node = node.next {
+		if node.do != nil {
+			node.do()
+			continue
+		}
+		at := node.at
+		if node.relative {
+			at = in.tw.after(node.in)
+		}
+		if err := in.tw.ScheduleEventAt(at, node.e); err != nil && in.rejected != nil {
+			in.rejected(at, node.e, err)
+		}
+	}
+}
+
+func (tw *TimerWheel) drainIntakes() {
+	for _, in := range tw.intakes {
+		in.drain()
+	}
+}
@@ -0,0 +1,107 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIntake(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	rejected := 0
+	in := tw.NewIntake(func(at time.Time, e Event, err error) {
+		if !errors.Is(err, ScheduledInPast) {
+			t.Errorf("Expected ScheduledInPast, but got %v", err)
+		}
+		rejected++
+	})
+	invoked := 0
+	event := func(*time.Time) { invoked++ }
+	var wg sync.WaitGroup
+	for producer := 0; producer < 8; producer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := 0; idx < 100; idx++ {
+				in.ScheduleEventAt(start.Add(time.Duration(idx)*time.Millisecond), event)
+			}
+		}()
+	}
+	wg.Wait()
+	if tw.Length() != 0 {
+		t.Errorf("Expected no events until the intake is taken in, but got %v", tw.Length())
+	}
+	if count := tw.AdvanceTo(start.Add(50*time.Millisecond), 0); count != 8*51 {
+		t.Errorf("Expected %v events invoked, but got %v", 8*51, count)
+	}
+	in.ScheduleEventAt(start, event)
+	in.ScheduleEventIn(time.Millisecond, event)
+	if _, count := tw.AdvanceToNextEvent(); count != 9 || rejected != 1 {
+		t.Errorf("Expected 9 events invoked and 1 rejected, but got %v and %v", count, rejected)
+	}
+	tw.AdvanceBy(time.Second, 0)
+	if invoked != 801 || !tw.IsEmpty() {
+		t.Errorf("Expected 801 events invoked, but got %v", invoked)
+	}
+}
+
+// The order in which one goroutine schedules events through an Intake
+// is kept.
+func TestIntakeOrder(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	in := tw.NewIntake(nil)
+	var invoked []int
+	for idx := 0; idx < 10; idx++ {
+		idx := idx
+		in.ScheduleEventAt(start, func(*time.Time) { invoked = append(invoked, idx) })
+	}
+	tw.AdvanceTo(start, 0)
+	for idx := range invoked {
+		if invoked[idx] != idx {
+			t.Fatalf("Expected events in order of scheduling, but got %v", invoked)
+		}
+	}
+	if len(invoked) != 10 {
+		t.Errorf("Expected 10 events invoked, but got %v", invoked)
+	}
+}
+
+// Handles are used from other goroutines through Do.
+func TestIntakeDo(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	in := tw.NewIntake(nil)
+	handles := make([]*EventHandle, 100)
+	for idx := range handles {
+		handles[idx], _ = tw.ScheduleHandleIn(time.Millisecond, func(*time.Time) { t.Error("Cancelled event invoked") })
+	}
+	results := make(chan bool, 2*len(handles))
+	var wg sync.WaitGroup
+	for producer := 0; producer < 2; producer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, h := range handles {
+				h := h
+				in.Do(func() { results <- h.Cancel() })
+			}
+		}()
+	}
+	wg.Wait()
+	if count := tw.AdvanceBy(time.Millisecond, 0); count != 0 || !tw.IsEmpty() {
+		t.Errorf("Expected every event to be cancelled before the advance, but got %v invoked", count)
+	}
+	close(results)
+	cancelled := 0
+	for result := range results {
+		if result {
+			cancelled++
+		}
+	}
+	if cancelled != len(handles) {
+		t.Errorf("Expected each event to be cancelled once, but got %v", cancelled)
+	}
+}
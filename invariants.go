@@ -0,0 +1,148 @@
+package gotimerwheel
+
+import (
+	"fmt"
+)
+
+// Verifies the internal consistency of the Timer Wheel, returning an
+// error describing the first inconsistency found, or nil. Every level
+// of the hierarchy must follow on from the level before it, every
+// bucket's count must match the events it holds, every event must lie
+// within its bucket's time range, the root wheel's buckets must be in
+// order unless marked for sorting, every key and EventID must refer to
+// a scheduled event, and no EventID may be both scheduled and settled.
+// This is O(n), and is meant for fuzzing and for tracking down
+// corruption, rather than for calling on every advance. Unlike calls
+// which walk every event, it leaves any cascade under way (see
+// WithIncrementalCascade) as it is.
+func (tw *TimerWheel) CheckInvariants() error {
+	events := make(map[*eventNode]bool)
+	var prev *TimerWheel
+	n := 0
+	for level := tw; level != nil; prev, level, n = level, level.next, n+1 {
+		if len(level.ring) != tw.levelBuckets(n) {
+			return fmt.Errorf("Level %d has %d buckets rather than %d", n, len(level.ring), tw.levelBuckets(n))
+		}
+		if level.ringIdx < 0 || level.ringIdx >= len(level.ring) {
+			return fmt.Errorf("Level %d is at bucket %d of %d", n, level.ringIdx, len(level.ring))
+		}
+		if prev != nil {
+			if level.bucketSize != prev.ringWidth() {
+				return fmt.Errorf("Level %d has buckets %d wide rather than %d, the width of level %d", n, level.bucketSize, prev.ringWidth(), n-1)
+			}
+			if current, end := level.start+int64(level.ringIdx)*level.bucketSize, prev.start+prev.ringWidth(); current != end {
+				return fmt.Errorf("Level %d is at %v rather than %v, the end of level %d", n, tw.toTime(current), tw.toTime(end), n-1)
+			}
+		}
+		for idx := range level.ring {
+			if err := level.checkBucket(n, idx, events); err != nil {
+				return err
+			}
+		}
+		if err := level.checkCascade(n, events); err != nil {
+			return err
+		}
+	}
+	if len(tw.overflow.events) > 0 {
+		end := prev.start + prev.ringWidth()
+		if n != tw.overflow.levels {
+			return fmt.Errorf("Overflow heap holds events, but there are %d levels rather than %d", n, tw.overflow.levels)
+		}
+		for _, entry := range tw.overflow.events {
+			if entry.event.at < end {
+				return fmt.Errorf("Overflow heap holds an event at %v, before the end of the last level at %v", tw.toTime(entry.event.at), tw.toTime(end))
+			}
+			if events[entry.event] {
+				return fmt.Errorf("Event at %v is held more than once", tw.toTime(entry.event.at))
+			}
+			events[entry.event] = true
+		}
+	}
+	if length := tw.Length(); length != len(events) {
+		return fmt.Errorf("Length is %d, but %d events are held", length, len(events))
+	}
+	for key, event := range tw.keys {
+		if !event.keyed || event.key != key {
+			return fmt.Errorf("Key %q refers to an event with a different key", key)
+		}
+		if !events[event] {
+			return fmt.Errorf("Key %q refers to an event which is not scheduled", key)
+		}
+	}
+	for id, event := range tw.ids {
+		if event.id != id {
+			return fmt.Errorf("EventID %d refers to an event with a different EventID", id)
+		}
+		if !events[event] {
+			return fmt.Errorf("EventID %d refers to an event which is not scheduled", id)
+		}
+		if _, found := tw.settled.events[id]; found {
+			return fmt.Errorf("EventID %d is both scheduled and settled", id)
+		}
+	}
+	return nil
+}
+
+func (tw *TimerWheel) checkBucket(n, idx int, events map[*eventNode]bool) error {
+	b := &tw.ring[idx]
+	var nodes []*eventNode
+	if b.sliced {
+		for _, entry := range b.live() {
+			if entry.at != entry.event.at {
+				return fmt.Errorf("Level %d bucket %d has an entry at %v for an event at %v", n, idx, tw.toTime(entry.at), tw.toTime(entry.event.at))
+			}
+			nodes = append(nodes, entry.event)
+		}
+	} else {
+		var last *eventNode
+		for event := b.eventNode; event != nil && len(nodes) <= b.count; event = event.next.eventNode {
+			nodes = append(nodes, event)
+			last = event
+		}
+		if b.tail != last {
+			return fmt.Errorf("Level %d bucket %d has a tail which is not its last event", n, idx)
+		}
+	}
+	if len(nodes) != b.count {
+		return fmt.Errorf("Level %d bucket %d has a count of %d, but holds %d events", n, idx, b.count, len(nodes))
+	}
+	if idx < tw.ringIdx && b.count > 0 {
+		return fmt.Errorf("Level %d bucket %d holds events, but the level is at bucket %d", n, idx, tw.ringIdx)
+	}
+	start := tw.start + int64(idx)*tw.bucketSize
+	for pos, event := range nodes {
+		if event.at < start || event.at >= start+tw.bucketSize {
+			return fmt.Errorf("Level %d bucket %d from %v to %v holds an event at %v", n, idx, tw.toTime(start), tw.toTime(start+tw.bucketSize), tw.toTime(event.at))
+		}
+		if tw.root == tw && !b.unsorted && pos > 0 && before(event, nodes[pos-1], b.tieBreak) {
+			return fmt.Errorf("Level %d bucket %d is not in order at %v", n, idx, tw.toTime(event.at))
+		}
+		if events[event] {
+			return fmt.Errorf("Event at %v is held more than once", tw.toTime(event.at))
+		}
+		events[event] = true
+	}
+	return nil
+}
+
+func (tw *TimerWheel) checkCascade(n int, events map[*eventNode]bool) error {
+	c := &tw.cascade
+	count := 0
+	var last *eventNode
+	start, end := tw.start+int64(tw.ringIdx)*tw.bucketSize, tw.start+tw.ringWidth()
+	for event := c.head; event != nil && count <= c.count; event = event.next.eventNode {
+		if event.at < start || event.at >= end {
+			return fmt.Errorf("Level %d has an event at %v pending cascade, outside %v to %v", n, tw.toTime(event.at), tw.toTime(start), tw.toTime(end))
+		}
+		if events[event] {
+			return fmt.Errorf("Event at %v is held more than once", tw.toTime(event.at))
+		}
+		events[event] = true
+		count++
+		last = event
+	}
+	if count != c.count || c.tail != last {
+		return fmt.Errorf("Level %d has %d events pending cascade, but a count of %d", n, count, c.count)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	start := time.Unix(0, 0)
+	variants := map[string]func() *TimerWheel{
+		"default":     func() *TimerWheel { return NewTimerWheel(start, time.Millisecond) },
+		"slices":      func() *TimerWheel { return NewTimerWheel(start, time.Millisecond, WithSliceBuckets()) },
+		"overflow":    func() *TimerWheel { return NewTimerWheel(start, time.Millisecond, WithOverflowHeap(2)) },
+		"incremental": func() *TimerWheel { return NewTimerWheel(start, time.Millisecond, WithIncrementalCascade(3)) },
+		"approximate": func() *TimerWheel { return NewTimerWheel(start, time.Millisecond, WithApproximateExpiry()) },
+		"hierarchy": func() *TimerWheel {
+			return NewHierarchicalTimerWheel(start, []LevelSpec{{time.Millisecond, 64}, {64 * time.Millisecond, 8}})
+		},
+	}
+	for name, newWheel := range variants {
+		rng := rand.New(rand.NewSource(1099))
+		tw := newWheel()
+		var handles []*EventHandle
+		for op := 0; op < 5000; op++ {
+			switch n := rng.Intn(10); {
+			case n < 6:
+				at := tw.Now().Add(time.Duration(rng.Int63n(int64(time.Minute))))
+				if rng.Intn(4) == 0 {
+					tw.ScheduleKeyedEventAt(string(rune('a'+rng.Intn(26))), at, func(*time.Time) {})
+				} else {
+					h, _ := tw.ScheduleHandleAt(at, func(*time.Time) {})
+					handles = append(handles, h)
+				}
+			case n < 7 && len(handles) > 0:
+				handles[rng.Intn(len(handles))].Cancel()
+			case n < 8:
+				from := tw.Now().Add(time.Duration(rng.Int63n(int64(time.Minute))))
+				tw.CancelBetween(from, from.Add(time.Second))
+			default:
+				tw.AdvanceBy(time.Duration(rng.Int63n(int64(time.Second))), rng.Intn(5))
+			}
+			if err := tw.CheckInvariants(); err != nil {
+				t.Fatalf("%v: after op %v: %v", name, op, err)
+			}
+		}
+	}
+}
+
+func TestCheckInvariantsFindsCorruption(t *testing.T) {
+	start := time.Unix(0, 0)
+	corruptions := map[string]func(tw *TimerWheel){
+		"count": func(tw *TimerWheel) { tw.ring[1].count++ },
+		"order": func(tw *TimerWheel) {
+			tw.ring[1].eventNode.at, tw.ring[1].tail.at = tw.ring[1].tail.at, tw.ring[1].eventNode.at
+		},
+		"range":  func(tw *TimerWheel) { tw.ring[1].eventNode.at = start.Add(5 * time.Millisecond).UnixNano() },
+		"level":  func(tw *TimerWheel) { tw.next.ringIdx++ },
+		"key":    func(tw *TimerWheel) { tw.keys = map[string]*eventNode{"missing": {key: "missing", keyed: true}} },
+		"behind": func(tw *TimerWheel) { tw.ringIdx = 2 },
+	}
+	for name, corrupt := range corruptions {
+		tw := NewTimerWheel(start, time.Millisecond)
+		tw.ScheduleEventAt(start.Add(time.Millisecond), func(*time.Time) {})
+		tw.ScheduleEventAt(start.Add(1500*time.Microsecond), func(*time.Time) {})
+		tw.ScheduleEventAt(start.Add(time.Hour), func(*time.Time) {})
+		if err := tw.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+		corrupt(tw)
+		if err := tw.CheckInvariants(); err == nil {
+			t.Errorf("Expected %v corruption to be found", name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"time"
+)
+
+type jitter struct {
+	fraction float64
+	rng      *rand.Rand
+}
+
+// Randomises the times of events scheduled relative to the current
+// time (ScheduleEventIn and the other ...In methods), to avoid many
+// timers, such as retries, all firing together. Each event is
+// delayed by a random extra duration, uniformly chosen from zero up
+// to fraction of its requested delay, so no event fires before the
+// time it asked for. Random numbers come from rng, or from the
+// math/rand top-level functions if rng is nil. Fraction must be
+// greater than 0.
+func WithJitter(fraction float64, rng *rand.Rand) Option {
+	if fraction <= 0 {
+		panic("TimerWheel jitter fraction must be greater than 0")
+	}
+	return func(tw *TimerWheel) {
+		tw.jitter = jitter{fraction: fraction, rng: rng}
+	}
+}
+
+// Returns the time which is in after the current time, plus any
+// jitter.
+func (tw *TimerWheel) after(in time.Duration) time.Time {
+	j := &tw.jitter
+	if j.fraction > 0 && in > 0 {
+		if window := int64(float64(in) * j.fraction); window > 0 {
+			if j.rng != nil {
+				in += time.Duration(j.rng.Int63n(window + 1))
+			} else {
+				in += time.Duration(rand.Int63n(window + 1))
+			}
+		}
+	}
+	return tw.Now().Add(in)
+}
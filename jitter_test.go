@@ -0,0 +1,31 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithJitter(0.5, rand.New(rand.NewSource(1))), WithNilEvents())
+	times := make(map[time.Time]bool)
+	for idx := 0; idx < 100; idx++ {
+		tw.ScheduleEventIn(100*time.Millisecond, nil)
+	}
+	tw.ScheduleEventAt(start.Add(100*time.Millisecond), nil)
+	exact := 0
+	tw.ForEach(func(at time.Time, e Event) bool {
+		if at.Before(start.Add(100*time.Millisecond)) || at.After(start.Add(150*time.Millisecond)) {
+			t.Errorf("Expected event between 100ms and 150ms, but got %v", at.Sub(start))
+		}
+		if at.Equal(start.Add(100 * time.Millisecond)) {
+			exact++
+		}
+		times[at] = true
+		return true
+	})
+	if len(times) < 50 || exact < 1 {
+		t.Errorf("Expected jittered times plus the unjittered event, but got %v distinct times", len(times))
+	}
+}
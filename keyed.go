@@ -0,0 +1,84 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Schedules an event identified by key to be invoked at the
+// indicated time. If an event with the same key is already scheduled
+// and has not yet been invoked then it is cancelled and replaced by
+// this one. Otherwise, this behaves just like ScheduleEventAt. If at
+// is in the past then ScheduledInPast is returned, or if it is beyond
+// the horizon TooFarInFuture (see WithMaxLevels), or if e is nil
+// NilEvent (see WithNilEvents), and any existing event with the same
+// key is left in place.
+func (tw *TimerWheel) ScheduleKeyedEventAt(key string, at time.Time, e Event) error {
+	if tw.closed {
+		return Closed
+	}
+	if e == nil && !tw.nilEvents {
+		return NilEvent
+	}
+	if err := tw.refusePast(at.UnixNano()); err != nil {
+		return err
+	}
+	if tw.refuseBeyond(at.UnixNano()) {
+		return TooFarInFuture
+	}
+	tw.cancelKey(key, "replace")
+	event := tw.newEvent(eventNode{at: at.UnixNano(), fun: e, key: key, keyed: true})
+	if tw.keys == nil {
+		tw.keys = make(map[string]*eventNode)
+	}
+	// Recorded first, as with PastInvoke the event may be invoked,
+	// and so forgotten, by scheduleEvent.
+	tw.keys[key] = event
+	if err := tw.scheduleEvent(event); err != nil {
+		tw.forgetKey(event)
+		return err
+	}
+	return nil
+}
+
+// Schedules an event identified by key to be invoked at the current
+// Timer Wheel's time plus the supplied duration. See
+// ScheduleKeyedEventAt.
+func (tw *TimerWheel) ScheduleKeyedEventIn(key string, in time.Duration, e Event) error {
+	return tw.ScheduleKeyedEventAt(key, tw.after(in), e)
+}
+
+// Cancels the event scheduled with the given key, so that it will
+// never be invoked. Returns true if there was such an event waiting
+// to be invoked.
+func (tw *TimerWheel) CancelKey(key string) bool {
+	return tw.cancelKey(key, "cancel")
+}
+
+func (tw *TimerWheel) cancelKey(key, reason string) bool {
+	event, found := tw.keys[key]
+	if !found {
+		return false
+	}
+	delete(tw.keys, key)
+	return tw.cancelEventFor(event, reason)
+}
+
+// Replaces the callback of the event scheduled with the given key,
+// without changing its time or its position relative to other
+// events. Returns false if there is no such event waiting to be
+// invoked, or if e is nil and nil events are refused (see
+// WithNilEvents), in which case the callback is left unchanged.
+func (tw *TimerWheel) SetKeyFunc(key string, e Event) bool {
+	event, found := tw.keys[key]
+	if !found || (e == nil && !tw.nilEvents) {
+		return false
+	}
+	event.fun = e
+	return true
+}
+
+func (tw *TimerWheel) forgetKey(event *eventNode) {
+	if event.keyed && tw.keys[event.key] == event {
+		delete(tw.keys, event.key)
+	}
+}
@@ -0,0 +1,98 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyedReplace(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1, WithNilEvents())
+	fired := []string{}
+	tw.ScheduleKeyedEventAt("conn", time.Unix(0, 5), func(*time.Time) { fired = append(fired, "first") })
+	tw.ScheduleKeyedEventAt("other", time.Unix(0, 5), func(*time.Time) { fired = append(fired, "other") })
+	// rescheduling the same key, even into a next wheel, replaces it
+	tw.ScheduleKeyedEventAt("conn", time.Unix(0, 100), func(*time.Time) { fired = append(fired, "second") })
+	assertNowLength(t, tw, start, 2)
+	if err := tw.ScheduleKeyedEventAt("conn", time.Unix(0, -1), nil); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	assertNowLength(t, tw, start, 2)
+
+	tw.AdvanceTo(time.Unix(0, 200), 0)
+	if len(fired) != 2 || fired[0] != "other" || fired[1] != "second" {
+		t.Errorf("Expected other then second, but got %v", fired)
+	}
+	if stats := tw.Stats(); stats.Cancelled != 1 {
+		t.Errorf("Expected 1 cancelled, but found %v", stats.Cancelled)
+	}
+	// once invoked, the key is free
+	if tw.CancelKey("conn") {
+		t.Error("Expected nothing to cancel")
+	}
+}
+
+func TestCancelKey(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	for _, ns := range []int64{3, 3, 40, 2000} {
+		key := time.Unix(0, ns).String()
+		tw.ScheduleEventAt(time.Unix(0, ns), func(*time.Time) {})
+		tw.ScheduleKeyedEventAt(key, time.Unix(0, ns), func(*time.Time) { t.Errorf("Cancelled event %v invoked", key) })
+	}
+	assertNowLength(t, tw, start, 7)
+	for _, ns := range []int64{3, 40, 2000} {
+		if !tw.CancelKey(time.Unix(0, ns).String()) {
+			t.Errorf("Expected to cancel event at %v", ns)
+		}
+	}
+	assertNowLength(t, tw, start, 4)
+	if tw.CancelKey("missing") {
+		t.Error("Expected nothing to cancel")
+	}
+	if count := tw.AdvanceTo(time.Unix(0, 3000), 0); count != 4 {
+		t.Errorf("Expected 4 events invoked, but got %v", count)
+	}
+}
+
+func TestCancelKeyFromEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 10)
+	fired := 0
+	// both in the same bucket: the first cancels the second
+	tw.ScheduleEventAt(time.Unix(0, 1), func(*time.Time) {
+		fired++
+		tw.CancelKey("victim")
+		// and reuses a key from within the event
+		tw.ScheduleKeyedEventAt("again", time.Unix(0, 25), func(*time.Time) { fired++ })
+	})
+	tw.ScheduleKeyedEventAt("victim", time.Unix(0, 2), func(*time.Time) { t.Error("Cancelled event invoked") })
+	tw.AdvanceTo(time.Unix(0, 20), 0)
+	assertNowLength(t, tw, time.Unix(0, 20), 1)
+	tw.AdvanceTo(time.Unix(0, 30), 0)
+	if fired != 2 {
+		t.Errorf("Expected 2 events invoked, but got %v", fired)
+	}
+	assertNowLength(t, tw, time.Unix(0, 30), 0)
+}
+
+func TestSetKeyFunc(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	fired := []string{}
+	tw.ScheduleKeyedEventAt("conn", time.Unix(0, 50), func(*time.Time) { fired = append(fired, "warn") })
+	tw.ScheduleEventAt(time.Unix(0, 50), func(*time.Time) { fired = append(fired, "unkeyed") })
+	if !tw.SetKeyFunc("conn", func(*time.Time) { fired = append(fired, "disconnect") }) {
+		t.Error("Expected to replace callback")
+	}
+	if tw.SetKeyFunc("missing", nil) {
+		t.Error("Expected nothing to replace")
+	}
+	tw.AdvanceTo(time.Unix(0, 50), 0)
+	if len(fired) != 2 || fired[0] != "disconnect" || fired[1] != "unkeyed" {
+		t.Errorf("Expected disconnect then unkeyed, but got %v", fired)
+	}
+	if tw.SetKeyFunc("conn", nil) {
+		t.Error("Expected nothing to replace once invoked")
+	}
+}
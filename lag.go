@@ -0,0 +1,85 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// How late events have been invoked: the difference between the time
+// each event was scheduled for and the Timer Wheel's current time
+// when it was invoked. Lags grow when AdvanceTo is called less often
+// than events fall due, or when its limit leaves events waiting for
+// a later call. See WithLagHistogram.
+type LagHistogram struct {
+	// The upper bound of each bucket of the histogram, ascending.
+	Bounds []time.Duration
+	// The number of events invoked with a lag of at most the bound of
+	// the same index, and more than the bound before. There is one
+	// more count than there are bounds, for lags beyond the last.
+	Counts []uint64
+	// The total lag of every event counted.
+	Sum time.Duration
+}
+
+type lagHistogram struct {
+	// nil unless WithLagHistogram was given.
+	bounds []int64
+	counts []uint64
+	sum    int64
+}
+
+// Records how late each event is invoked in a histogram with the
+// indicated bucket bounds, which is returned as part of Stats. For
+// example, bounds of 0, time.Millisecond and 10 * time.Millisecond
+// count separately the events invoked on time, those up to a
+// millisecond late, up to 10 milliseconds late, and later still.
+// Events which expire (see WithMaxLateness) are not counted. Bounds
+// must not be empty, must not be negative, and must be ascending.
+func WithLagHistogram(bounds ...time.Duration) Option {
+	if len(bounds) == 0 {
+		panic("TimerWheel lag histogram must have at least 1 bound")
+	}
+	lh := lagHistogram{bounds: make([]int64, len(bounds)), counts: make([]uint64, len(bounds)+1)}
+	for idx, bound := range bounds {
+		if bound < 0 || (idx > 0 && bound <= bounds[idx-1]) {
+			panic("TimerWheel lag histogram bounds must not be negative and must be ascending")
+		}
+		lh.bounds[idx] = int64(bound)
+	}
+	return func(tw *TimerWheel) {
+		tw.lag = lagHistogram{bounds: lh.bounds, counts: append([]uint64(nil), lh.counts...)}
+	}
+}
+
+func (lh *lagHistogram) record(lag int64) {
+	if lh.bounds == nil {
+		return
+	}
+	idx := 0
+	for idx < len(lh.bounds) && lag > lh.bounds[idx] {
+		idx++
+	}
+	lh.counts[idx]++
+	lh.sum += lag
+}
+
+func (lh *lagHistogram) reset() {
+	for idx := range lh.counts {
+		lh.counts[idx] = 0
+	}
+	lh.sum = 0
+}
+
+func (lh *lagHistogram) histogram() LagHistogram {
+	if lh.bounds == nil {
+		return LagHistogram{}
+	}
+	h := LagHistogram{
+		Bounds: make([]time.Duration, len(lh.bounds)),
+		Counts: append([]uint64(nil), lh.counts...),
+		Sum:    time.Duration(lh.sum),
+	}
+	for idx, bound := range lh.bounds {
+		h.Bounds[idx] = time.Duration(bound)
+	}
+	return h
+}
@@ -0,0 +1,60 @@
+package gotimerwheel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLagHistogram(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithLagHistogram(0, time.Millisecond, 10*time.Millisecond))
+	e := func(*time.Time) {}
+	for _, at := range []time.Duration{5, 10, 20, 30, 200, 200} {
+		tw.ScheduleEventAt(start.Add(at*time.Millisecond), e)
+	}
+	tw.AdvanceTo(start.Add(5*time.Millisecond), 0)
+	tw.AdvanceTo(start.Add(10500*time.Microsecond), 0)
+	tw.AdvanceTo(start.Add(25*time.Millisecond), 0)
+	tw.AdvanceTo(start.Add(100*time.Millisecond), 0)
+	// the limit leaves the second event for the next advance
+	tw.AdvanceTo(start.Add(200*time.Millisecond), 1)
+	tw.AdvanceTo(start.Add(203*time.Millisecond), 0)
+
+	lag := tw.Stats().Lag
+	expected := LagHistogram{
+		Bounds: []time.Duration{0, time.Millisecond, 10 * time.Millisecond},
+		Counts: []uint64{2, 1, 2, 1},
+		Sum:    78500 * time.Microsecond,
+	}
+	if !reflect.DeepEqual(lag, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, lag)
+	}
+
+	clone := tw.Clone()
+	clone.ScheduleEventAt(start.Add(time.Second), e)
+	clone.AdvanceTo(start.Add(2*time.Second), 0)
+	if !reflect.DeepEqual(tw.Stats().Lag, expected) || clone.Stats().Lag.Counts[3] != 2 {
+		t.Error("Expected a clone's histogram to be independent of the original's")
+	}
+	tw.Reset(start)
+	if lag := tw.Stats().Lag; lag.Sum != 0 || !reflect.DeepEqual(lag.Counts, []uint64{0, 0, 0, 0}) {
+		t.Errorf("Expected Reset to empty the histogram, but got %v", lag)
+	}
+	if lag := NewTimerWheel(start, time.Millisecond).Stats().Lag; lag.Counts != nil {
+		t.Errorf("Expected no histogram without WithLagHistogram, but got %v", lag)
+	}
+}
+
+func TestLagHistogramBounds(t *testing.T) {
+	for _, bounds := range [][]time.Duration{nil, {-time.Second}, {time.Second, time.Second}, {time.Second, time.Millisecond}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected bounds %v to panic", bounds)
+				}
+			}()
+			WithLagHistogram(bounds...)
+		}()
+	}
+}
@@ -0,0 +1,53 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type lateness struct {
+	bounded bool
+	max     time.Duration
+	expired func(at time.Time, e Event)
+}
+
+// Bounds how late an event may be invoked. When advancing, any event
+// whose time is more than max before the time being advanced to is
+// not invoked: instead it is passed, along with the time it was
+// scheduled for, to expired (if non-nil), and counted as Expired
+// rather than Fired in Stats (though still towards the limit and
+// count of AdvanceTo). Handlers therefore never see an event more
+// than max late, which suits control loops that would rather drop
+// stale commands than act on them. A max of 0 means only events for
+// exactly the time being advanced to are invoked. Max must not be
+// negative.
+func WithMaxLateness(max time.Duration, expired func(at time.Time, e Event)) Option {
+	if max < 0 {
+		panic("TimerWheel max lateness must not be negative")
+	}
+	return func(tw *TimerWheel) {
+		tw.lateness = lateness{bounded: true, max: max, expired: expired}
+	}
+}
+
+// Returns true if the event was too late to be invoked, in which case
+// it has been passed to the expired function (or its own onExpired,
+// see ScheduleTTLEventAt).
+func (tw *TimerWheel) expire(event *eventNode, now *time.Time) bool {
+	if ttl := event.ttl; ttl != nil {
+		if now.UnixNano()-event.at <= ttl.ttl {
+			return false
+		}
+		if ttl.onExpired != nil {
+			ttl.onExpired(now)
+		}
+		return true
+	}
+	l := &tw.lateness
+	if !l.bounded || now.UnixNano()-event.at <= int64(l.max) {
+		return false
+	}
+	if l.expired != nil {
+		l.expired(tw.toTime(event.at), event.fun)
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxLateness(t *testing.T) {
+	start := time.Unix(0, 0)
+	expired := []time.Time{}
+	tw := NewTimerWheel(start, 1, WithMaxLateness(5, func(at time.Time, e Event) {
+		expired = append(expired, at)
+	}))
+	invoked := []time.Time{}
+	for _, ns := range []int64{2, 4, 5, 9, 12} {
+		tw.ScheduleEventAt(time.Unix(0, ns), func(now *time.Time) {
+			invoked = append(invoked, *now)
+		})
+	}
+	// it's now 10: events at 2 and 4 are more than 5 late
+	if count := tw.AdvanceTo(time.Unix(0, 10), 0); count != 4 {
+		t.Errorf("Expected 4 events, but got %v", count)
+	}
+	if len(expired) != 2 || !expired[0].Equal(time.Unix(0, 2)) || !expired[1].Equal(time.Unix(0, 4)) {
+		t.Errorf("Expected events at 2 and 4 to expire, but got %v", expired)
+	}
+	if len(invoked) != 2 {
+		t.Errorf("Expected 2 events to be invoked, but got %v", invoked)
+	}
+	tw.AdvanceTo(time.Unix(0, 12), 0)
+	if stats := tw.Stats(); stats.Fired != 3 || stats.Expired != 2 {
+		t.Errorf("Expected 3 fired and 2 expired, but got %+v", stats)
+	}
+}
@@ -0,0 +1,55 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A description of one wheel in the hierarchy, as returned by Levels.
+type LevelInfo struct {
+	// 0 for the root wheel.
+	Level int
+	// The time range covered by the wheel's ring.
+	Start time.Time
+	End   time.Time
+	// The width of each of the wheel's buckets.
+	BucketSize time.Duration
+	// The index of the wheel's current bucket. Buckets before it have
+	// been passed, and are empty.
+	RingIdx int
+	// The number of events in each of the wheel's buckets.
+	Counts []int
+	// The total of Counts.
+	Length int
+}
+
+// Returns a description of each wheel in the hierarchy, root first,
+// for tuning bucketSize against real traffic: if the root wheel's
+// buckets routinely hold more than around 100 events then bucketSize
+// is too large, and if most events are in the coarser wheels then
+// they are cascaded more than they need be. Events in the overflow
+// heap (see WithOverflowHeap) are not in any level: see
+// Stats.Overflow. This is O(buckets).
+func (tw *TimerWheel) Levels() []LevelInfo {
+	tw.completeCascades()
+	var levels []LevelInfo
+	for level := tw; level != nil; level = level.next {
+		levels = append(levels, level.levelInfo())
+	}
+	return levels
+}
+
+func (tw *TimerWheel) levelInfo() LevelInfo {
+	li := LevelInfo{
+		Level:      tw.level(),
+		Start:      tw.toTime(tw.start),
+		End:        tw.toTime(tw.start + tw.bucketSize*int64(len(tw.ring))),
+		BucketSize: time.Duration(tw.bucketSize),
+		RingIdx:    tw.ringIdx,
+		Counts:     make([]int, len(tw.ring)),
+	}
+	for idx := tw.ringIdx; idx < len(tw.ring); idx++ {
+		li.Counts[idx] = tw.ring[idx].count
+		li.Length += tw.ring[idx].count
+	}
+	return li
+}
@@ -0,0 +1,35 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevels(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(3*time.Millisecond, nop)
+	tw.ScheduleEventIn(40*time.Millisecond, nop)
+	tw.AdvanceBy(time.Millisecond, 0)
+
+	levels := tw.Levels()
+	if len(levels) != 2 {
+		t.Fatalf("Expected 2 levels, but got %v", levels)
+	}
+	root, next := levels[0], levels[1]
+	if root.Level != 0 || !root.Start.Equal(start) || !root.End.Equal(start.Add(32*time.Millisecond)) ||
+		root.BucketSize != time.Millisecond || root.RingIdx != 1 || root.Length != 2 || root.Counts[3] != 2 {
+		t.Errorf("Unexpected root level %+v", root)
+	}
+	if next.Level != 1 || next.BucketSize != 32*time.Millisecond || next.Length != 1 || next.Counts[0] != 1 {
+		t.Errorf("Unexpected next level %+v", next)
+	}
+	stats := tw.Stats()
+	for idx := range levels {
+		if levels[idx].Length != stats.Levels[idx] {
+			t.Errorf("Expected Levels to agree with Stats, but got %v and %v", levels[idx].Length, stats.Levels[idx])
+		}
+	}
+}
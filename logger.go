@@ -0,0 +1,22 @@
+package gotimerwheel
+
+// Receives log messages about notable transitions inside a Timer
+// Wheel: a wheel of the hierarchy being created, events cascading
+// down from one wheel to the next, events refused for being in the
+// past, panics recovered from events and, while tracing is on (see
+// SetTracing), every event scheduled and settled. A *log.Logger
+// satisfies Logger. Printf is called synchronously, from within
+// whichever Timer Wheel method caused the message, except that panics
+// recovered by a worker pool (see WithWorkerPool) are logged from the
+// worker, so the Logger must then be safe for concurrent use.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Sets a Logger to be told about notable transitions inside the
+// Timer Wheel. By default nothing is logged.
+func WithLogger(logger Logger) Option {
+	return func(tw *TimerWheel) {
+		tw.logger = logger
+	}
+}
@@ -0,0 +1,56 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (rl *recordingLogger) Printf(format string, v ...interface{}) {
+	rl.lines = append(rl.lines, fmt.Sprintf(format, v...))
+}
+
+func (rl *recordingLogger) count(substr string) int {
+	count := 0
+	for _, line := range rl.lines {
+		if strings.Contains(line, substr) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLogger(t *testing.T) {
+	start := time.Unix(0, 0)
+	rl := &recordingLogger{}
+	tw := NewTimerWheel(start, 1, WithLogger(rl), WithPanicPolicy(PanicSwallow, nil))
+	nop := func(*time.Time) {}
+	tw.ScheduleEventAt(time.Unix(0, ringLength+3), nop)
+	tw.ScheduleEventAt(time.Unix(0, ringLength+4), func(*time.Time) { panic("boom") })
+	if rl.count("created level 1") != 1 {
+		t.Errorf("Expected the creation of level 1 to be logged, but got %v", rl.lines)
+	}
+	tw.AdvanceTo(time.Unix(0, ringLength+5), 0)
+	if rl.count("cascaded 2 events down from level 1") != 1 {
+		t.Errorf("Expected the cascade to be logged, but got %v", rl.lines)
+	}
+	if rl.count("recovered panic") != 1 || rl.count("boom") != 1 {
+		t.Errorf("Expected the panic to be logged, but got %v", rl.lines)
+	}
+	tw.ScheduleEventAt(start, nop)
+	if rl.count("refused event") != 1 {
+		t.Errorf("Expected the refused event to be logged, but got %v", rl.lines)
+	}
+	// empty buckets cascading are not worth logging
+	lines := len(rl.lines)
+	tw.ScheduleEventAt(time.Unix(0, 3*ringLength), nop)
+	tw.AdvanceTo(time.Unix(0, 3*ringLength), 0)
+	if rl.count("cascaded") != 2 || len(rl.lines) != lines+2 {
+		t.Errorf("Expected only the level creation and one cascade to be logged, but got %v", rl.lines[lines:])
+	}
+}
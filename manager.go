@@ -0,0 +1,141 @@
+package gotimerwheel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Manager drives several Timer Wheels, which may have different
+// bucket sizes, clocks and so on, from a single goroutine: the one
+// calling Run. Timer Wheels may be added and removed at any time and
+// from any goroutine, but whilst a Timer Wheel belongs to a Manager it
+// is the Manager's goroutine which drives it, so other goroutines
+// should schedule events on it through an Intake (see NewIntake).
+type Manager struct {
+	interval time.Duration
+	lock     sync.Mutex
+	// Signalled whenever a Timer Wheel has been advanced.
+	idle   *sync.Cond
+	wheels []*TimerWheel
+	// The Timer Wheel being advanced, if any.
+	current *TimerWheel
+}
+
+// Creates an empty Manager which, once Run, advances its Timer Wheels
+// every interval. Interval should be no longer than the smallest
+// bucketSize of the Timer Wheels, and must be greater than 0.
+func NewManager(interval time.Duration) *Manager {
+	if interval <= 0 {
+		panic("Manager interval must be greater than 0")
+	}
+	m := &Manager{interval: interval}
+	m.idle = sync.NewCond(&m.lock)
+	return m
+}
+
+// Adds the Timer Wheel to those driven by the Manager. Adding a Timer
+// Wheel which is already driven by the Manager does nothing. From
+// then on, only the Manager's goroutine should drive the Timer Wheel.
+func (m *Manager) Add(tw *TimerWheel) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, other := range m.wheels {
+		if other == tw {
+			return
+		}
+	}
+	m.wheels = append(m.wheels, tw)
+}
+
+// Removes the Timer Wheel from those driven by the Manager, returning
+// false if it was not one of them. Once Remove returns, the Manager
+// is not advancing the Timer Wheel and never will again, so the
+// caller may drive it themselves. Remove may be called from events
+// invoked by the Manager, but not from those of the Timer Wheel being
+// removed, as it would wait for itself.
+func (m *Manager) Remove(tw *TimerWheel) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for idx, other := range m.wheels {
+		if other == tw {
+			copy(m.wheels[idx:], m.wheels[idx+1:])
+			m.wheels[len(m.wheels)-1] = nil
+			m.wheels = m.wheels[:len(m.wheels)-1]
+			for m.current == tw {
+				m.idle.Wait()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the number of Timer Wheels driven by the Manager.
+func (m *Manager) Length() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.wheels)
+}
+
+// Advances every Timer Wheel driven by the Manager to the current time
+// of its Clock, with AdvanceToNow, one after another. Returns the
+// total number of events invoked. Run calls Tick every interval; it
+// is exported for tests and for callers with a loop of their own. The
+// Timer Wheels' events may add and remove Timer Wheels: one added
+// during a Tick is first advanced by the next.
+func (m *Manager) Tick() int {
+	m.lock.Lock()
+	wheels := append([]*TimerWheel(nil), m.wheels...)
+	m.lock.Unlock()
+	count := 0
+	for _, tw := range wheels {
+		count += m.advance(tw)
+	}
+	return count
+}
+
+// Advances the Timer Wheel unless it has been removed since the Tick
+// began.
+func (m *Manager) advance(tw *TimerWheel) int {
+	if !m.begin(tw) {
+		return 0
+	}
+	defer m.end()
+	return tw.AdvanceToNow(0)
+}
+
+func (m *Manager) begin(tw *TimerWheel) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, other := range m.wheels {
+		if other == tw {
+			m.current = tw
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) end() {
+	m.lock.Lock()
+	m.current = nil
+	m.idle.Broadcast()
+	m.lock.Unlock()
+}
+
+// Calls Tick every interval until ctx is done, then returns ctx.Err().
+// The calling goroutine becomes the one driving every Timer Wheel of
+// the Manager. Only one call to Run should be made at a time.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.Tick()
+		}
+	}
+}
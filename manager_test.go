@@ -0,0 +1,67 @@
+package gotimerwheel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &manualClock{now: start}
+	fine := NewTimerWheel(start, time.Millisecond, WithClock(clock))
+	coarse := NewTimerWheel(start, time.Second, WithClock(clock))
+	m := NewManager(time.Millisecond)
+	m.Add(fine)
+	m.Add(coarse)
+	m.Add(fine)
+	if m.Length() != 2 {
+		t.Fatalf("Expected 2 Timer Wheels, but got %v", m.Length())
+	}
+	fired := 0
+	fine.ScheduleEventAt(start.Add(5*time.Millisecond), func(*time.Time) { fired++ })
+	coarse.ScheduleEventAt(start.Add(3*time.Second), func(*time.Time) { fired++ })
+	late := NewTimerWheel(start, time.Millisecond, WithClock(clock))
+	late.ScheduleEventAt(start.Add(time.Minute), func(*time.Time) { fired++ })
+	// events may add and remove Timer Wheels
+	coarse.ScheduleEventAt(start.Add(4*time.Second), func(*time.Time) {
+		m.Remove(fine)
+		m.Add(late)
+	})
+
+	clock.now = start.Add(10 * time.Millisecond)
+	if m.Tick() != 1 || !fine.Now().Equal(clock.now) || !coarse.Now().Equal(clock.now) {
+		t.Fatal("Expected every Timer Wheel to be advanced to the Clock's time")
+	}
+	clock.now = start.Add(5 * time.Second)
+	if m.Tick() != 2 || fired != 2 || m.Length() != 2 {
+		t.Fatalf("Expected the coarse Timer Wheel's events to swap the fine one for the late one, but %v fired", fired)
+	}
+	clock.now = start.Add(2 * time.Minute)
+	if m.Tick() != 1 || fired != 3 || !fine.Now().Equal(start.Add(5*time.Second)) {
+		t.Fatal("Expected the added Timer Wheel to be advanced, and the removed one not")
+	}
+	if !m.Remove(coarse) || m.Remove(coarse) || m.Length() != 1 {
+		t.Error("Expected Remove to report whether the Timer Wheel was driven by the Manager")
+	}
+}
+
+func TestManagerRun(t *testing.T) {
+	m := NewManager(time.Millisecond)
+	tw := NewTimerWheel(time.Now(), time.Millisecond)
+	in := tw.NewIntake(nil)
+	m.Add(tw)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+	fired := make(chan struct{})
+	in.ScheduleEventIn(time.Millisecond, func(*time.Time) { close(fired) })
+	<-fired
+	if !m.Remove(tw) {
+		t.Error("Expected the Timer Wheel to be removed")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected Run to return %v, but got %v", context.Canceled, err)
+	}
+}
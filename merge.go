@@ -0,0 +1,69 @@
+package gotimerwheel
+
+import (
+	"errors"
+)
+
+var (
+	MergeWithSelf = errors.New("Cannot merge a Timer Wheel with itself")
+)
+
+// Moves every scheduled event of other into tw, leaving other
+// empty. The two Timer Wheels may have different start times, current
+// times and bucket sizes: each event keeps its scheduled time. Keyed
+// events keep their keys, replacing any event in tw with the same
+// key. If any of other's events is in the past of tw's current time
+// then, unless another policy has been set with WithPastPolicy,
+// ScheduledInPast is returned and nothing is moved: advancing other
+// to tw's current time first will invoke such events. Helpers
+// such as DeadlineTree and StagedTimeout built on other lose track of
+// events that are moved. Moved events count as cancelled in other's
+// Stats. Returns WheelFull, moving nothing, if tw does not have the
+// capacity for them (see WithCapacity), and likewise TooFarInFuture
+// if any is beyond tw's horizon (see WithMaxLevels).
+func (tw *TimerWheel) Merge(other *TimerWheel) error {
+	if tw == other {
+		return MergeWithSelf
+	}
+	if tw.closed {
+		return Closed
+	}
+	events := make([]*eventNode, 0, other.Length())
+	other.forEachEvent(func(event *eventNode) bool {
+		events = append(events, event)
+		return true
+	})
+	if len(events) > 0 {
+		if err := tw.refusePast(events[0].at); err != nil {
+			return err
+		}
+		if tw.refuseBeyond(events[len(events)-1].at) {
+			return TooFarInFuture
+		}
+	}
+	if tw.full(len(events)) {
+		return WheelFull
+	}
+	other.stats.cancelled += uint64(len(events))
+	other.removeAll()
+	for key := range other.keys {
+		delete(other.keys, key)
+	}
+	other.duplicates.clear()
+	for _, event := range events {
+		other.forgetID(event)
+		if event.keyed {
+			tw.CancelKey(event.key)
+			if tw.keys == nil {
+				tw.keys = make(map[string]*eventNode)
+			}
+			tw.keys[event.key] = event
+		}
+	}
+	events, past := tw.splitPast(events)
+	tw.scheduleSortedEvents(events)
+	for _, event := range past {
+		tw.invokePast(event)
+	}
+	return nil
+}
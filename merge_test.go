@@ -0,0 +1,65 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 10), 5)
+	other := NewTimerWheel(time.Unix(0, 17), 3)
+	invoked := []int64{}
+	schedule := func(wheel *TimerWheel, ns int64) {
+		wheel.ScheduleEventAt(time.Unix(0, ns), func(now *time.Time) {
+			if now.Before(time.Unix(0, ns)) {
+				t.Errorf("Event at %v invoked early at %v", ns, now)
+			}
+			invoked = append(invoked, ns)
+		})
+	}
+	for _, ns := range []int64{12, 20, 500, 5000} {
+		schedule(tw, ns)
+	}
+	for _, ns := range []int64{17, 20, 21, 400, 90000} {
+		schedule(other, ns)
+	}
+	tw.ScheduleKeyedEventAt("key", time.Unix(0, 30), func(*time.Time) { t.Error("Replaced keyed event invoked") })
+	other.ScheduleKeyedEventAt("key", time.Unix(0, 31), func(*time.Time) { invoked = append(invoked, 31) })
+
+	if err := tw.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+	assertNowLength(t, tw, time.Unix(0, 10), 10)
+	assertNowLength(t, other, time.Unix(0, 17), 0)
+	if other.CancelKey("key") {
+		t.Error("Expected key to have moved")
+	}
+	for tw.Length() > 0 {
+		tw.AdvanceToNextEvent()
+	}
+	expected := []int64{12, 17, 20, 20, 21, 31, 400, 500, 5000, 90000}
+	if len(invoked) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, invoked)
+	}
+	for idx := range expected {
+		if invoked[idx] != expected[idx] {
+			t.Fatalf("Expected %v, but got %v", expected, invoked)
+		}
+	}
+	if err := tw.Merge(tw); err != MergeWithSelf {
+		t.Errorf("Expected MergeWithSelf, but got %v", err)
+	}
+}
+
+func TestMergeInPast(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 100), 5, WithNilEvents())
+	other := NewTimerWheel(time.Unix(0, 0), 5, WithNilEvents())
+	other.ScheduleEventAt(time.Unix(0, 50), nil)
+	other.ScheduleEventAt(time.Unix(0, 150), nil)
+	if err := tw.Merge(other); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	assertNowLength(t, other, time.Unix(0, 0), 2)
+	assertNowLength(t, tw, time.Unix(0, 100), 0)
+}
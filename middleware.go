@@ -0,0 +1,31 @@
+package gotimerwheel
+
+// A Middleware wraps the invocation of events, for example to open a
+// tracing span, time the event or log it. It is given the event about
+// to be invoked and returns the Event to invoke in its place, which
+// should call next (unless the middleware means to suppress the
+// event).
+type Middleware func(next Event) Event
+
+// Wraps every event the Timer Wheel invokes in the supplied
+// middlewares, whoever scheduled it and however it was scheduled: as
+// a plain Event, an EventE, a RecurringEvent, a ChainedEvent and so
+// on. The first middleware is outermost. Middlewares run inside the
+// Timer Wheel's panic recovery, so a middleware may recover panics
+// itself before the PanicPolicy sees them. With an aggregation
+// notifier (see WithAggregation) events are never invoked, so
+// middlewares are not used. Supplying this option more than once
+// appends to the chain.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(tw *TimerWheel) {
+		tw.middleware = append(tw.middleware, middlewares...)
+	}
+}
+
+// Returns e wrapped in the Timer Wheel's middlewares.
+func (tw *TimerWheel) intercept(e Event) Event {
+	for idx := len(tw.middleware) - 1; idx >= 0; idx-- {
+		e = tw.middleware[idx](e)
+	}
+	return e
+}
@@ -0,0 +1,93 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	start := time.Unix(0, 0)
+	var trace []string
+	tracing := func(name string) Middleware {
+		return func(next Event) Event {
+			return func(now *time.Time) {
+				trace = append(trace, name+" in")
+				next(now)
+				trace = append(trace, name+" out")
+			}
+		}
+	}
+	tw := NewTimerWheel(start, time.Millisecond,
+		WithMiddleware(tracing("outer")), WithMiddleware(tracing("inner")))
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { trace = append(trace, "event") })
+	tw.AdvanceBy(time.Millisecond, 0)
+	expected := []string{"outer in", "inner in", "event", "inner out", "outer out"}
+	if len(trace) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, trace)
+	}
+	for idx, step := range expected {
+		if trace[idx] != step {
+			t.Errorf("Expected %v, but got %v", expected, trace)
+		}
+	}
+
+	// every kind of event is wrapped
+	var wrapped int
+	counting := func(next Event) Event {
+		return func(now *time.Time) {
+			wrapped++
+			next(now)
+		}
+	}
+	tw = NewTimerWheel(start, time.Millisecond, WithMiddleware(counting))
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	tw.ScheduleEventInE(time.Millisecond, func(*time.Time) error { return errors.New("failed") })
+	tw.ScheduleRecurringEventIn(time.Millisecond, func(now time.Time) (time.Time, bool) {
+		return now.Add(time.Millisecond), now.Before(start.Add(2 * time.Millisecond))
+	})
+	tw.ScheduleKeyedEventIn("key", time.Millisecond, func(*time.Time) {})
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.AdvanceBy(time.Millisecond, 0)
+	if wrapped != 5 {
+		t.Errorf("Expected 5 wrapped invocations, but got %v", wrapped)
+	}
+	if errs := tw.Errors(); len(errs) != 1 {
+		t.Errorf("Expected the event's error to still be collected, but got %v", errs)
+	}
+
+	// a middleware may suppress an event, or recover its panic
+	var recovered interface{}
+	guarding := func(next Event) Event {
+		return func(now *time.Time) {
+			defer func() { recovered = recover() }()
+			next(now)
+		}
+	}
+	tw = NewTimerWheel(start, time.Millisecond, WithMiddleware(guarding))
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { panic("boom") })
+	tw.AdvanceBy(time.Millisecond, 0)
+	if recovered != "boom" {
+		t.Errorf("Expected the middleware to recover the panic, but got %v", recovered)
+	}
+}
+
+func TestMiddlewareWorkerPool(t *testing.T) {
+	start := time.Unix(0, 0)
+	var wrapped int64
+	tw := NewTimerWheel(start, time.Millisecond, WithWorkerPool(2), WithMiddleware(func(next Event) Event {
+		return func(now *time.Time) {
+			atomic.AddInt64(&wrapped, 1)
+			next(now)
+		}
+	}))
+	for idx := 0; idx < 4; idx++ {
+		tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.WaitForInflight()
+	if wrapped != 4 {
+		t.Errorf("Expected 4 wrapped invocations, but got %v", wrapped)
+	}
+}
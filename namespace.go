@@ -0,0 +1,32 @@
+package gotimerwheel
+
+// A Namespace is a named Group owned by the Timer Wheel, so that
+// several subsystems sharing one Timer Wheel can each count,
+// enumerate and cancel their own events. Unlike other Groups,
+// Namespaces keep track of Clear and Reset of the Timer Wheel. See
+// TimerWheel.Namespace.
+type Namespace struct {
+	*Group
+	name string
+}
+
+// Returns the Timer Wheel's Namespace with the given name, creating
+// it if necessary. Every call with the same name returns the same
+// Namespace. A clone of the Timer Wheel (see Clone) starts with no
+// Namespaces.
+func (tw *TimerWheel) Namespace(name string) *Namespace {
+	if ns, found := tw.namespaces[name]; found {
+		return ns
+	}
+	if tw.namespaces == nil {
+		tw.namespaces = make(map[string]*Namespace)
+	}
+	ns := &Namespace{Group: tw.NewGroup(), name: name}
+	tw.namespaces[name] = ns
+	return ns
+}
+
+// Returns the Namespace's name.
+func (ns *Namespace) Name() string {
+	return ns.name
+}
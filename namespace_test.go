@@ -0,0 +1,40 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNamespace(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	rpc, cache := tw.Namespace("rpc"), tw.Namespace("cache")
+	if tw.Namespace("rpc") != rpc || rpc.Name() != "rpc" {
+		t.Fatal("Expected the same Namespace for the same name")
+	}
+	nop := func(*time.Time) {}
+	rpc.ScheduleEventIn(3*time.Millisecond, nop)
+	rpc.ScheduleEventIn(time.Millisecond, nop)
+	cache.ScheduleEventIn(2*time.Millisecond, nop)
+	tw.ScheduleEventIn(2*time.Millisecond, nop)
+
+	var times []time.Time
+	rpc.ForEach(func(at time.Time, e Event) bool {
+		times = append(times, at)
+		return true
+	})
+	if len(times) != 2 || !times[0].Equal(start.Add(time.Millisecond)) || !times[1].Equal(start.Add(3*time.Millisecond)) {
+		t.Errorf("Unexpected rpc events %v", times)
+	}
+	if cache.Cancel() != 1 || cache.Length() != 0 || rpc.Length() != 2 || tw.Length() != 3 {
+		t.Errorf("Expected cancelling cache to leave rpc's events, but got %v and %v", rpc.Length(), tw.Length())
+	}
+	tw.Clear()
+	if rpc.Length() != 0 {
+		t.Errorf("Expected Clear to empty the Namespaces, but got %v", rpc.Length())
+	}
+	rpc.ScheduleEventIn(time.Millisecond, nop)
+	if clone := tw.Clone(); clone.Namespace("rpc").Length() != 0 || clone.Length() != 1 {
+		t.Error("Expected the clone to start with no Namespaces")
+	}
+}
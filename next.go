@@ -0,0 +1,83 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Returns the time of the earliest scheduled event (or with
+// WithApproximateExpiry, the time at which it will be invoked). If
+// there are no scheduled events then false is returned.
+func (tw *TimerWheel) NextEventAt() (time.Time, bool) {
+	at, found := tw.nextEventAt()
+	if !found {
+		at, found = tw.overflow.nextEventAt()
+	}
+	if !found {
+		return time.Time{}, false
+	}
+	return tw.toTime(tw.invokedAt(at)), true
+}
+
+// Advances the Timer Wheel's current time to the time of the earliest
+// scheduled event, invoking that event and every other event
+// scheduled for that same time. Returns the new current time and the
+// number of events invoked. If there are no scheduled events then
+// nothing happens and the current time is returned. Intervening empty
+// parts of the Timer Wheel are skipped efficiently, so this is the
+// natural main loop of a discrete event simulation.
+func (tw *TimerWheel) AdvanceToNextEvent() (time.Time, int) {
+	tw.drainIntakes()
+	at, found := tw.NextEventAt()
+	if !found {
+		return tw.Now(), 0
+	}
+	count := tw.AdvanceTo(at, 0)
+	return tw.Now(), count
+}
+
+func (tw *TimerWheel) nextEventAt() (int64, bool) {
+	for level := tw; level != nil; level = level.next {
+		level.completeCascade()
+		for idx := level.ringIdx; idx < len(level.ring); idx++ {
+			b := &level.ring[idx]
+			if b.count == 0 {
+				continue
+			}
+			// Only the root wheel's buckets are sorted, and even they
+			// may not be yet, so in general we have to look at every
+			// event in the bucket.
+			earliest := b.first().at
+			b.each(func(event *eventNode) bool {
+				if event.at < earliest {
+					earliest = event.at
+				}
+				return true
+			})
+			return earliest, true
+		}
+	}
+	return 0, false
+}
+
+// Repeatedly advances the Timer Wheel to its earliest scheduled event,
+// as AdvanceToNextEvent does, until no scheduled events remain or
+// limit events have been invoked. If limit is 0 there is no limit,
+// in which case recurring events, or events which always schedule
+// further events, mean RunUntilEmpty never returns. Returns the new
+// current time and the number of events invoked.
+func (tw *TimerWheel) RunUntilEmpty(limit int) (time.Time, int) {
+	count := 0
+	for limit == 0 || count < limit {
+		tw.drainIntakes()
+		at, found := tw.NextEventAt()
+		if !found {
+			break
+		}
+		remaining := 0
+		if limit > 0 {
+			remaining = limit - count
+		}
+		count += tw.AdvanceTo(at, remaining)
+	}
+	return tw.Now(), count
+}
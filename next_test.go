@@ -0,0 +1,124 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestAdvanceToNextEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	if now, count := tw.AdvanceToNextEvent(); !now.Equal(start) || count != 0 {
+		t.Errorf("Expected nothing to happen, but got %v %v", now, count)
+	}
+	// far enough out to need several next wheels
+	times := []time.Time{
+		start.Add(3 * time.Millisecond),
+		start.Add(3 * time.Millisecond),
+		start.Add(time.Hour),
+		start.Add(365 * 24 * time.Hour),
+	}
+	for _, at := range times {
+		tw.ScheduleEventAt(at, func(*time.Time) {})
+	}
+	expectedCounts := []int{2, 0, 1, 1}
+	for idx, at := range times {
+		if idx == 1 {
+			continue
+		}
+		if next, found := tw.NextEventAt(); !found || !next.Equal(at) {
+			t.Errorf("Expected next event at %v, but got %v", at, next)
+		}
+		if now, count := tw.AdvanceToNextEvent(); !now.Equal(at) || count != expectedCounts[idx] {
+			t.Errorf("Expected to advance to %v invoking %v, but got %v and %v", at, expectedCounts[idx], now, count)
+		}
+	}
+	if _, found := tw.NextEventAt(); found || !tw.IsEmpty() {
+		t.Error("Expected no more events")
+	}
+}
+
+// Schedules events at random times over a wide range and then
+// advances in random, sometimes huge, steps, checking that every
+// event is invoked exactly once and never early or out of order.
+func TestAdvanceRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iteration := 0; iteration < 50; iteration++ {
+		start := time.Unix(0, rng.Int63n(1000))
+		bucketSize := time.Duration(1 + rng.Int63n(10))
+		tw := NewTimerWheel(start, bucketSize)
+		fired := make(map[int]bool)
+		var last time.Time
+		count := 1000
+		for idx := 0; idx < count; idx++ {
+			idx := idx
+			// mostly near, sometimes very far
+			at := start.Add(time.Duration(rng.Int63n(int64(bucketSize) * ringLength * 4)))
+			if rng.Intn(10) == 0 {
+				at = start.Add(time.Duration(rng.Int63n(1 << 40)))
+			}
+			tw.ScheduleEventAt(at, func(now *time.Time) {
+				if fired[idx] {
+					t.Errorf("Event %v invoked twice", idx)
+				}
+				fired[idx] = true
+				if now.Before(at) {
+					t.Errorf("Event at %v invoked early at %v", at, now)
+				}
+				if at.Before(last) {
+					t.Errorf("Event at %v invoked after event at %v", at, last)
+				}
+				last = at
+			})
+		}
+		for !tw.IsEmpty() {
+			step := time.Duration(rng.Int63n(int64(bucketSize) * ringLength * 2))
+			if rng.Intn(4) == 0 {
+				step = time.Duration(rng.Int63n(1 << 38))
+			}
+			tw.AdvanceBy(step, 0)
+			if next, found := tw.NextEventAt(); found && !next.After(tw.Now()) {
+				t.Fatalf("Event at %v left behind at %v", next, tw.Now())
+			}
+		}
+		if len(fired) != count {
+			t.Fatalf("Expected %v events invoked, but got %v", count, len(fired))
+		}
+	}
+}
+
+func TestRunUntilEmpty(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	// each event schedules the next, so the wheel is only empty once
+	// the chain ends
+	var step Event
+	invoked := 0
+	step = func(*time.Time) {
+		invoked++
+		if invoked < 10 {
+			tw.ScheduleEventIn(time.Duration(invoked)*time.Hour, step)
+		}
+	}
+	tw.ScheduleEventIn(time.Millisecond, step)
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+
+	now, count := tw.RunUntilEmpty(4)
+	if count != 4 || invoked != 3 {
+		t.Errorf("Expected to stop after 4 events, but got %v (%v steps)", count, invoked)
+	}
+	if expected := start.Add(time.Millisecond + 3*time.Hour); !now.Equal(expected) {
+		t.Errorf("Expected to be at %v, but got %v", expected, now)
+	}
+	now, count = tw.RunUntilEmpty(0)
+	if count != 7 || invoked != 10 || !tw.IsEmpty() {
+		t.Errorf("Expected to run the rest of the chain, but got %v (%v steps)", count, invoked)
+	}
+	if expected := start.Add(time.Millisecond + 45*time.Hour); !now.Equal(expected) {
+		t.Errorf("Expected to be at %v, but got %v", expected, now)
+	}
+	if _, count := tw.RunUntilEmpty(0); count != 0 {
+		t.Errorf("Expected nothing to happen, but got %v", count)
+	}
+}
@@ -0,0 +1,32 @@
+package gotimerwheel
+
+import (
+	"errors"
+)
+
+var (
+	NilEvent = errors.New("Requested event has no function to invoke")
+)
+
+// By default, scheduling a nil Event (or EventE, RecurringEvent and
+// so on) is refused with NilEvent, rather than panicking once the
+// event is due. With this option, nil is accepted as a placeholder
+// which does nothing when invoked, but is otherwise just like any
+// other event: it is counted by Length and Stats, tagged events are
+// counted by aggregation (see WithAggregation), and so on.
+func WithNilEvents() Option {
+	return func(tw *TimerWheel) {
+		tw.nilEvents = true
+	}
+}
+
+// Returns true if the event has nothing to invoke.
+func (event *eventNode) isNil() bool {
+	return event.fun == nil && event.funE == nil && event.recurring == nil && event.chained == nil
+}
+
+// Returns true if an event with nothing to invoke should be refused
+// with NilEvent.
+func (tw *TimerWheel) refuseNil(event *eventNode) bool {
+	return !tw.nilEvents && event.isNil()
+}
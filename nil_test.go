@@ -0,0 +1,66 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNilEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	if err := tw.ScheduleEventIn(time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if err := tw.ScheduleRecurringEventIn(time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if err := tw.ScheduleEventInE(time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if err := tw.ScheduleEvents([]ScheduledEvent{{At: start, Event: nop}, {At: start}}); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if _, err := NewEvent(nil).At(start).Build(tw); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if _, err := tw.ScheduleFutureIn(time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if _, err := NewCoalescer(tw, time.Millisecond, CoalesceEarliest).ScheduleEventIn("key", time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	// a keyed event is left in place
+	tw.ScheduleKeyedEventIn("key", time.Millisecond, nop)
+	if err := tw.ScheduleKeyedEventIn("key", time.Millisecond, nil); err != NilEvent {
+		t.Errorf("Expected NilEvent, but got %v", err)
+	}
+	if tw.SetKeyFunc("key", nil) {
+		t.Error("Expected SetKeyFunc to refuse nil")
+	}
+	if tw.Length() != 1 || tw.Stats().Scheduled != 1 {
+		t.Errorf("Expected only the keyed event to be scheduled, but got %v", tw.Length())
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+
+	// as placeholders, nil events are counted but do nothing
+	for _, options := range [][]Option{{WithNilEvents()}, {WithNilEvents(), WithWorkerPool(2)}} {
+		tw = NewTimerWheel(start, time.Millisecond, options...)
+		tw.ScheduleEventIn(time.Millisecond, nil)
+		tw.ScheduleKeyedEventIn("key", time.Millisecond, nop)
+		if !tw.SetKeyFunc("key", nil) {
+			t.Error("Expected SetKeyFunc to accept nil")
+		}
+		tw.ScheduleEvents([]ScheduledEvent{{At: start.Add(time.Millisecond)}})
+		f, _ := tw.ScheduleFutureIn(time.Millisecond, nil)
+		if count := tw.AdvanceBy(time.Millisecond, 0); count != 4 || tw.Stats().Fired != 4 {
+			t.Errorf("Expected 4 placeholders invoked, but got %v", count)
+		}
+		tw.WaitForInflight()
+		select {
+		case <-f.Done():
+		default:
+			t.Error("Expected the placeholder's Future to be completed")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Receives notifications of what happens inside a Timer Wheel, for
+// example to feed a tracing or metrics system. Methods are called
+// synchronously, from within whichever Timer Wheel method caused
+// them, and must not schedule or cancel events.
+type Observer interface {
+	// An event for the indicated time has been scheduled.
+	OnScheduled(at time.Time)
+	// An event scheduled for the indicated time is about to be
+	// invoked. Lag is how far the Timer Wheel's current time is
+	// beyond the event's time.
+	OnFired(at time.Time, lag time.Duration)
+	// A bucket of the wheel at the indicated level of the hierarchy
+	// has been moved down into the level below. Level 0 is the root
+	// wheel, so level is always at least 1.
+	OnCascade(level int)
+	// An attempt to schedule an event for the indicated time has been
+	// refused with ScheduledInPast.
+	OnDroppedPast(at time.Time)
+}
+
+// Sets an Observer to be notified of events being scheduled, fired
+// and cascaded.
+func WithObserver(observer Observer) Option {
+	return func(tw *TimerWheel) {
+		tw.observer = observer
+	}
+}
+
+func (tw *TimerWheel) observeScheduled(at int64) {
+	if observer := tw.root.observer; observer != nil {
+		observer.OnScheduled(tw.toTime(at))
+	}
+}
+
+func (tw *TimerWheel) observeDroppedPast(at time.Time) {
+	if observer := tw.root.observer; observer != nil {
+		observer.OnDroppedPast(at)
+	}
+}
+
+// Returns the level of this wheel within the hierarchy. The root is
+// level 0.
+func (tw *TimerWheel) level() int {
+	level := 0
+	for wheel := tw.root; wheel != tw; wheel = wheel.next {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,59 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	scheduled, dropped []time.Time
+	lags               []time.Duration
+	cascades           map[int]int
+}
+
+func (ro *recordingObserver) OnScheduled(at time.Time) {
+	ro.scheduled = append(ro.scheduled, at)
+}
+
+func (ro *recordingObserver) OnFired(at time.Time, lag time.Duration) {
+	ro.lags = append(ro.lags, lag)
+}
+
+func (ro *recordingObserver) OnCascade(level int) {
+	ro.cascades[level]++
+}
+
+func (ro *recordingObserver) OnDroppedPast(at time.Time) {
+	ro.dropped = append(ro.dropped, at)
+}
+
+func TestObserver(t *testing.T) {
+	start := time.Unix(0, 0)
+	ro := &recordingObserver{cascades: make(map[int]int)}
+	tw := NewTimerWheel(start, 1, WithObserver(ro))
+	nop := func(*time.Time) {}
+	tw.ScheduleEventAt(time.Unix(0, 3), nop)
+	tw.ScheduleKeyedEventAt("key", time.Unix(0, 2*ringLength*ringLength+5), nop)
+	tw.ScheduleEvents([]ScheduledEvent{{At: time.Unix(0, 7), Event: nop}})
+	if len(ro.scheduled) != 3 {
+		t.Errorf("Expected 3 scheduled, but got %v", ro.scheduled)
+	}
+
+	tw.AdvanceTo(time.Unix(0, 10), 0)
+	if len(ro.lags) != 2 || ro.lags[0] != 7 || ro.lags[1] != 3 {
+		t.Errorf("Expected lags of 7 and 3, but got %v", ro.lags)
+	}
+	tw.ScheduleEventAt(time.Unix(0, 9), nop)
+	tw.ScheduleKeyedEventAt("other", time.Unix(0, 2), nop)
+	if len(ro.dropped) != 2 || !ro.dropped[0].Equal(time.Unix(0, 9)) {
+		t.Errorf("Expected 2 dropped, but got %v", ro.dropped)
+	}
+
+	tw.AdvanceTo(time.Unix(0, 2*ringLength*ringLength+5), 0)
+	if len(ro.lags) != 3 || ro.lags[2] != 0 {
+		t.Errorf("Expected a third event with no lag, but got %v", ro.lags)
+	}
+	if ro.cascades[1] == 0 || ro.cascades[2] == 0 || ro.cascades[0] != 0 {
+		t.Errorf("Expected cascades from levels 1 and 2 only, but got %v", ro.cascades)
+	}
+}
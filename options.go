@@ -0,0 +1,33 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Options modify the behaviour of a Timer Wheel. They are supplied to
+// NewTimerWheel.
+type Option func(*TimerWheel)
+
+// By default, bucket boundaries are relative to the startAt time
+// given to NewTimerWheel. With this option, the first bucket instead
+// starts at the latest multiple of alignment (measured from the Unix
+// epoch) that is not after startAt. If alignment is the bucketSize
+// (or a multiple of it), every bucket boundary is a round time, so
+// Timer Wheels created at different times but with the same
+// bucketSize place the same timestamps in the same buckets.
+func WithAlignment(alignment time.Duration) Option {
+	if alignment <= 0 {
+		panic("TimerWheel alignment must be greater than 0")
+	}
+	return func(tw *TimerWheel) {
+		tw.alignment = alignment
+	}
+}
+
+func alignTime(t int64, alignment time.Duration) int64 {
+	offset := t % int64(alignment)
+	if offset < 0 {
+		offset += int64(alignment)
+	}
+	return t - offset
+}
@@ -0,0 +1,38 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlignment(t *testing.T) {
+	bucketSize := time.Duration(10)
+	twA := NewTimerWheel(time.Unix(0, 7), bucketSize, WithAlignment(bucketSize))
+	twB := NewTimerWheel(time.Unix(0, 23), bucketSize, WithAlignment(bucketSize))
+	if twA.start != 0 || twB.start != 20 {
+		t.Errorf("Expected aligned starts, but found %v and %v", twA.start, twB.start)
+	}
+	// the current time is not affected by alignment
+	assertNowLength(t, twA, time.Unix(0, 7), 0)
+	assertNowLength(t, twB, time.Unix(0, 23), 0)
+
+	// before the epoch, we still align downwards
+	twC := NewTimerWheel(time.Unix(0, -7), bucketSize, WithAlignment(bucketSize))
+	if twC.start != -10 {
+		t.Errorf("Expected aligned start, but found %v", twC.start)
+	}
+
+	fired := 0
+	at := time.Unix(0, 35)
+	twA.ScheduleEventAt(at, func(*time.Time) { fired++ })
+	twB.ScheduleEventAt(at, func(*time.Time) { fired++ })
+	// both land in the bucket covering [30, 40)
+	if twA.ring[3].count != 1 || twB.ring[1].count != 1 {
+		t.Errorf("Expected events in buckets starting at 30: %v, %v", twA, twB)
+	}
+	twA.AdvanceTo(at, 0)
+	twB.AdvanceTo(at, 0)
+	if fired != 2 {
+		t.Errorf("Expected 2 events invoked, but got %v", fired)
+	}
+}
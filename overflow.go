@@ -0,0 +1,175 @@
+package gotimerwheel
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	TooFarInFuture = errors.New("Requested event to be scheduled beyond the Timer Wheel's horizon")
+)
+
+// Events beyond the end of the deepest wheel permitted by
+// WithOverflowHeap.
+type overflow struct {
+	// 0 if the hierarchy is unbounded.
+	levels int
+	// Set by WithMaxLevels, in which case there are never any events
+	// in the heap.
+	reject bool
+	events overflowHeap
+	seq    uint64
+}
+
+type overflowEntry struct {
+	event *eventNode
+	// Events for the same time are kept in order of scheduling.
+	seq uint64
+}
+
+type overflowHeap []overflowEntry
+
+func (h overflowHeap) Len() int { return len(h) }
+func (h overflowHeap) Less(a, b int) bool {
+	if ea, eb := h[a].event, h[b].event; ea.at != eb.at || ea.priority != eb.priority {
+		return before(ea, eb, nil)
+	}
+	return h[a].seq < h[b].seq
+}
+func (h overflowHeap) Swap(a, b int)       { h[a], h[b] = h[b], h[a] }
+func (h *overflowHeap) Push(x interface{}) { *h = append(*h, x.(overflowEntry)) }
+func (h *overflowHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = overflowEntry{}
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// By default the hierarchy of wheels grows for as long as it takes to
+// reach the furthest-future event, so a handful of events scheduled
+// years out create a long chain of nested wheels which every
+// Length, IsEmpty and advance has to walk. With this option, the
+// hierarchy is limited to levels wheels (including the root), and
+// events beyond the end of the last of them are held in a single
+// min-heap until the hierarchy catches up with them. Levels must be
+// at least 1.
+func WithOverflowHeap(levels int) Option {
+	if levels < 1 {
+		panic("TimerWheel overflow heap levels must be at least 1")
+	}
+	return func(tw *TimerWheel) {
+		tw.overflow.levels = levels
+		tw.overflow.reject = false
+	}
+}
+
+// Like WithOverflowHeap, limits the hierarchy to levels wheels
+// (including the root), but refuses events beyond the end of the
+// last of them with TooFarInFuture: the horizon. A buggy caller
+// scheduling an event a century ahead then gets an error rather
+// than a hierarchy which slows every advance. The horizon moves on as
+// the Timer Wheel advances, and with a bucketSize of a millisecond
+// four levels reach more than a week ahead. Levels must be at least
+// 1. This replaces any earlier WithOverflowHeap, and vice versa.
+func WithMaxLevels(levels int) Option {
+	if levels < 1 {
+		panic("TimerWheel max levels must be at least 1")
+	}
+	return func(tw *TimerWheel) {
+		tw.overflow.levels = levels
+		tw.overflow.reject = true
+	}
+}
+
+// Returns true if an event for at should be refused with
+// TooFarInFuture.
+func (tw *TimerWheel) refuseBeyond(at int64) bool {
+	return tw.overflow.reject && at >= tw.horizon()
+}
+
+// Returns the end of the ring of the last level permitted, whether
+// or not that level has been created yet.
+func (tw *TimerWheel) horizon() int64 {
+	level := tw
+	width := tw.ringWidth()
+	end := tw.start + width
+	for n := 1; n < tw.overflow.levels; n++ {
+		if level.next != nil {
+			level = level.next
+			width = level.ringWidth()
+			end = level.start + width
+			continue
+		}
+		// Each level's ring starts where the previous one's ends.
+		buckets := int64(tw.levelBuckets(n))
+		if width > math.MaxInt64/buckets || end > math.MaxInt64-width*buckets {
+			return math.MaxInt64
+		}
+		width *= buckets
+		end += width
+	}
+	return end
+}
+
+// Passes an event beyond the end of this wheel's ring on to the next
+// wheel, or to the overflow heap if this wheel is the last level that
+// may be created.
+func (tw *TimerWheel) scheduleBeyond(event *eventNode) {
+	o := &tw.root.overflow
+	if o.levels > 0 && tw.level() == o.levels-1 {
+		heap.Push(&o.events, overflowEntry{event: event, seq: o.seq})
+		o.seq++
+		return
+	}
+	tw.ensureNext()
+	tw.next.scheduleNestedEvent(event)
+}
+
+// Called once this wheel's window has moved on. If this is the last
+// level, moves in events from the overflow heap which now fall within
+// its ring.
+func (tw *TimerWheel) fetchFromOverflow() {
+	o := &tw.root.overflow
+	if len(o.events) == 0 || tw.level() != o.levels-1 {
+		return
+	}
+	end := tw.start + tw.ringWidth()
+	for len(o.events) > 0 && o.events[0].event.at < end {
+		tw.addEvent(heap.Pop(&o.events).(overflowEntry).event)
+	}
+}
+
+// Returns the time of the earliest event in the overflow heap.
+func (o *overflow) nextEventAt() (int64, bool) {
+	if len(o.events) == 0 {
+		return 0, false
+	}
+	return o.events[0].event.at, true
+}
+
+// Removes the event from the overflow heap. Returns false if it is
+// not there.
+func (o *overflow) removeEvent(event *eventNode) bool {
+	for idx, entry := range o.events {
+		if entry.event == event {
+			heap.Remove(&o.events, idx)
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the events in the overflow heap in the order in which they
+// would be invoked.
+func (o *overflow) sorted() []*eventNode {
+	entries := append(overflowHeap(nil), o.events...)
+	sort.Sort(entries)
+	events := make([]*eventNode, len(entries))
+	for idx, entry := range entries {
+		events[idx] = entry.event
+	}
+	return events
+}
@@ -0,0 +1,148 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestOverflowHeap(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithOverflowHeap(2))
+	invoked := []int{}
+	for idx, at := range []time.Duration{time.Hour, 3 * time.Millisecond, 24 * time.Hour, time.Hour, 365 * 24 * time.Hour} {
+		idx := idx
+		tw.ScheduleEventAt(start.Add(at), func(*time.Time) { invoked = append(invoked, idx) })
+	}
+	tw.ScheduleKeyedEventAt("cancel", start.Add(48*time.Hour), func(*time.Time) { t.Error("Cancelled event invoked") })
+	stats := tw.Stats()
+	if len(stats.Levels) != 2 || stats.Overflow != 5 || tw.Length() != 6 {
+		t.Errorf("Expected 2 levels and 5 events in the overflow heap, but got %v and %v", stats.Levels, stats.Overflow)
+	}
+	if !tw.CancelKey("cancel") || tw.Length() != 5 {
+		t.Errorf("Expected to cancel the event in the overflow heap, leaving 5, but got %v", tw.Length())
+	}
+	var forEach []time.Time
+	tw.ForEach(func(at time.Time, e Event) bool {
+		forEach = append(forEach, at)
+		return true
+	})
+	for idx := 1; idx < len(forEach); idx++ {
+		if forEach[idx].Before(forEach[idx-1]) {
+			t.Fatalf("ForEach out of order: %v", forEach)
+		}
+	}
+	clone := tw.Clone()
+	for {
+		if _, count := tw.AdvanceToNextEvent(); count == 0 {
+			break
+		}
+	}
+	expected := []int{1, 0, 3, 2, 4}
+	if len(invoked) != len(expected) {
+		t.Fatalf("Expected invocations %v, but got %v", expected, invoked)
+	}
+	for idx := range expected {
+		if invoked[idx] != expected[idx] {
+			t.Fatalf("Expected invocations %v, but got %v", expected, invoked)
+		}
+	}
+	if !tw.IsEmpty() || tw.Stats().Overflow != 0 {
+		t.Errorf("Expected no more events, but got %v", tw.Length())
+	}
+	if clone.Length() != 5 || clone.AdvanceBy(400*24*time.Hour, 0) != 5 {
+		t.Error("Expected the clone to keep its own events")
+	}
+
+	// with a single level, the heap may be all there is
+	single := NewTimerWheel(start, time.Millisecond, WithOverflowHeap(1))
+	single.ScheduleEventAt(start.Add(time.Hour), func(*time.Time) {})
+	if single.IsEmpty() {
+		t.Error("Expected a Timer Wheel with an event in the overflow heap not to be empty")
+	}
+}
+
+// Checks that limiting the hierarchy makes no difference to which
+// events are invoked when, nor to their order.
+func TestOverflowHeapRandom(t *testing.T) {
+	for _, levels := range []int{1, 2, 3} {
+		rng := rand.New(rand.NewSource(int64(levels)))
+		start := time.Unix(0, 0)
+		tw := NewTimerWheel(start, 3)
+		limited := NewTimerWheel(start, 3, WithOverflowHeap(levels))
+		var twInvoked, limitedInvoked []int
+		now := int64(0)
+		id := 0
+		for step := 0; step < 300; step++ {
+			for count := rng.Intn(5); count > 0; count-- {
+				at := time.Unix(0, now+rng.Int63n(100000))
+				eventID := id
+				id++
+				tw.ScheduleEventAt(at, func(*time.Time) { twInvoked = append(twInvoked, eventID) })
+				limited.ScheduleEventAt(at, func(*time.Time) { limitedInvoked = append(limitedInvoked, eventID) })
+			}
+			if levels := len(limited.Stats().Levels); levels > 3 {
+				t.Fatalf("Expected at most 3 levels, but got %v", levels)
+			}
+			now += rng.Int63n(2000)
+			if twCount, limitedCount := tw.AdvanceTo(time.Unix(0, now), 0), limited.AdvanceTo(time.Unix(0, now), 0); twCount != limitedCount {
+				t.Fatalf("Levels %v: at %v, expected %v events invoked, but got %v", levels, now, twCount, limitedCount)
+			}
+			if tw.Length() != limited.Length() {
+				t.Fatalf("Levels %v: at %v, expected %v events, but got %v", levels, now, tw.Length(), limited.Length())
+			}
+		}
+		tw.AdvanceBy(time.Second, 0)
+		limited.AdvanceBy(time.Second, 0)
+		if len(limitedInvoked) != id || !limited.IsEmpty() {
+			t.Fatalf("Levels %v: expected %v invocations, but got %v", levels, id, len(limitedInvoked))
+		}
+		for idx := range twInvoked {
+			if twInvoked[idx] != limitedInvoked[idx] {
+				t.Fatalf("Levels %v: invocation %v differs: %v vs %v", levels, idx, twInvoked[idx], limitedInvoked[idx])
+			}
+		}
+	}
+}
+
+func TestMaxLevels(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithMaxLevels(2))
+	// the root wheel's ring is 32ms, and the next 1024ms after that
+	horizon := start.Add(32*time.Millisecond + 1024*time.Millisecond)
+	nop := func(*time.Time) {}
+	if err := tw.ScheduleEventAt(horizon.Add(-time.Nanosecond), nop); err != nil {
+		t.Errorf("Expected an event just inside the horizon to be scheduled, but got %v", err)
+	}
+	if err := tw.ScheduleEventAt(horizon, nop); err != TooFarInFuture {
+		t.Errorf("Expected TooFarInFuture, but got %v", err)
+	}
+	if err := tw.ScheduleKeyedEventAt("key", time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC), nop); err != TooFarInFuture {
+		t.Errorf("Expected TooFarInFuture, but got %v", err)
+	}
+	if err := tw.ScheduleEvents([]ScheduledEvent{{At: start, Event: nop}, {At: horizon, Event: nop}}); err != TooFarInFuture || tw.Length() != 1 {
+		t.Errorf("Expected TooFarInFuture scheduling nothing, but got %v", err)
+	}
+	if _, err := NewEvent(nop).At(horizon).Build(tw); err != TooFarInFuture {
+		t.Errorf("Expected TooFarInFuture, but got %v", err)
+	}
+	other := NewTimerWheel(start, time.Millisecond)
+	other.ScheduleEventAt(start.Add(time.Hour), nop)
+	if err := tw.Merge(other); err != TooFarInFuture || other.Length() != 1 {
+		t.Errorf("Expected TooFarInFuture moving nothing, but got %v", err)
+	}
+	if len(tw.Stats().Levels) != 2 {
+		t.Errorf("Expected 2 levels, but got %v", tw.Stats().Levels)
+	}
+	// the horizon moves on with the Timer Wheel, as each level wraps
+	tw.AdvanceBy(2*time.Second, 0)
+	if err := tw.ScheduleEventAt(horizon.Add(time.Second), nop); err != nil {
+		t.Errorf("Expected the horizon to have moved on, but got %v", err)
+	}
+
+	// the horizon of a very deep hierarchy does not overflow
+	deep := NewTimerWheel(start, time.Nanosecond, WithMaxLevels(20))
+	if err := deep.ScheduleEventAt(time.Unix(0, 1<<62), nop); err != nil {
+		t.Errorf("Expected a deep hierarchy to reach far, but got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"time"
+)
+
+// What to do when an Event panics during AdvanceTo.
+type PanicPolicy int
+
+const (
+	// The panic is not recovered: it propagates out of AdvanceTo. This
+	// is the default. The panicking event is considered to have been
+	// invoked and the Timer Wheel remains consistent, so a subsequent
+	// call to AdvanceTo carries on from where the panic occurred.
+	PanicPropagate PanicPolicy = iota
+	// The panic is recovered and passed to the panic handler, if there
+	// is one, and AdvanceTo carries on invoking events.
+	PanicSwallow
+	// The panic is recovered and kept, and AdvanceTo carries on
+	// invoking events. Collected panics are retrieved with Panics.
+	PanicCollect
+)
+
+// A panic recovered from an Event.
+type EventPanic struct {
+	// The time the event was scheduled for.
+	At time.Time
+	// The value passed to panic.
+	Recovered interface{}
+}
+
+func (ep *EventPanic) Error() string {
+	return fmt.Sprintf("Event scheduled at %v panicked: %v", ep.At, ep.Recovered)
+}
+
+type panicRecovery struct {
+	policy    PanicPolicy
+	handler   func(*EventPanic)
+	collected []*EventPanic
+}
+
+// Sets the policy for events that panic when invoked. With
+// PanicSwallow, handler (if non-nil) is called with every recovered
+// panic. Handler is ignored for other policies.
+func WithPanicPolicy(policy PanicPolicy, handler func(*EventPanic)) Option {
+	return func(tw *TimerWheel) {
+		tw.panics.policy = policy
+		tw.panics.handler = handler
+	}
+}
+
+// Returns the panics collected under the PanicCollect policy since
+// the last call to Panics, in the order they occurred.
+func (tw *TimerWheel) Panics() []*EventPanic {
+	tw.collectPoolPanics()
+	collected := tw.panics.collected
+	tw.panics.collected = nil
+	return collected
+}
+
+func (tw *TimerWheel) recoverFrom(event *eventNode) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	pr := &tw.panics
+	ep := &EventPanic{At: tw.toTime(event.at), Recovered: recovered}
+	if logger := tw.logger; logger != nil {
+		logger.Printf("gotimerwheel: recovered panic: %v", ep)
+	}
+	switch pr.policy {
+	case PanicSwallow:
+		if pr.handler != nil {
+			pr.handler(ep)
+		}
+	case PanicCollect:
+		pr.collected = append(pr.collected, ep)
+	}
+}
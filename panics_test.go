@@ -0,0 +1,74 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func schedulePanicking(tw *TimerWheel, fired *[]int64) {
+	for _, ns := range []int64{1, 2, 3} {
+		ns := ns
+		tw.ScheduleEventAt(time.Unix(0, ns), func(*time.Time) {
+			*fired = append(*fired, ns)
+			if ns == 2 {
+				panic("boom")
+			}
+		})
+	}
+}
+
+func TestPanicPropagate(t *testing.T) {
+	fired := []int64{}
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	schedulePanicking(tw, &fired)
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("Expected panic to propagate, but got %v", recovered)
+			}
+		}()
+		tw.AdvanceTo(time.Unix(0, 10), 0)
+	}()
+	assertNowLength(t, tw, time.Unix(0, 10), 1)
+	// the wheel carries on from where it left off
+	if count := tw.AdvanceTo(time.Unix(0, 10), 0); count != 1 {
+		t.Errorf("Expected 1 event invoked, but got %v", count)
+	}
+	assertNowLength(t, tw, time.Unix(0, 10), 0)
+	if len(fired) != 3 || fired[2] != 3 {
+		t.Errorf("Expected all events invoked in order, but got %v", fired)
+	}
+}
+
+func TestPanicSwallow(t *testing.T) {
+	fired := []int64{}
+	handled := []*EventPanic{}
+	tw := NewTimerWheel(time.Unix(0, 0), 1,
+		WithPanicPolicy(PanicSwallow, func(ep *EventPanic) { handled = append(handled, ep) }))
+	schedulePanicking(tw, &fired)
+	if count := tw.AdvanceTo(time.Unix(0, 10), 0); count != 3 {
+		t.Errorf("Expected 3 events invoked, but got %v", count)
+	}
+	if len(handled) != 1 || handled[0].Recovered != "boom" || !handled[0].At.Equal(time.Unix(0, 2)) {
+		t.Errorf("Expected one handled panic, but got %v", handled)
+	}
+	if panics := tw.Panics(); len(panics) != 0 {
+		t.Errorf("Expected no collected panics, but got %v", panics)
+	}
+}
+
+func TestPanicCollect(t *testing.T) {
+	fired := []int64{}
+	tw := NewTimerWheel(time.Unix(0, 0), 1, WithPanicPolicy(PanicCollect, nil))
+	schedulePanicking(tw, &fired)
+	if count := tw.AdvanceTo(time.Unix(0, 10), 0); count != 3 {
+		t.Errorf("Expected 3 events invoked, but got %v", count)
+	}
+	panics := tw.Panics()
+	if len(panics) != 1 || panics[0].Recovered != "boom" {
+		t.Errorf("Expected one collected panic, but got %v", panics)
+	}
+	if panics = tw.Panics(); len(panics) != 0 {
+		t.Errorf("Expected collected panics to be cleared, but got %v", panics)
+	}
+}
@@ -0,0 +1,101 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"time"
+)
+
+// The error returned when an event is refused because it is scheduled
+// for a time in the past of the Timer Wheel's current time. It
+// matches ScheduledInPast with errors.Is.
+type ScheduledInPastError struct {
+	// The time the event was to be scheduled for.
+	At time.Time
+	// The Timer Wheel's current time.
+	Now time.Time
+}
+
+// Returns how far At is in the past of Now.
+func (pe *ScheduledInPastError) Delta() time.Duration {
+	return pe.Now.Sub(pe.At)
+}
+
+func (pe *ScheduledInPastError) Error() string {
+	return fmt.Sprintf("Requested event to be scheduled at %v, %v in the past of %v", pe.At, pe.Delta(), pe.Now)
+}
+
+func (pe *ScheduledInPastError) Unwrap() error {
+	return ScheduledInPast
+}
+
+// What to do with an event scheduled for a time in the past of the
+// Timer Wheel's current time (see WithPastPolicy).
+type PastPolicy int
+
+const (
+	// The event is refused with ScheduledInPast.
+	PastError PastPolicy = iota
+	// The event is scheduled for the Timer Wheel's current time
+	// instead, so it is invoked by the next advance.
+	PastClamp
+	// The event is invoked straight away, with the Timer Wheel's
+	// current time, from within the call scheduling it.
+	PastInvoke
+)
+
+// Sets what happens to events scheduled for a time in the past, for
+// example because of clock skew between the caller and whatever
+// drives the Timer Wheel. By default (PastError) they are refused
+// with ScheduledInPast. The policy applies to every way of scheduling
+// events, including ScheduleEvents and Merge, but not to helpers such
+// as DeadlineTree and StagedTimeout, whose events are always relative
+// to the current time.
+func WithPastPolicy(policy PastPolicy) Option {
+	return func(tw *TimerWheel) {
+		tw.pastPolicy = policy
+	}
+}
+
+// Returns a *ScheduledInPastError, having notified any Observer, if
+// an event for at should be refused.
+func (tw *TimerWheel) refusePast(at int64) error {
+	if at >= tw.now || tw.pastPolicy != PastError {
+		return nil
+	}
+	tw.observeDroppedPast(tw.toTime(at))
+	if logger := tw.logger; logger != nil {
+		logger.Printf("gotimerwheel: refused event scheduled at %v, in the past of %v", tw.toTime(at), tw.Now())
+	}
+	return tw.pastError(at)
+}
+
+func (tw *TimerWheel) pastError(at int64) error {
+	return &ScheduledInPastError{At: tw.toTime(at), Now: tw.Now()}
+}
+
+// Applies the past policy to sorted events, none of which may be
+// refused. Returns the events to be scheduled and those to be invoked
+// straight away with invokePast.
+func (tw *TimerWheel) splitPast(events []*eventNode) (schedule, invoke []*eventNode) {
+	past := 0
+	for past < len(events) && events[past].at < tw.now {
+		past++
+	}
+	if tw.pastPolicy == PastInvoke {
+		return events[past:], events[:past]
+	}
+	for _, event := range events[:past] {
+		event.at = tw.now
+	}
+	// Now all for the same time, they are ordered by priority alone.
+	sortEvents(events[:past], tw.tieBreak)
+	return events, nil
+}
+
+// Invokes an event, scheduled in the past, straight away.
+func (tw *TimerWheel) invokePast(event *eventNode) {
+	tw.stats.scheduled++
+	tw.observeScheduled(event.at)
+	now := tw.Now()
+	tw.fire(event, &now)
+}
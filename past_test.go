@@ -0,0 +1,88 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPastClamp(t *testing.T) {
+	start := time.Unix(0, 100)
+	tw := NewTimerWheel(start, 10, WithPastPolicy(PastClamp))
+	var invokedAt []time.Time
+	record := func(now *time.Time) { invokedAt = append(invokedAt, *now) }
+	if err := tw.ScheduleEventAt(time.Unix(0, 50), record); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.ScheduleEvents([]ScheduledEvent{{At: time.Unix(0, 20), Event: record}, {At: time.Unix(0, 150), Event: record}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.ScheduleKeyedEventAt("key", time.Unix(0, 0), record); err != nil {
+		t.Fatal(err)
+	}
+	if len(invokedAt) != 0 || tw.Length() != 4 {
+		t.Errorf("Expected nothing invoked and 4 events scheduled, but got %v and %v", len(invokedAt), tw.Length())
+	}
+	if at, _ := tw.NextEventAt(); !at.Equal(start) {
+		t.Errorf("Expected past events to be clamped to %v, but got %v", start, at)
+	}
+	if count := tw.AdvanceTo(start, 0); count != 3 {
+		t.Errorf("Expected the 3 clamped events to be invoked, but got %v", count)
+	}
+	if tw.CancelKey("key") {
+		t.Error("Expected the keyed event to have been invoked")
+	}
+}
+
+func TestPastInvoke(t *testing.T) {
+	start := time.Unix(0, 100)
+	tw := NewTimerWheel(start, 10, WithPastPolicy(PastInvoke))
+	var invokedAt []time.Time
+	record := func(now *time.Time) { invokedAt = append(invokedAt, *now) }
+	tw.ScheduleEventAt(time.Unix(0, 50), record)
+	tw.ScheduleEvents([]ScheduledEvent{{At: time.Unix(0, 20), Event: record}, {At: time.Unix(0, 150), Event: record}})
+	tw.ScheduleKeyedEventAt("key", time.Unix(0, 0), record)
+	if len(invokedAt) != 3 || tw.Length() != 1 {
+		t.Errorf("Expected 3 events invoked and 1 scheduled, but got %v and %v", len(invokedAt), tw.Length())
+	}
+	for _, at := range invokedAt {
+		if !at.Equal(start) {
+			t.Errorf("Expected events to be invoked with %v, but got %v", start, at)
+		}
+	}
+	if tw.CancelKey("key") {
+		t.Error("Expected the keyed event to have been invoked")
+	}
+	if stats := tw.Stats(); stats.Scheduled != 4 || stats.Fired != 3 {
+		t.Errorf("Expected 4 scheduled and 3 fired, but got %+v", stats)
+	}
+}
+
+func TestPastMerge(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 100), 10, WithPastPolicy(PastInvoke))
+	other := NewTimerWheel(time.Unix(0, 0), 10)
+	invoked := 0
+	other.ScheduleEventAt(time.Unix(0, 50), func(*time.Time) { invoked++ })
+	other.ScheduleEventAt(time.Unix(0, 500), func(*time.Time) { invoked++ })
+	if err := tw.Merge(other); err != nil {
+		t.Fatal(err)
+	}
+	if invoked != 1 || tw.Length() != 1 || !other.IsEmpty() {
+		t.Errorf("Expected the past event to be invoked and the other moved, but got %v invoked and %v scheduled", invoked, tw.Length())
+	}
+}
+
+func TestScheduledInPastError(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 100), 10)
+	err := tw.ScheduleEventAt(time.Unix(0, 40), func(*time.Time) {})
+	if !errors.Is(err, ScheduledInPast) {
+		t.Fatalf("Expected an error matching ScheduledInPast, but got %v", err)
+	}
+	var pe *ScheduledInPastError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected a *ScheduledInPastError, but got %T", err)
+	}
+	if !pe.At.Equal(time.Unix(0, 40)) || !pe.Now.Equal(time.Unix(0, 100)) || pe.Delta() != 60 {
+		t.Errorf("Unexpected error fields %v, %v, %v", pe.At, pe.Now, pe.Delta())
+	}
+}
@@ -0,0 +1,43 @@
+package gotimerwheel
+
+// Freezes the Timer Wheel's time as far as AdvanceToNow is concerned:
+// until Resume is called, AdvanceToNow invokes nothing. This suits
+// suspending timers while a debugger is attached, or in tests.
+// Explicit calls to AdvanceTo and the like still advance the Timer
+// Wheel as usual. Does nothing if the Timer Wheel is already paused.
+func (tw *TimerWheel) Pause() {
+	d := &tw.driving
+	if d.paused {
+		return
+	}
+	d.paused = true
+	d.pausedAt = tw.wallClock().Now()
+}
+
+// Unfreezes the Timer Wheel after Pause. The Timer Wheel is shifted
+// with Rebase by the Clock's time spent paused, so every scheduled
+// event keeps the delay it had left when paused, and the next
+// AdvanceToNow carries on as if no time had passed. Does nothing if
+// the Timer Wheel is not paused.
+func (tw *TimerWheel) Resume() {
+	d := &tw.driving
+	if !d.paused {
+		return
+	}
+	d.paused = false
+	reading := tw.wallClock().Now()
+	pausedFor := reading.Sub(d.pausedAt)
+	if pausedFor <= 0 {
+		return
+	}
+	tw.Rebase(pausedFor)
+	if d.started {
+		d.last = reading
+		d.target += int64(pausedFor)
+	}
+}
+
+// Returns true if the Timer Wheel is paused (see Pause).
+func (tw *TimerWheel) IsPaused() bool {
+	return tw.driving.paused
+}
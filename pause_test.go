@@ -0,0 +1,44 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPause(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, policy := range []ClockJumpPolicy{ClockJumpFollow, ClockJumpIgnore} {
+		clock := &manualClock{now: start}
+		tw := NewTimerWheel(start, time.Millisecond, WithClock(clock), WithClockJumpPolicy(policy))
+		invoked := 0
+		tw.ScheduleEventIn(10*time.Second, func(*time.Time) { invoked++ })
+		tw.AdvanceToNow(0)
+
+		clock.now = start.Add(4 * time.Second)
+		tw.AdvanceToNow(0)
+		tw.Pause()
+		tw.Pause()
+		if !tw.IsPaused() {
+			t.Fatal("Expected the Timer Wheel to be paused")
+		}
+		clock.now = start.Add(time.Minute)
+		if count := tw.AdvanceToNow(0); count != 0 || invoked != 0 {
+			t.Errorf("Expected nothing invoked while paused, but got %v", count)
+		}
+		tw.Resume()
+		if tw.IsPaused() {
+			t.Fatal("Expected the Timer Wheel to be resumed")
+		}
+		// 6s of the event's delay are left
+		clock.now = clock.now.Add(5 * time.Second)
+		tw.AdvanceToNow(0)
+		if invoked != 0 {
+			t.Errorf("Policy %v: expected the event not to be invoked yet", policy)
+		}
+		clock.now = clock.now.Add(time.Second)
+		tw.AdvanceToNow(0)
+		if invoked != 1 || !tw.Now().Equal(clock.now) {
+			t.Errorf("Policy %v: expected the event invoked at %v, but got %v at %v", policy, clock.now, invoked, tw.Now())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A pending event as reported by PeekNext.
+type PendingEvent struct {
+	// The time the event is scheduled for.
+	At time.Time
+	// Nil for events which return errors or are chained.
+	Event Event
+	// For events scheduled with ScheduleIDAt, the EventID. Otherwise
+	// 0.
+	ID EventID
+	// For keyed events (see ScheduleKeyedEventAt), the key. Otherwise
+	// empty.
+	Key string
+	// Optional. See ScheduleTaggedEventAt.
+	Tag string
+	// Optional. See SchedulePriorityEventAt.
+	Priority int
+	// For events scheduled by name (see ScheduleNamedEventAt), the
+	// name. Otherwise empty.
+	Name string
+	// Set for events removed by Extract, so that ScheduleExtracted can
+	// move the whole event.
+	event *eventNode
+}
+
+// Returns the earliest n pending events, in the order in which they
+// would be invoked, without advancing the Timer Wheel's current time.
+// Fewer are returned if fewer are scheduled. Only the buckets up to
+// the nth event are walked, so this is cheap enough to call often,
+// for example to show what is coming up next.
+func (tw *TimerWheel) PeekNext(n int) []PendingEvent {
+	if n <= 0 {
+		return nil
+	}
+	if length := tw.Length(); n > length {
+		n = length
+	}
+	events := make([]PendingEvent, 0, n)
+	tw.forEachEvent(func(event *eventNode) bool {
+		if len(events) == n {
+			return false
+		}
+		events = append(events, tw.pendingEvent(event))
+		return true
+	})
+	return events
+}
+
+func (tw *TimerWheel) pendingEvent(event *eventNode) PendingEvent {
+	pe := PendingEvent{At: tw.toTime(event.at), Event: event.fun, ID: event.id, Tag: event.tag, Priority: event.priority}
+	if event.keyed {
+		pe.Key = event.key
+	}
+	if event.named != nil {
+		pe.Name = event.named.name
+	}
+	return pe
+}
@@ -0,0 +1,52 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPeekNext(t *testing.T) {
+	start := time.Unix(0, 0)
+	rng := rand.New(rand.NewSource(1095))
+	tw := NewTimerWheel(start, time.Millisecond, WithHandlers(Handlers{"noop": func([]byte) Event {
+		return func(*time.Time) {}
+	}}))
+	if len(tw.PeekNext(5)) != 0 {
+		t.Fatal("Expected nothing to peek at in an empty Timer Wheel")
+	}
+	for idx := 0; idx < 2000; idx++ {
+		tw.ScheduleEventAt(start.Add(time.Duration(rng.Int63n(int64(time.Hour)))), func(*time.Time) {})
+	}
+	tw.ScheduleKeyedEventAt("key", start.Add(time.Nanosecond), func(*time.Time) {})
+	tw.ScheduleTaggedEventAt("tag", start.Add(2*time.Nanosecond), func(*time.Time) {})
+	tw.ScheduleNamedEventAt(start.Add(3*time.Nanosecond), "noop", nil)
+
+	events := tw.PeekNext(3)
+	if len(events) != 3 || events[0].Key != "key" || events[1].Tag != "tag" || events[2].Name != "noop" {
+		t.Fatalf("Unexpected events %v", events)
+	}
+	var all []time.Time
+	tw.ForEach(func(at time.Time, e Event) bool {
+		all = append(all, at)
+		return true
+	})
+	for _, n := range []int{0, 1, 100, len(all), len(all) + 10} {
+		events := tw.PeekNext(n)
+		expected := n
+		if expected > len(all) {
+			expected = len(all)
+		}
+		if len(events) != expected {
+			t.Fatalf("Expected %v events, but got %v", expected, len(events))
+		}
+		for idx, pe := range events {
+			if !pe.At.Equal(all[idx]) {
+				t.Fatalf("Expected event %v at %v, but got %v", idx, all[idx], pe.At)
+			}
+		}
+	}
+	if !tw.Now().Equal(start) || tw.Length() != len(all) {
+		t.Error("Expected peeking to leave the Timer Wheel unchanged")
+	}
+}
@@ -0,0 +1,130 @@
+package gotimerwheel
+
+import (
+	"sync"
+	"time"
+)
+
+type workerPool struct {
+	workers  int
+	tasks    chan poolTask
+	inflight sync.WaitGroup
+	mutex    sync.Mutex
+	panics   []*EventPanic
+}
+
+type poolTask struct {
+	e   Event
+	now time.Time
+	at  time.Time
+}
+
+// Hands invoked events to a pool of workers goroutines to run,
+// rather than running them within the call to AdvanceTo, so that
+// long-running events do not hold up later ones. If every worker is
+// busy, AdvanceTo waits for one to become free. Only plain events
+// (those scheduled with ScheduleEventAt and similar) are handed to
+// the pool: events which return errors, recurring and chained events
+// are still invoked by AdvanceTo itself, as they need the Timer
+// Wheel. As the Timer Wheel is not safe for concurrent use, events
+// run by the pool must not use it directly: an Intake (see NewIntake)
+// can be used to schedule further events. Under PanicSwallow the
+// panic handler is called from the worker goroutine; under
+// PanicCollect panics are collected as usual; under PanicPropagate a
+// panic crashes the program, as in any goroutine. Workers must be
+// greater than 0. The workers are started when first needed and
+// stopped by Close.
+func WithWorkerPool(workers int) Option {
+	if workers <= 0 {
+		panic("TimerWheel worker pool must have at least 1 worker")
+	}
+	return func(tw *TimerWheel) {
+		tw.pool = &workerPool{workers: workers}
+	}
+}
+
+// Returns true if the event was handed to the worker pool.
+func (tw *TimerWheel) dispatch(event *eventNode, now *time.Time) bool {
+	p := tw.pool
+	if p == nil || event.fun == nil || event.funE != nil || event.recurring != nil || event.chained != nil {
+		return false
+	}
+	if p.tasks == nil {
+		p.start(&tw.panics, tw.logger)
+	}
+	p.inflight.Add(1)
+	p.tasks <- poolTask{e: tw.intercept(event.fun), now: *now, at: tw.toTime(event.at)}
+	return true
+}
+
+func (p *workerPool) start(recovery *panicRecovery, logger Logger) {
+	tasks := make(chan poolTask, p.workers)
+	p.tasks = tasks
+	policy, handler := recovery.policy, recovery.handler
+	for idx := 0; idx < p.workers; idx++ {
+		go func() {
+			for task := range tasks {
+				p.run(task, policy, handler, logger)
+			}
+		}()
+	}
+}
+
+func (p *workerPool) run(task poolTask, policy PanicPolicy, handler func(*EventPanic), logger Logger) {
+	defer p.inflight.Done()
+	if policy != PanicPropagate {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			ep := &EventPanic{At: task.at, Recovered: recovered}
+			if logger != nil {
+				logger.Printf("gotimerwheel: recovered panic: %v", ep)
+			}
+			switch {
+			case policy == PanicCollect:
+				p.mutex.Lock()
+				p.panics = append(p.panics, ep)
+				p.mutex.Unlock()
+			case handler != nil:
+				handler(ep)
+			}
+		}()
+	}
+	task.e(&task.now)
+}
+
+// Waits until every event handed to the worker pool (see
+// WithWorkerPool) has finished running. Returns immediately if there
+// is no worker pool.
+func (tw *TimerWheel) WaitForInflight() {
+	if tw.pool == nil {
+		return
+	}
+	tw.pool.inflight.Wait()
+	tw.collectPoolPanics()
+}
+
+// Moves panics collected by the worker pool into the Timer Wheel's
+// own collection.
+func (tw *TimerWheel) collectPoolPanics() {
+	p := tw.pool
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	tw.panics.collected = append(tw.panics.collected, p.panics...)
+	p.panics = nil
+	p.mutex.Unlock()
+}
+
+// Waits for the workers to finish, then stops them.
+func (p *workerPool) stop() {
+	if p == nil || p.tasks == nil {
+		return
+	}
+	p.inflight.Wait()
+	close(p.tasks)
+	p.tasks = nil
+}
@@ -0,0 +1,53 @@
+package gotimerwheel
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithWorkerPool(4), WithPanicPolicy(PanicCollect, nil))
+	release := make(chan struct{})
+	var finished int64
+	for idx := 0; idx < 4; idx++ {
+		tw.ScheduleEventAt(start, func(*time.Time) {
+			<-release
+			atomic.AddInt64(&finished, 1)
+		})
+	}
+	tw.ScheduleEventAt(start.Add(time.Millisecond), func(*time.Time) { panic("boom") })
+	// the first events block their workers, but do not hold up the
+	// advance
+	if count := tw.AdvanceTo(start.Add(time.Millisecond), 0); count != 5 {
+		t.Errorf("Expected 5 events invoked, but got %v", count)
+	}
+	if atomic.LoadInt64(&finished) != 0 {
+		t.Error("Expected the events to still be running")
+	}
+	close(release)
+	tw.WaitForInflight()
+	if finished != 4 {
+		t.Errorf("Expected 4 events finished, but got %v", finished)
+	}
+	if panics := tw.Panics(); len(panics) != 1 || panics[0].Recovered != "boom" {
+		t.Errorf("Expected the panic to be collected, but got %v", panics)
+	}
+	// errors and recurring events still run within AdvanceTo
+	var recurred int64
+	tw.ScheduleRecurringEventIn(time.Millisecond, func(now time.Time) (time.Time, bool) {
+		recurred++
+		return now.Add(time.Millisecond), recurred < 3
+	})
+	for idx := 0; idx < 3; idx++ {
+		tw.AdvanceToNextEvent()
+	}
+	if recurred != 3 || !tw.IsEmpty() {
+		t.Errorf("Expected the recurring event to be invoked 3 times, but got %v", recurred)
+	}
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { atomic.AddInt64(&finished, 1) })
+	if count := tw.Close(CloseDrain); count != 1 || atomic.LoadInt64(&finished) != 5 {
+		t.Errorf("Expected Close to wait for the drained event, but got %v", finished)
+	}
+}
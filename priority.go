@@ -0,0 +1,83 @@
+package gotimerwheel
+
+import (
+	"sort"
+	"time"
+)
+
+// Schedules an event with a priority to be invoked at the indicated
+// time. Among events scheduled for the same time, those with a higher
+// priority are invoked first, and those with the same priority in the
+// order in which they were scheduled. Events scheduled without a
+// priority have priority 0, so a negative priority puts an event
+// after them. Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) SchedulePriorityEventAt(priority int, at time.Time, e Event) error {
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: at.UnixNano(), fun: e, priority: priority}))
+}
+
+// Schedules an event with a priority to be invoked at the current
+// Timer Wheel's time plus the supplied duration. See
+// SchedulePriorityEventAt.
+func (tw *TimerWheel) SchedulePriorityEventIn(priority int, in time.Duration, e Event) error {
+	return tw.SchedulePriorityEventAt(priority, tw.after(in), e)
+}
+
+// The properties of an event passed to a TieBreak.
+type EventMeta struct {
+	// See ScheduleTaggedEventAt.
+	Tag string
+	// See ScheduleKeyedEventAt. Empty for events without a key.
+	Key string
+	// See SchedulePriorityEventAt.
+	Priority int
+}
+
+// Returns true if an event with properties a is to be invoked before
+// one with properties b for the same time. See WithTieBreak.
+type TieBreak func(a, b EventMeta) bool
+
+// Orders events scheduled for the same time with less, so that
+// deterministic simulations can invoke them in an order which depends
+// on their properties rather than on the order in which they happened
+// to be scheduled. Events which less does not order either way are
+// ordered by priority and then in the order in which they were
+// scheduled. Less must be a strict weak ordering, and must not
+// schedule or cancel events.
+func WithTieBreak(less TieBreak) Option {
+	return func(tw *TimerWheel) {
+		tw.tieBreak = less
+		for idx := range tw.ring {
+			tw.ring[idx].tieBreak = less
+		}
+	}
+}
+
+func (event *eventNode) meta() EventMeta {
+	return EventMeta{Tag: event.tag, Key: event.key, Priority: event.priority}
+}
+
+// Returns true if a is to be invoked before b whatever the order in
+// which they were scheduled: it is for an earlier time, or for the
+// same time and tieBreak (if non-nil) or else a higher priority puts
+// it first.
+func before(a, b *eventNode, tieBreak TieBreak) bool {
+	if a.at != b.at {
+		return a.at < b.at
+	}
+	if tieBreak != nil {
+		ma, mb := a.meta(), b.meta()
+		if tieBreak(ma, mb) {
+			return true
+		}
+		if tieBreak(mb, ma) {
+			return false
+		}
+	}
+	return a.priority > b.priority
+}
+
+// Sorts events into the order in which they are to be invoked. Events
+// which are not before one another keep their order.
+func sortEvents(events []*eventNode, tieBreak TieBreak) {
+	sort.SliceStable(events, func(a, b int) bool { return before(events[a], events[b], tieBreak) })
+}
@@ -0,0 +1,114 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriority(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}, {WithOverflowHeap(1)}} {
+		// near enough for the root wheel, and far enough to be
+		// cascaded or held in the overflow heap
+		for _, offset := range []time.Duration{3 * time.Millisecond, time.Hour} {
+			tw := NewTimerWheel(start, time.Millisecond, options...)
+			at := start.Add(offset)
+			var invoked []string
+			record := func(name string) Event { return func(*time.Time) { invoked = append(invoked, name) } }
+			tw.ScheduleEventAt(at, record("keepalive"))
+			tw.SchedulePriorityEventAt(10, at, record("cancel 1"))
+			tw.SchedulePriorityEventAt(-1, at, record("log"))
+			tw.SchedulePriorityEventAt(10, at, record("cancel 2"))
+			tw.ScheduleEvents([]ScheduledEvent{
+				{At: at, Event: record("batch 5"), Priority: 5},
+				{At: at, Event: record("batch 0")},
+			})
+			tw.SchedulePriorityEventAt(-5, at.Add(-time.Nanosecond), record("earlier"))
+			expected := []string{"earlier", "cancel 1", "cancel 2", "batch 5", "keepalive", "batch 0", "log"}
+
+			var forEach []Event
+			tw.ForEach(func(at time.Time, e Event) bool {
+				forEach = append(forEach, e)
+				return true
+			})
+			for _, e := range forEach {
+				e(nil)
+			}
+			tw.AdvanceTo(at, 0)
+			if len(invoked) != 2*len(expected) {
+				t.Fatalf("Expected %v, twice, but got %v", expected, invoked)
+			}
+			for idx, name := range append(expected, expected...) {
+				if invoked[idx] != name {
+					t.Fatalf("%v: expected %v, twice, but got %v", offset, expected, invoked)
+				}
+			}
+		}
+	}
+}
+
+func TestTieBreak(t *testing.T) {
+	start := time.Unix(0, 0)
+	byTag := func(a, b EventMeta) bool { return a.Tag < b.Tag }
+	for _, options := range [][]Option{{WithTieBreak(byTag)}, {WithTieBreak(byTag), WithSliceBuckets()}, {WithOverflowHeap(1), WithTieBreak(byTag)}} {
+		for _, offset := range []time.Duration{3 * time.Millisecond, time.Hour} {
+			tw := NewTimerWheel(start, time.Millisecond, options...)
+			at := start.Add(offset)
+			var invoked []string
+			record := func(name string) Event { return func(*time.Time) { invoked = append(invoked, name) } }
+			tw.ScheduleTaggedEventAt("c", at, record("c"))
+			tw.ScheduleTaggedEventAt("a", at, record("a"))
+			tw.SchedulePriorityEventAt(1, at, record("untagged 1"))
+			tw.ScheduleEvents([]ScheduledEvent{{At: at, Tag: "b", Event: record("b 1")}, {At: at, Event: record("untagged 0")}})
+			tw.SchedulePriorityEventAt(2, at, record("untagged 2"))
+			tw.ScheduleTaggedEventAt("b", at, record("b 2"))
+			tw.ScheduleTaggedEventAt("z", at.Add(-time.Nanosecond), record("earlier"))
+			expected := []string{"earlier", "untagged 2", "untagged 1", "untagged 0", "a", "b 1", "b 2", "c"}
+
+			var forEach []Event
+			tw.ForEach(func(at time.Time, e Event) bool {
+				forEach = append(forEach, e)
+				return true
+			})
+			for _, e := range forEach {
+				e(nil)
+			}
+			tw.AdvanceTo(at, 0)
+			if len(invoked) != 2*len(expected) {
+				t.Fatalf("Expected %v, twice, but got %v", expected, invoked)
+			}
+			for idx, name := range append(expected, expected...) {
+				if invoked[idx] != name {
+					t.Fatalf("%v: expected %v, twice, but got %v", offset, expected, invoked)
+				}
+			}
+		}
+	}
+}
+
+func TestTieBreakClone(t *testing.T) {
+	start := time.Unix(0, 0)
+	byTagDescending := func(a, b EventMeta) bool { return a.Tag > b.Tag }
+	tw := NewTimerWheel(start, time.Millisecond, WithTieBreak(byTagDescending))
+	var invoked []string
+	at := start.Add(3 * time.Millisecond)
+	for _, tag := range []string{"a", "c", "b"} {
+		tag := tag
+		tw.ScheduleTaggedEventAt(tag, at, func(*time.Time) { invoked = append(invoked, tag) })
+	}
+	clone := tw.Clone()
+	// Same-time events scheduled into the clone are ordered by the
+	// same tie-break.
+	clone.ScheduleTaggedEventAt("d", at, func(*time.Time) { invoked = append(invoked, "d") })
+	tw.AdvanceTo(at, 0)
+	clone.AdvanceTo(at, 0)
+	expected := []string{"c", "b", "a", "d", "c", "b", "a"}
+	if len(invoked) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, invoked)
+	}
+	for idx, tag := range expected {
+		if invoked[idx] != tag {
+			t.Fatalf("Expected %v, but got %v", expected, invoked)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// An event which has become due, handed to its consumer to invoke
+// rather than being invoked by the Timer Wheel. See WithFiredEvents
+// and AdvanceToCollect.
+type FiredEvent struct {
+	// The time the event was scheduled for.
+	At time.Time
+	// The time passed to AdvanceTo (or AdvanceBy and so on).
+	Now time.Time
+	// The event itself, wrapped by any middleware (see WithMiddleware).
+	Event Event
+	// For events scheduled by name (see ScheduleNamedEventAt), the
+	// name and payload. Otherwise empty.
+	Name    string
+	Payload []byte
+}
+
+// Invokes the event, passing it Now, as the Timer Wheel would have.
+func (fe *FiredEvent) Invoke() {
+	fe.Event(&fe.Now)
+}
+
+type pullSink struct {
+	fired      chan<- FiredEvent
+	collecting bool
+	collected  []FiredEvent
+}
+
+// Sends events on fired as they become due, rather than invoking
+// them within the call to AdvanceTo, so that the consumer decides
+// where and when to run them, for example on a goroutine of its own
+// with its own prioritisation. If fired is full, AdvanceTo waits for
+// the consumer. Events are counted as invoked (by Stats and the value
+// returned by AdvanceTo) once they are sent. As with WithWorkerPool,
+// only plain events are sent: events which return errors, recurring
+// and chained events are still invoked by AdvanceTo itself. Events
+// which are sent are not handed to the worker pool, and any panic
+// they raise is the consumer's to recover. The Timer Wheel never
+// closes fired. Fired must not be nil.
+func WithFiredEvents(fired chan<- FiredEvent) Option {
+	if fired == nil {
+		panic("TimerWheel fired events channel must not be nil")
+	}
+	return func(tw *TimerWheel) {
+		tw.pull.fired = fired
+	}
+}
+
+// Advances the Timer Wheel's current time just as AdvanceTo does, but
+// appends the plain events which become due to fired rather than
+// invoking them, returning the extended slice along with the number
+// of events invoked or collected. Passing in the slice returned by
+// the previous call, truncated to length 0, means collecting allocates
+// nothing once the slice is large enough. Other events are invoked as
+// usual; see WithFiredEvents, which AdvanceToCollect overrides.
+func (tw *TimerWheel) AdvanceToCollect(now time.Time, limit int, fired []FiredEvent) ([]FiredEvent, int) {
+	tw.pull.collecting = true
+	tw.pull.collected = fired
+	defer func() {
+		tw.pull.collecting = false
+		tw.pull.collected = nil
+	}()
+	count, _ := tw.advanceTo(now, limitTo(limit))
+	return tw.pull.collected, count
+}
+
+// Returns true if the event was sent or collected rather than
+// needing to be invoked.
+func (tw *TimerWheel) deliver(event *eventNode, now *time.Time) bool {
+	p := &tw.pull
+	if (p.fired == nil && !p.collecting) ||
+		event.fun == nil || event.funE != nil || event.recurring != nil || event.chained != nil {
+		return false
+	}
+	fe := FiredEvent{At: tw.toTime(event.at), Now: *now, Event: tw.intercept(event.fun)}
+	if event.named != nil {
+		fe.Name, fe.Payload = event.named.name, event.named.payload
+	}
+	if p.collecting {
+		p.collected = append(p.collected, fe)
+	} else {
+		p.fired <- fe
+	}
+	return true
+}
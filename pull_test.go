@@ -0,0 +1,80 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiredEvents(t *testing.T) {
+	start := time.Unix(0, 0)
+	fired := make(chan FiredEvent, 10)
+	handlers := Handlers{
+		"send": func(payload []byte) Event { return func(*time.Time) {} },
+	}
+	tw := NewTimerWheel(start, time.Millisecond, WithFiredEvents(fired), WithHandlers(handlers))
+	invoked := 0
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { invoked++ })
+	tw.ScheduleNamedEventIn(2*time.Millisecond, "send", []byte("payload"))
+	// still invoked by the Timer Wheel
+	tw.ScheduleEventInE(time.Millisecond, func(*time.Time) error { invoked += 10; return nil })
+
+	if count := tw.AdvanceBy(2*time.Millisecond, 0); count != 3 || tw.Stats().Fired != 3 {
+		t.Errorf("Expected 3 events, but got %v", count)
+	}
+	if invoked != 10 || len(fired) != 2 {
+		t.Fatalf("Expected only the EventE invoked, but got %v and %v sent", invoked, len(fired))
+	}
+	fe := <-fired
+	if !fe.At.Equal(start.Add(time.Millisecond)) || !fe.Now.Equal(start.Add(2*time.Millisecond)) || fe.Name != "" {
+		t.Errorf("Unexpected fired event %+v", fe)
+	}
+	fe.Invoke()
+	if invoked != 11 {
+		t.Errorf("Expected the consumer to invoke the event, but got %v", invoked)
+	}
+	if fe = <-fired; fe.Name != "send" || string(fe.Payload) != "payload" {
+		t.Errorf("Expected the named event's name and payload, but got %+v", fe)
+	}
+}
+
+func TestAdvanceToCollect(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithWorkerPool(1))
+	invoked := 0
+	for idx := 1; idx <= 3; idx++ {
+		tw.ScheduleEventAt(start.Add(time.Duration(idx)*time.Millisecond), func(*time.Time) { invoked++ })
+	}
+	fired, count := tw.AdvanceToCollect(start.Add(2*time.Millisecond), 0, nil)
+	if count != 2 || len(fired) != 2 || invoked != 0 {
+		t.Fatalf("Expected 2 events collected and none invoked, but got %v, %v, %v", count, len(fired), invoked)
+	}
+	for idx := range fired {
+		fired[idx].Invoke()
+	}
+	fired, count = tw.AdvanceToCollect(start.Add(10*time.Millisecond), 0, fired[:0])
+	if count != 1 || len(fired) != 1 || !fired[0].At.Equal(start.Add(3*time.Millisecond)) {
+		t.Errorf("Expected the last event collected, but got %v", fired)
+	}
+	// only while collecting
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) { invoked++ })
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.WaitForInflight()
+	if invoked != 3 {
+		t.Errorf("Expected the pool to invoke the event, but got %v", invoked)
+	}
+}
+
+func TestFiredEventsAllocs(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	fired := make([]FiredEvent, 0, 4)
+	allocs := testing.AllocsPerRun(100, func() {
+		tw.ScheduleEventIn(time.Millisecond, nop)
+		fired, _ = tw.AdvanceToCollect(tw.Now().Add(time.Millisecond), 0, fired[:0])
+	})
+	// the event's node, and the copy of now passed to events
+	if allocs > 2 {
+		t.Errorf("Expected collecting to allocate nothing, but got %v allocations", allocs)
+	}
+}
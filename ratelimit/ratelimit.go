@@ -0,0 +1,101 @@
+// Package ratelimit provides token bucket rate limiters whose refills
+// are events on a TimerWheel, so that simulations get rate limiting
+// in virtual time, and servers can run thousands of limiters from one
+// wheel rather than a time.Ticker each. A limiter with a full bucket
+// has no events scheduled, so idle limiters cost nothing. Like the
+// TimerWheel they are built on, limiters are not safe for concurrent
+// use.
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// A token bucket: it holds up to capacity tokens, and gains one token
+// every interval until it is full. Each operation allowed takes one
+// token. See New.
+type Limiter struct {
+	tw       *gotimerwheel.TimerWheel
+	capacity int
+	interval time.Duration
+	tokens   int
+	// The time of the scheduled refill, if refilling.
+	refillAt  time.Time
+	refilling bool
+}
+
+// Creates a Limiter, with a full bucket of capacity tokens, which
+// gains a token every interval of tw's time. Capacity must be at
+// least 1, and interval greater than 0.
+func New(tw *gotimerwheel.TimerWheel, capacity int, interval time.Duration) *Limiter {
+	if capacity < 1 {
+		panic("Limiter capacity must be at least 1")
+	}
+	if interval <= 0 {
+		panic("Limiter interval must be greater than 0")
+	}
+	return &Limiter{tw: tw, capacity: capacity, interval: interval, tokens: capacity}
+}
+
+// Takes a token if there is one. Returns true if the operation is
+// allowed.
+func (l *Limiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// Takes n tokens if there are at least n. Returns true if the
+// operations are allowed; otherwise no tokens are taken.
+func (l *Limiter) AllowN(n int) bool {
+	if n > l.tokens {
+		l.refill()
+		return false
+	}
+	l.tokens -= n
+	l.refill()
+	return true
+}
+
+// Returns the number of tokens in the bucket.
+func (l *Limiter) Tokens() int {
+	return l.tokens
+}
+
+// Returns the time at which the bucket next gains a token. If the
+// bucket is full then false is returned.
+func (l *Limiter) NextRefill() (time.Time, bool) {
+	if !l.refilling {
+		return time.Time{}, false
+	}
+	return l.refillAt, true
+}
+
+// Schedules the next refill, unless the bucket is full or a refill is
+// already scheduled. If the refill cannot be scheduled (for example
+// because the Timer Wheel is closed), the next call tries again.
+func (l *Limiter) refill() {
+	if l.refilling || l.tokens >= l.capacity {
+		return
+	}
+	l.schedule(l.tw.Now().Add(l.interval))
+}
+
+func (l *Limiter) schedule(at time.Time) {
+	l.refilling = l.tw.ScheduleEventAt(at, l.refilled) == nil
+	l.refillAt = at
+}
+
+// Events are invoked with the time being advanced to, which may be
+// several intervals after the refill was due, so every token due by
+// then is added at once.
+func (l *Limiter) refilled(now *time.Time) {
+	l.refilling = false
+	due := 1 + int(now.Sub(l.refillAt)/l.interval)
+	l.tokens += due
+	if l.tokens >= l.capacity {
+		l.tokens = l.capacity
+		return
+	}
+	l.schedule(l.refillAt.Add(time.Duration(due) * l.interval))
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+func TestLimiter(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := gotimerwheel.NewTimerWheel(start, time.Millisecond)
+	l := New(tw, 3, 10*time.Millisecond)
+	if _, refilling := l.NextRefill(); refilling || !tw.IsEmpty() {
+		t.Error("Expected a full bucket to schedule nothing")
+	}
+	for idx := 0; idx < 3; idx++ {
+		if !l.Allow() {
+			t.Errorf("Expected token %v to be allowed", idx)
+		}
+	}
+	if l.Allow() || l.Tokens() != 0 {
+		t.Error("Expected an empty bucket")
+	}
+	if at, refilling := l.NextRefill(); !refilling || !at.Equal(start.Add(10*time.Millisecond)) || tw.Length() != 1 {
+		t.Errorf("Expected a single refill at 10ms, but got %v", at)
+	}
+
+	tw.AdvanceTo(start.Add(15*time.Millisecond), 0)
+	if l.Tokens() != 1 {
+		t.Errorf("Expected 1 token, but got %v", l.Tokens())
+	}
+	if l.AllowN(2) || l.Tokens() != 1 {
+		t.Error("Expected AllowN to take nothing when there are too few tokens")
+	}
+	// refills keep to their schedule, however coarse the advances
+	if at, _ := l.NextRefill(); !at.Equal(start.Add(20 * time.Millisecond)) {
+		t.Errorf("Expected the next refill at 20ms, but got %v", at)
+	}
+	tw.AdvanceTo(start.Add(35*time.Millisecond), 0)
+	if _, refilling := l.NextRefill(); l.Tokens() != 3 || refilling || !tw.IsEmpty() {
+		t.Errorf("Expected a full bucket with no refill, but got %v tokens", l.Tokens())
+	}
+	l.AllowN(2)
+	tw.AdvanceTo(start.Add(50*time.Millisecond), 0)
+	if at, _ := l.NextRefill(); l.Tokens() != 2 || !at.Equal(start.Add(55*time.Millisecond)) {
+		t.Errorf("Expected 2 tokens and a refill at 55ms, but got %v at %v", l.Tokens(), at)
+	}
+}
+
+// Many limiters share one wheel, and only those refilling have an
+// event scheduled.
+func TestManyLimiters(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := gotimerwheel.NewTimerWheel(start, time.Millisecond)
+	limiters := make([]*Limiter, 10000)
+	for idx := range limiters {
+		limiters[idx] = New(tw, 1, time.Second)
+	}
+	for idx := 0; idx < len(limiters); idx += 2 {
+		limiters[idx].Allow()
+	}
+	if tw.Length() != len(limiters)/2 {
+		t.Errorf("Expected %v refills, but got %v", len(limiters)/2, tw.Length())
+	}
+	tw.AdvanceBy(time.Second, 0)
+	for idx, l := range limiters {
+		if l.Tokens() != 1 {
+			t.Fatalf("Expected limiter %v to be full, but got %v", idx, l.Tokens())
+		}
+	}
+	if !tw.IsEmpty() {
+		t.Errorf("Expected no refills once full, but got %v", tw.Length())
+	}
+}
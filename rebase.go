@@ -0,0 +1,48 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Shifts the Timer Wheel's current time, and the time of every
+// scheduled event, by delta, which may be negative. Events keep their
+// order, and their distance from the current time, so nothing is
+// invoked and an event due in a minute before Rebase is still due in
+// a minute after it. This suits a simulation whose epoch changes, or
+// reconciling with a corrected reference clock. The shift is cheap,
+// as each event stays in the same bucket. Helpers such as
+// Coalescer and Throttler which keep times of their own are not
+// shifted, and a start time set by WithAlignment is only still
+// aligned if delta is a multiple of the alignment.
+func (tw *TimerWheel) Rebase(delta time.Duration) {
+	d := int64(delta)
+	tw.completeCascades()
+	for level := tw; level != nil; level = level.next {
+		level.now += d
+		level.start += d
+		for idx := range level.ring {
+			b := &level.ring[idx]
+			if b.sliced {
+				entries := b.live()
+				for idx := range entries {
+					entries[idx].at += d
+				}
+			}
+			b.each(func(event *eventNode) bool {
+				event.at += d
+				return true
+			})
+		}
+	}
+	for _, entry := range tw.overflow.events {
+		entry.event.at += d
+	}
+	if tw.duplicates.enabled {
+		events := make(map[duplicateKey]*eventNode, len(tw.duplicates.events))
+		for _, event := range tw.duplicates.events {
+			events[keyForDuplicates(event)] = event
+		}
+		tw.duplicates.events = events
+	}
+	tw.waiters.reach(tw.now)
+}
@@ -0,0 +1,41 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRebase(t *testing.T) {
+	for _, options := range [][]Option{nil, {WithSliceBuckets()}, {WithOverflowHeap(1)}, {WithDuplicateDetection(DuplicateReject)}} {
+		for _, delta := range []time.Duration{time.Hour, -37 * time.Millisecond} {
+			start := time.Unix(1000, 0)
+			tw := NewTimerWheel(start, time.Millisecond, options...)
+			offsets := []time.Duration{0, 3 * time.Millisecond, 3 * time.Millisecond, time.Second, 24 * time.Hour}
+			var invokedAt []time.Time
+			for idx, offset := range offsets {
+				tw.ScheduleTaggedEventAt(fmt.Sprint(idx), start.Add(offset), func(*time.Time) { invokedAt = append(invokedAt, tw.Now()) })
+			}
+			tw.Rebase(delta)
+			if !tw.Now().Equal(start.Add(delta)) || tw.Length() != len(offsets) {
+				t.Fatalf("Expected to be at %v with %v events, but got %v with %v", start.Add(delta), len(offsets), tw.Now(), tw.Length())
+			}
+			if tw.duplicates.enabled {
+				if err := tw.ScheduleTaggedEventAt("3", start.Add(delta+time.Second), func(*time.Time) {}); err != DuplicateEvent {
+					t.Errorf("Expected duplicates to be detected at the rebased times, but got %v", err)
+				}
+			}
+			for !tw.IsEmpty() {
+				tw.AdvanceToNextEvent()
+			}
+			if len(invokedAt) != len(offsets) {
+				t.Fatalf("Expected %v events to be invoked, but got %v", len(offsets), len(invokedAt))
+			}
+			for idx, offset := range offsets {
+				if expected := start.Add(delta + offset); !invokedAt[idx].Equal(expected) {
+					t.Errorf("Expected event %v at %v, but got %v", idx, expected, invokedAt[idx])
+				}
+			}
+		}
+	}
+}
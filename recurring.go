@@ -0,0 +1,48 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Events that may reschedule themselves. When invoked, a
+// RecurringEvent is given the same time as an Event is. If it returns
+// again as true, it is scheduled once more, to be invoked at next.
+type RecurringEvent func(now time.Time) (next time.Time, again bool)
+
+// Schedules a RecurringEvent to be invoked at the indicated time. Each
+// time it is invoked it may ask to be invoked again. This makes
+// periodic and backoff timers possible without the event needing a
+// reference to the Timer Wheel. If the next time it asks for is not
+// strictly after the time it was invoked with then it is not
+// rescheduled: otherwise it would be invoked again, without end,
+// by the same call to AdvanceTo. Returns ScheduledInPast if at is in
+// the past.
+func (tw *TimerWheel) ScheduleRecurringEventAt(at time.Time, e RecurringEvent) error {
+	return tw.scheduleEvent(tw.newRecurringEvent(at, e))
+}
+
+// Schedules a RecurringEvent to be invoked at the current Timer
+// Wheel's time plus the supplied duration. See
+// ScheduleRecurringEventAt.
+func (tw *TimerWheel) ScheduleRecurringEventIn(in time.Duration, e RecurringEvent) error {
+	return tw.ScheduleRecurringEventAt(tw.after(in), e)
+}
+
+func (tw *TimerWheel) newRecurringEvent(at time.Time, e RecurringEvent) *eventNode {
+	event := tw.newEvent(eventNode{at: at.UnixNano(), recurring: e})
+	if e != nil {
+		// fun is only used to present the event through ForEach.
+		event.fun = func(now *time.Time) { e(*now) }
+	}
+	return event
+}
+
+// Invokes a recurring event, rescheduling it into this Timer Wheel.
+// Rescheduling through whichever Timer Wheel invokes the event,
+// rather than the one it was first scheduled in, keeps clones and
+// merged wheels independent.
+func (tw *TimerWheel) recur(event *eventNode, now *time.Time) {
+	if next, again := event.recurring(*now); again && next.After(*now) {
+		tw.scheduleEvent(tw.newRecurringEvent(next, event.recurring))
+	}
+}
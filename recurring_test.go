@@ -0,0 +1,51 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurringEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	invocations := []time.Time{}
+	// exponential backoff: 1, 2, 4, 8, ... stopping after 6 goes
+	delay := time.Duration(1)
+	tw.ScheduleRecurringEventIn(delay, func(now time.Time) (time.Time, bool) {
+		invocations = append(invocations, now)
+		delay *= 2
+		return now.Add(delay), len(invocations) < 6
+	})
+	for !tw.IsEmpty() {
+		tw.AdvanceToNextEvent()
+	}
+	expected := []int64{1, 3, 7, 15, 31, 63}
+	if len(invocations) != len(expected) {
+		t.Fatalf("Expected %v invocations, but got %v", expected, invocations)
+	}
+	for idx, ns := range expected {
+		if !invocations[idx].Equal(time.Unix(0, ns)) {
+			t.Errorf("Expected invocations at %v, but got %v", expected, invocations)
+			break
+		}
+	}
+
+	// asking for a time in the past stops the recurrence
+	tw.ScheduleRecurringEventIn(5, func(now time.Time) (time.Time, bool) {
+		return now.Add(-1), true
+	})
+	tw.AdvanceBy(100, 0)
+	assertNowLength(t, tw, time.Unix(0, 163), 0)
+
+	// as does asking for the same time again
+	invoked := 0
+	tw.ScheduleRecurringEventIn(5, func(now time.Time) (time.Time, bool) {
+		invoked++
+		return now, invoked < 1000
+	})
+	tw.AdvanceBy(100, 0)
+	assertNowLength(t, tw, time.Unix(0, 263), 0)
+	if invoked != 1 {
+		t.Errorf("Expected 1 invocation, but got %v", invoked)
+	}
+}
@@ -0,0 +1,149 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	NameRegistered  = errors.New("A Timer Wheel is already registered with that name")
+	WheelRegistered = errors.New("The Timer Wheel is already registered under another name")
+)
+
+var registry = struct {
+	sync.Mutex
+	wheels map[string]*registration
+}{wheels: make(map[string]*registration)}
+
+// A registered Timer Wheel, and the most recently published snapshot
+// of it. The snapshot is written by the goroutine driving the Timer
+// Wheel and may be read by any goroutine.
+type registration struct {
+	name string
+	tw   *TimerWheel
+	sync.Mutex
+	now    time.Time
+	length int
+	stats  Stats
+}
+
+// Registers tw under name in a process-wide registry, so that all the
+// Timer Wheels of a program can be observed from one place. A Timer
+// Wheel is not safe for concurrent use, so the registry does not
+// inspect registered wheels directly. Instead, each registered Timer
+// Wheel publishes a snapshot of its Stats when it is registered, at
+// the end of every AdvanceTo and Drain, and whenever PublishStats is
+// called. RegisteredStats and DumpRegistered report those snapshots,
+// and may be called from any goroutine, for example an admin
+// endpoint. Register itself must be called from the goroutine which
+// drives tw. Returns NameRegistered if another Timer Wheel is
+// registered with the same name, and WheelRegistered if tw is
+// registered under a different name.
+func Register(name string, tw *TimerWheel) error {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, found := registry.wheels[name]; found {
+		return NameRegistered
+	}
+	if reg := tw.registration; reg != nil && registry.wheels[reg.name] == reg {
+		return WheelRegistered
+	}
+	reg := &registration{name: name, tw: tw}
+	tw.registration = reg
+	tw.PublishStats()
+	registry.wheels[name] = reg
+	return nil
+}
+
+// Removes the Timer Wheel registered under name. Returns true iff
+// there was one. This may be called from any goroutine.
+func Unregister(name string) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	_, found := registry.wheels[name]
+	delete(registry.wheels, name)
+	return found
+}
+
+// Returns the Timer Wheel registered under name, if there is one.
+func Lookup(name string) (*TimerWheel, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	if reg, found := registry.wheels[name]; found {
+		return reg.tw, true
+	}
+	return nil, false
+}
+
+// Returns the names of all registered Timer Wheels, sorted.
+func RegisteredNames() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	return registeredNames()
+}
+
+func registeredNames() []string {
+	names := make([]string, 0, len(registry.wheels))
+	for name := range registry.wheels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Publishes a snapshot of the Timer Wheel's Stats to the registry, if
+// the Timer Wheel is registered. This happens automatically at the
+// end of every AdvanceTo and Drain, so is only needed to make other
+// changes, such as scheduling lots of events, visible sooner.
+func (tw *TimerWheel) PublishStats() {
+	reg := tw.registration
+	if reg == nil {
+		return
+	}
+	stats := tw.Stats()
+	length := stats.Overflow
+	for _, count := range stats.Levels {
+		length += count
+	}
+	reg.Lock()
+	reg.now, reg.length, reg.stats = tw.Now(), length, stats
+	reg.Unlock()
+}
+
+// Returns the most recently published Stats of every registered Timer
+// Wheel, by name. See Register.
+func RegisteredStats() map[string]Stats {
+	registry.Lock()
+	defer registry.Unlock()
+	stats := make(map[string]Stats, len(registry.wheels))
+	for name, reg := range registry.wheels {
+		reg.Lock()
+		stats[name] = reg.stats
+		reg.Unlock()
+	}
+	return stats
+}
+
+// Writes a human readable summary of the most recently published
+// snapshot of every registered Timer Wheel to w, one line per wheel,
+// sorted by name. See Register.
+func DumpRegistered(w io.Writer) error {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, name := range registeredNames() {
+		reg := registry.wheels[name]
+		reg.Lock()
+		now, length, stats := reg.now, reg.length, reg.stats
+		reg.Unlock()
+		_, err := fmt.Fprintf(w, "%s: now %v, pending %v, scheduled %v, fired %v, cancelled %v, cascades %v, levels %v\n",
+			name, now, length, stats.Scheduled, stats.Fired, stats.Cancelled, stats.Cascades, stats.Levels)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
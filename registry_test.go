@@ -0,0 +1,87 @@
+package gotimerwheel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	start := time.Unix(0, 0)
+	a, b := NewTimerWheel(start, 1, WithNilEvents()), NewTimerWheel(start, 1, WithNilEvents())
+	if err := Register("test-b", b); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("test-b")
+	if err := Register("test-a", a); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("test-a")
+	if err := Register("test-a", b); err != NameRegistered {
+		t.Errorf("Expected NameRegistered, but got %v", err)
+	}
+	if err := Register("test-c", b); err != WheelRegistered {
+		t.Errorf("Expected WheelRegistered, but got %v", err)
+	}
+	if tw, found := Lookup("test-a"); !found || tw != a {
+		t.Error("Expected to find registered wheel")
+	}
+	if names := RegisteredNames(); len(names) != 2 || names[0] != "test-a" || names[1] != "test-b" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+
+	a.ScheduleEventIn(5, nil)
+	b.ScheduleEventIn(5, nil)
+	b.ScheduleEventIn(500, nil)
+	// nothing is visible until published
+	if stats := RegisteredStats(); stats["test-a"].Scheduled != 0 {
+		t.Errorf("Expected unpublished stats, but got %v", stats)
+	}
+	a.PublishStats()
+	b.AdvanceTo(start, 0)
+	stats := RegisteredStats()
+	if stats["test-a"].Scheduled != 1 || stats["test-b"].Scheduled != 2 {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+	buf := new(bytes.Buffer)
+	if err := DumpRegistered(buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "test-a: ") || !strings.Contains(lines[1], "pending 2") {
+		t.Errorf("Unexpected dump:\n%s", buf)
+	}
+
+	if !Unregister("test-a") || Unregister("test-a") {
+		t.Error("Expected exactly one successful unregister")
+	}
+	if _, found := Lookup("test-a"); found {
+		t.Error("Expected unregistered wheel to be gone")
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	if err := Register("test-concurrent", tw); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("test-concurrent")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 100; idx++ {
+			RegisteredStats()
+			DumpRegistered(new(bytes.Buffer))
+		}
+	}()
+	for idx := 0; idx < 1000; idx++ {
+		tw.ScheduleEventIn(1, func(*time.Time) {})
+		tw.AdvanceBy(1, 0)
+	}
+	<-done
+	if stats := RegisteredStats()["test-concurrent"]; stats.Fired != 1000 {
+		t.Errorf("Expected 1000 fired, but got %+v", stats)
+	}
+}
@@ -0,0 +1,24 @@
+package gotimerwheel
+
+// Rebuilds the root wheel's ring with ringLength buckets, for example
+// to give a workload which has grown much denser a root wheel covering
+// more time, so that fewer events pass through the next wheels. Every
+// pending event is kept, along with its key, tag, handle and so on,
+// and the current time and bucketSize are unchanged. The next wheels
+// are rebuilt too, as their buckets are as wide as the root wheel's
+// whole ring; they keep the number of buckets they had. Resizing is
+// O(n). Panics if ringLength is less than 2.
+func (tw *TimerWheel) Resize(ringLength int) {
+	if ringLength < 2 {
+		panic("TimerWheel ring length must be at least 2")
+	}
+	if ringLength == len(tw.ring) {
+		return
+	}
+	sizes := []int{ringLength, tw.levelBuckets(1)}
+	if len(tw.levelSizes) > 1 {
+		sizes = append(sizes[:1], tw.levelSizes[1:]...)
+	}
+	tw.levelSizes = sizes
+	tw.rebuild(tw.bucketSize, ringLength)
+}
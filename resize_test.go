@@ -0,0 +1,78 @@
+package gotimerwheel
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestResize(t *testing.T) {
+	start := time.Unix(0, 0)
+	for _, ringLength := range []int{256, 4, 32} {
+		tw := NewTimerWheel(start, time.Millisecond, WithSliceBuckets())
+		rng := rand.New(rand.NewSource(1103))
+		var order []int
+		for idx := 0; idx < 2000; idx++ {
+			idx := idx
+			at := start.Add(time.Duration(rng.Int63n(int64(time.Hour))))
+			tw.ScheduleKeyedEventAt(string(rune(idx)), at, func(*time.Time) { order = append(order, idx) })
+		}
+		tw.AdvanceBy(100*time.Millisecond+500*time.Microsecond, 10)
+		now, length := tw.Now(), tw.Length()
+		var before []time.Time
+		tw.ForEach(func(at time.Time, e Event) bool {
+			before = append(before, at)
+			return true
+		})
+		// shares the events, so invokes them into order too
+		expected := tw.Clone()
+
+		tw.Resize(ringLength)
+		if len(tw.ring) != ringLength || !tw.Now().Equal(now) || tw.Length() != length {
+			t.Fatalf("Expected %v buckets with the time and events unchanged, but got %v", ringLength, len(tw.ring))
+		}
+		if err := tw.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+		idx := 0
+		tw.ForEach(func(at time.Time, e Event) bool {
+			if !at.Equal(before[idx]) {
+				t.Fatalf("Expected event %v at %v, but got %v", idx, before[idx], at)
+			}
+			idx++
+			return true
+		})
+		if !tw.CancelKey(string(rune(1999))) || !expected.CancelKey(string(rune(1999))) {
+			t.Fatal("Expected the keys to be kept")
+		}
+		order = nil
+		tw.AdvanceBy(2*time.Hour, 0)
+		got := order
+		order = nil
+		expected.AdvanceBy(2*time.Hour, 0)
+		if len(got) != len(order) || len(got) != length-1 {
+			t.Fatalf("Expected %v events invoked, but got %v", len(order), len(got))
+		}
+		for idx := range order {
+			if got[idx] != order[idx] {
+				t.Fatalf("Expected the same order of events as without resizing")
+			}
+		}
+	}
+}
+
+func TestResizeHierarchy(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewHierarchicalTimerWheel(start, []LevelSpec{{time.Millisecond, 16}, {16 * time.Millisecond, 8}})
+	tw.ScheduleEventAt(start.Add(time.Second), func(*time.Time) {})
+	tw.Resize(64)
+	if tw.levelBuckets(0) != 64 || tw.levelBuckets(1) != 8 || tw.levelBuckets(5) != 8 {
+		t.Fatalf("Expected the next wheels to keep their number of buckets, but got %v", tw.levelSizes)
+	}
+	if err := tw.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if tw.AdvanceBy(time.Second, 0) != 1 {
+		t.Error("Expected the event to be invoked")
+	}
+}
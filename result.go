@@ -0,0 +1,47 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// The outcome of AdvanceToWithResult.
+type AdvanceToResult struct {
+	// The number of events invoked.
+	Fired int
+	// True if the limit stopped the advance before every due event
+	// had been invoked. If so, the Timer Wheel's current time is left
+	// at the time of the next due event.
+	Truncated bool
+	// The Timer Wheel's current time once the advance has finished:
+	// the target time, unless the advance was truncated, or the
+	// target was in the past of the Timer Wheel's current time, in
+	// which case the current time is unchanged.
+	Reached time.Time
+	// The number of events still scheduled for the target time or
+	// before, which a further advance to the target would invoke.
+	// Always 0 unless Truncated.
+	RemainingDue int
+	// The time of the earliest event still scheduled, if HasNext.
+	Next    time.Time
+	HasNext bool
+}
+
+// Just the same as AdvanceTo, but reports more about the outcome, so
+// that a loop invoking events in batches can tell whether it needs to
+// call again straight away, or when it next needs to.
+func (tw *TimerWheel) AdvanceToWithResult(now time.Time, limit int) AdvanceToResult {
+	fired, truncated := tw.advanceTo(now, limitTo(limit))
+	result := AdvanceToResult{Fired: fired, Truncated: truncated, Reached: tw.Now()}
+	if truncated {
+		target := now.UnixNano()
+		tw.forEachEvent(func(event *eventNode) bool {
+			if event.at > target {
+				return false
+			}
+			result.RemainingDue++
+			return true
+		})
+	}
+	result.Next, result.HasNext = tw.NextEventAt()
+	return result
+}
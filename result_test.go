@@ -0,0 +1,39 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvanceToWithResult(t *testing.T) {
+	run := createBasicRun(t)
+	first, _ := run.NextEventAt()
+	result := run.AdvanceToWithResult(run.end, 10)
+	if result.Fired != 10 || !result.Truncated || !result.HasNext || !result.Next.Equal(run.Now()) {
+		t.Errorf("Expected a truncated pass of 10, but got %+v at %v", result, run.Now())
+	}
+	if !first.Before(result.Next) {
+		t.Errorf("Expected to have moved on from %v, but got %+v", first, result)
+	}
+	if !result.Reached.Equal(result.Next) || result.RemainingDue != run.targetExecCount-10 {
+		t.Errorf("Expected to have reached the next event with %v remaining, but got %+v", run.targetExecCount-10, result)
+	}
+	result = run.AdvanceToWithResult(run.end, 10)
+	if result.Fired != run.targetExecCount-10 || result.Truncated {
+		t.Errorf("Expected an untruncated pass of %v, but got %+v", run.targetExecCount-10, result)
+	}
+	if result.HasNext || result.RemainingDue != 0 || !result.Reached.Equal(run.end) {
+		t.Errorf("Expected no more events, but got %+v", result)
+	}
+	run.assertExecCount(run.targetExecCount)
+
+	// a limit that is exactly met doesn't count as truncation
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	tw.ScheduleEventIn(5, func(*time.Time) {})
+	if result := tw.AdvanceToWithResult(time.Unix(0, 10), 1); result.Fired != 1 || result.Truncated || result.HasNext {
+		t.Errorf("Expected a complete pass, but got %+v", result)
+	}
+	if count := tw.AdvanceBy(5, 0); count != 0 {
+		t.Errorf("Expected AdvanceBy to invoke nothing, but got %v", count)
+	}
+}
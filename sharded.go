@@ -0,0 +1,134 @@
+package gotimerwheel
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// A ShardedTimerWheel spreads events across several Timer Wheels,
+// each with its own lock, so that many goroutines can schedule events
+// concurrently without all contending for one lock. Events are
+// assigned to a shard by hashing a key supplied when scheduling.
+// Unlike a TimerWheel, a ShardedTimerWheel is safe for concurrent
+// use.
+type ShardedTimerWheel struct {
+	shards []shard
+	mutex  sync.Mutex
+}
+
+type shard struct {
+	sync.Mutex
+	tw *TimerWheel
+	// Events invoked by the shard's Timer Wheel, waiting to be run
+	// once its lock has been released.
+	fired []firedEvent
+}
+
+type firedEvent struct {
+	now time.Time
+	e   Event
+}
+
+// Creates a new ShardedTimerWheel of the indicated number of shards,
+// each of which is a Timer Wheel created with NewTimerWheel and the
+// remaining arguments.
+func NewShardedTimerWheel(shards int, startAt time.Time, bucketSize time.Duration, options ...Option) *ShardedTimerWheel {
+	if shards <= 0 {
+		panic("ShardedTimerWheel must have at least 1 shard")
+	}
+	stw := &ShardedTimerWheel{shards: make([]shard, shards)}
+	for idx := range stw.shards {
+		stw.shards[idx].tw = NewTimerWheel(startAt, bucketSize, options...)
+	}
+	return stw
+}
+
+func (stw *ShardedTimerWheel) shardFor(key string) *shard {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return &stw.shards[hash.Sum32()%uint32(len(stw.shards))]
+}
+
+// Schedules an event to be invoked at the indicated time, in the
+// shard chosen by key. See TimerWheel.ScheduleEventAt.
+func (stw *ShardedTimerWheel) ScheduleEventAt(key string, at time.Time, e Event) error {
+	s := stw.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	return s.tw.ScheduleEventAt(at, s.deferred(e))
+}
+
+// Schedules an event to be invoked at the ShardedTimerWheel's current
+// time plus the supplied duration, in the shard chosen by key.
+func (stw *ShardedTimerWheel) ScheduleEventIn(key string, in time.Duration, e Event) error {
+	s := stw.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	return s.tw.ScheduleEventIn(in, s.deferred(e))
+}
+
+// Wraps e so that, rather than being invoked with the shard locked,
+// it is run once the shard is unlocked, and so may itself schedule
+// events.
+func (s *shard) deferred(e Event) Event {
+	if e == nil {
+		return nil
+	}
+	return func(now *time.Time) {
+		s.fired = append(s.fired, firedEvent{now: *now, e: e})
+	}
+}
+
+// Returns the current time, which is the same for every shard.
+func (stw *ShardedTimerWheel) Now() time.Time {
+	s := &stw.shards[0]
+	s.Lock()
+	defer s.Unlock()
+	return s.tw.Now()
+}
+
+// Returns the number of scheduled events across every shard.
+func (stw *ShardedTimerWheel) Length() int {
+	length := 0
+	for idx := range stw.shards {
+		s := &stw.shards[idx]
+		s.Lock()
+		length += s.tw.Length()
+		s.Unlock()
+	}
+	return length
+}
+
+// Advances every shard to the indicated time, invoking the events
+// which are due. Events are invoked shard by shard: within a shard
+// they are invoked in order, but there is no ordering between events
+// of different shards. Events are invoked without the shard being
+// locked, so they may schedule further events; those due by now are
+// invoked before AdvanceTo returns. For the same reason, panics from
+// events are not recovered by the shards' panic policies. Only one
+// call to AdvanceTo runs at a time. Returns the number of events
+// invoked.
+func (stw *ShardedTimerWheel) AdvanceTo(now time.Time) int {
+	stw.mutex.Lock()
+	defer stw.mutex.Unlock()
+	count := 0
+	for idx := range stw.shards {
+		s := &stw.shards[idx]
+		for {
+			s.Lock()
+			s.tw.AdvanceTo(now, 0)
+			fired := s.fired
+			s.fired = nil
+			s.Unlock()
+			if len(fired) == 0 {
+				break
+			}
+			for _, f := range fired {
+				f.e(&f.now)
+			}
+			count += len(fired)
+		}
+	}
+	return count
+}
@@ -0,0 +1,47 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedTimerWheel(t *testing.T) {
+	start := time.Unix(0, 0)
+	stw := NewShardedTimerWheel(4, start, time.Millisecond)
+	var mutex sync.Mutex
+	invoked := 0
+	event := func(*time.Time) {
+		mutex.Lock()
+		invoked++
+		mutex.Unlock()
+	}
+	var wg sync.WaitGroup
+	for producer := 0; producer < 8; producer++ {
+		wg.Add(1)
+		go func(producer int) {
+			defer wg.Done()
+			for idx := 0; idx < 100; idx++ {
+				key := fmt.Sprint(producer, idx)
+				if err := stw.ScheduleEventIn(key, time.Duration(idx)*time.Millisecond, event); err != nil {
+					t.Error(err)
+				}
+			}
+		}(producer)
+	}
+	wg.Wait()
+	if stw.Length() != 800 {
+		t.Errorf("Expected 800 events, but got %v", stw.Length())
+	}
+	// an event which schedules another from within its shard
+	stw.ScheduleEventAt("chain", start.Add(200*time.Millisecond), func(now *time.Time) {
+		stw.ScheduleEventAt("chain", *now, event)
+	})
+	if count := stw.AdvanceTo(start.Add(time.Second)); count != 802 || invoked != 801 {
+		t.Errorf("Expected 802 events invoked, but got %v (%v counted)", count, invoked)
+	}
+	if stw.Length() != 0 || !stw.Now().Equal(start.Add(time.Second)) {
+		t.Errorf("Expected no events at %v, but got %v at %v", start.Add(time.Second), stw.Length(), stw.Now())
+	}
+}
@@ -0,0 +1,53 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type skewAlarm struct {
+	threshold int64
+	alarm     func(skew time.Duration)
+	// The skew measured by the most recent call to AdvanceToNow, and
+	// the largest measured.
+	last int64
+	max  int64
+}
+
+// Calls alarm whenever AdvanceToNow finds the Timer Wheel's current
+// time more than threshold behind the time it is about to advance
+// to: the Clock's time (see WithClock), less any jumps ignored by the
+// ClockJumpPolicy. This skew grows when the Timer Wheel is driven
+// less often than intended, because the driver has stalled or because
+// the events of the previous advance took a long time to run, or when
+// the limit of AdvanceToNow leaves events waiting. The alarm is called
+// before the advance, with the skew, on every call which finds the
+// skew over threshold. The skew is also reported in Stats, with or
+// without an alarm. Threshold must be greater than 0.
+func WithSkewAlarm(threshold time.Duration, alarm func(skew time.Duration)) Option {
+	if threshold <= 0 {
+		panic("TimerWheel skew alarm threshold must be greater than 0")
+	}
+	return func(tw *TimerWheel) {
+		tw.skew.threshold = int64(threshold)
+		tw.skew.alarm = alarm
+	}
+}
+
+// Notes how far target is ahead of the Timer Wheel's current time.
+func (tw *TimerWheel) measureSkew(target int64) {
+	s := &tw.skew
+	s.last = target - tw.now
+	if s.last < 0 {
+		s.last = 0
+	}
+	if s.last > s.max {
+		s.max = s.last
+	}
+	if s.alarm != nil && s.last > s.threshold {
+		s.alarm(time.Duration(s.last))
+	}
+}
+
+func (s *skewAlarm) reset() {
+	s.last, s.max = 0, 0
+}
@@ -0,0 +1,39 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkewAlarm(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := &manualClock{now: start}
+	var alarms []time.Duration
+	tw := NewTimerWheel(start, time.Millisecond, WithClock(clock), WithSkewAlarm(10*time.Millisecond, func(skew time.Duration) {
+		alarms = append(alarms, skew)
+	}))
+	for idx := 0; idx < 5; idx++ {
+		tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	}
+	clock.now = start.Add(5 * time.Millisecond)
+	tw.AdvanceToNow(0)
+	if stats := tw.Stats(); stats.Skew != 5*time.Millisecond || len(alarms) != 0 {
+		t.Errorf("Expected a skew of 5ms and no alarm, but got %v and %v", stats.Skew, alarms)
+	}
+	// The driver stalls, and then cannot catch up within its limit:
+	// the wheel stops at the event it has yet to invoke, at 7ms.
+	clock.now = start.Add(time.Second)
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	tw.ScheduleEventIn(2*time.Millisecond, func(*time.Time) {})
+	tw.AdvanceToNow(1)
+	clock.now = clock.now.Add(time.Millisecond)
+	tw.AdvanceToNow(0)
+	expected := []time.Duration{995 * time.Millisecond, 994 * time.Millisecond}
+	if len(alarms) != len(expected) || alarms[0] != expected[0] || alarms[1] != expected[1] {
+		t.Errorf("Expected alarms %v, but got %v", expected, alarms)
+	}
+	tw.AdvanceToNow(0)
+	if stats := tw.Stats(); stats.Skew != 0 || stats.MaxSkew != 995*time.Millisecond || len(alarms) != 2 {
+		t.Errorf("Expected the skew to have gone, but got %v and %v", stats.Skew, stats.MaxSkew)
+	}
+}
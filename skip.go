@@ -0,0 +1,41 @@
+package gotimerwheel
+
+// Called when the root wheel has run out of buckets whilst advancing
+// to now. Normally this just fetches the next window's events from
+// the next wheel. But if neither the next window nor any window up
+// to now contain events then we skip straight past all the empty
+// windows rather than stepping through them one at a time.
+func (tw *TimerWheel) wrap(now int64) {
+	ringWidth := tw.ringWidth()
+	target := now
+	at, found := tw.next.nextEventAt()
+	if !found {
+		at, found = tw.root.overflow.nextEventAt()
+	}
+	if found && at < target {
+		target = at
+	}
+	if target < tw.start+2*ringWidth {
+		tw.fetchFromNext()
+	} else {
+		tw.skipTo(target)
+	}
+}
+
+// Moves the window of this wheel forwards so that it contains t,
+// cascading down the events of the window that ends up containing
+// t. There must be no scheduled events before t.
+func (tw *TimerWheel) skipTo(t int64) {
+	ringWidth := tw.ringWidth()
+	if windows := (t - tw.start) / ringWidth; windows > 0 {
+		// Move to the window before the one containing t, and make
+		// sure the next wheel's current bucket is the one we need,
+		// then fetch it as normal.
+		tw.start += (windows - 1) * ringWidth
+		if tw.next != nil {
+			tw.next.skipTo(tw.start + ringWidth)
+		}
+		tw.fetchFromNext()
+	}
+	tw.ringIdx = int((t - tw.start) / tw.bucketSize)
+}
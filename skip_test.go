@@ -0,0 +1,71 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+// Schedules events either side of the boundaries of windows at
+// several levels of the hierarchy, then advances across huge empty
+// spans, checking that each event is invoked by exactly the advance
+// that first reaches it.
+func TestSkipEmptyWindows(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	level1 := int64(ringLength * ringLength)
+	level2 := level1 * ringLength
+	level3 := level2 * ringLength
+	// a span to skip that ends part way into windows at every level
+	far := 3*level3 + 5*level2 + 7*level1 + 11*ringLength + 13
+	times := []int64{
+		// just before and just after the end of the first windows
+		ringLength - 1, ringLength, level1 - 1, level1, level2 - 1, level2,
+		// just before and just after the far, skipped to, time
+		far - 1, far, far + 1,
+		// just before and just after windows beyond it
+		far - far%level2 + level2 - 1, far - far%level2 + level2,
+		10*level3 - 1, 10 * level3,
+	}
+	fired := make(map[int64]int)
+	for _, ns := range times {
+		ns := ns
+		tw.ScheduleEventAt(time.Unix(0, ns), func(now *time.Time) {
+			if now.Before(time.Unix(0, ns)) {
+				t.Errorf("Event at %v invoked early at %v", ns, now.UnixNano())
+			}
+			fired[ns]++
+		})
+	}
+	// advance to each event time and to just before it, and straight
+	// over several at once
+	targets := []int64{ringLength - 1, level1, level2 - 2, level2, far - 1, far, 10*level3 - 2, 10 * level3}
+	prev := int64(-1)
+	for _, target := range targets {
+		tw.AdvanceTo(time.Unix(0, target), 0)
+		for _, ns := range times {
+			expected := 0
+			if ns <= target {
+				expected = 1
+			}
+			if fired[ns] != expected {
+				t.Fatalf("After advancing from %v to %v, event at %v invoked %v times", prev, target, ns, fired[ns])
+			}
+		}
+		prev = target
+	}
+	assertNowLength(t, tw, time.Unix(0, 10*level3), 0)
+	// stepping through every window would cascade millions of times
+	if cascades := tw.Stats().Cascades; cascades > 1000 {
+		t.Errorf("Expected empty windows to be skipped, but cascaded %v times", cascades)
+	}
+}
+
+func BenchmarkAdvanceFarFuture(b *testing.B) {
+	start := time.Unix(0, 0)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		tw := NewTimerWheel(start, time.Millisecond)
+		tw.ScheduleEventIn(24*time.Hour, func(*time.Time) {})
+		tw.AdvanceBy(48*time.Hour, 0)
+	}
+}
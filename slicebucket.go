@@ -0,0 +1,95 @@
+package gotimerwheel
+
+import (
+	"sort"
+)
+
+type sliceEntry struct {
+	at    int64
+	event *eventNode
+}
+
+// By default, each bucket of the root wheel is a linked list of
+// events. With this option, buckets of the root wheel instead keep
+// their events in a growable slice, with each event's time alongside
+// it. Inserting into the middle of a bucket is then a binary search
+// and a copy rather than a walk along the list, and invoking a bucket
+// walks contiguous memory, which suits buckets routinely holding
+// thousands of events. Buckets of the other wheels of the hierarchy
+// are unaffected.
+func WithSliceBuckets() Option {
+	return func(tw *TimerWheel) {
+		tw.sliceBuckets()
+	}
+}
+
+func (tw *TimerWheel) sliceBuckets() {
+	for idx := range tw.ring {
+		tw.ring[idx].sliced = true
+	}
+}
+
+// The live entries of a sliced bucket.
+func (b *bucket) live() []sliceEntry {
+	return b.entries[b.head:]
+}
+
+// Only looks at the events themselves for entries for the same time.
+func (b *bucket) entryBefore(entry, other sliceEntry) bool {
+	return entry.at < other.at || (entry.at == other.at && before(entry.event, other.event, b.tieBreak))
+}
+
+func (b *bucket) insertEntry(event *eventNode) {
+	entries := b.live()
+	// After every event which it is not to be invoked before.
+	entry := sliceEntry{at: event.at, event: event}
+	idx := b.head + sort.Search(len(entries), func(i int) bool { return b.entryBefore(entry, entries[i]) })
+	b.entries = append(b.entries, sliceEntry{})
+	copy(b.entries[idx+1:], b.entries[idx:])
+	b.entries[idx] = entry
+	b.count++
+}
+
+func (b *bucket) appendEntry(event *eventNode) {
+	entry := sliceEntry{at: event.at, event: event}
+	if entries := b.live(); len(entries) > 0 && b.entryBefore(entry, entries[len(entries)-1]) {
+		b.unsorted = true
+	}
+	b.entries = append(b.entries, entry)
+	b.count++
+}
+
+func (b *bucket) popEntry() {
+	b.entries[b.head] = sliceEntry{}
+	b.head++
+	b.count--
+	if b.head == len(b.entries) {
+		// Reuse the slice from the start.
+		b.entries = b.entries[:0]
+		b.head = 0
+	}
+}
+
+func (b *bucket) removeEntry(event *eventNode) bool {
+	entries := b.live()
+	for idx := range entries {
+		if entries[idx].event == event {
+			copy(entries[idx:], entries[idx+1:])
+			b.entries[len(b.entries)-1] = sliceEntry{}
+			b.entries = b.entries[:len(b.entries)-1]
+			b.count--
+			if b.head == len(b.entries) {
+				b.entries = b.entries[:0]
+				b.head = 0
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (b *bucket) sortEntries() {
+	entries := b.live()
+	sort.SliceStable(entries, func(i, j int) bool { return b.entryBefore(entries[i], entries[j]) })
+	b.unsorted = false
+}
@@ -0,0 +1,91 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Checks that slice-backed buckets make no difference to which events
+// are invoked when, nor to their order, including events cancelled,
+// scheduled in batches and cascaded out of order.
+func TestSliceBucketsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 3)
+	sliced := NewTimerWheel(start, 3, WithSliceBuckets())
+	var twInvoked, slicedInvoked []int
+	now := int64(0)
+	id := 0
+	for step := 0; step < 300; step++ {
+		var twBatch, slicedBatch []ScheduledEvent
+		for count := rng.Intn(20); count > 0; count-- {
+			at := time.Unix(0, now+rng.Int63n(500))
+			eventID := id
+			id++
+			twEvent := func(*time.Time) { twInvoked = append(twInvoked, eventID) }
+			slicedEvent := func(*time.Time) { slicedInvoked = append(slicedInvoked, eventID) }
+			switch rng.Intn(3) {
+			case 0:
+				tw.ScheduleEventAt(at, twEvent)
+				sliced.ScheduleEventAt(at, slicedEvent)
+			case 1:
+				key := fmt.Sprint(rng.Intn(50))
+				tw.ScheduleKeyedEventAt(key, at, twEvent)
+				sliced.ScheduleKeyedEventAt(key, at, slicedEvent)
+			default:
+				twBatch = append(twBatch, ScheduledEvent{At: at, Event: twEvent})
+				slicedBatch = append(slicedBatch, ScheduledEvent{At: at, Event: slicedEvent})
+			}
+		}
+		tw.ScheduleEvents(twBatch)
+		sliced.ScheduleEvents(slicedBatch)
+		if key := fmt.Sprint(rng.Intn(50)); tw.CancelKey(key) != sliced.CancelKey(key) {
+			t.Fatalf("Cancelling key %v differs", key)
+		}
+		now += rng.Int63n(30)
+		if twCount, slicedCount := tw.AdvanceTo(time.Unix(0, now), 0), sliced.AdvanceTo(time.Unix(0, now), 0); twCount != slicedCount {
+			t.Fatalf("At %v, expected %v events invoked, but got %v", now, twCount, slicedCount)
+		}
+		if tw.Length() != sliced.Length() {
+			t.Fatalf("At %v, expected %v events, but got %v", now, tw.Length(), sliced.Length())
+		}
+	}
+	clone := sliced.Clone()
+	cloneLength := clone.Length()
+	tw.AdvanceBy(time.Second, 0)
+	sliced.AdvanceBy(time.Second, 0)
+	if len(twInvoked) != len(slicedInvoked) || !sliced.IsEmpty() {
+		t.Fatalf("Expected %v invocations, but got %v", len(twInvoked), len(slicedInvoked))
+	}
+	for idx := range twInvoked {
+		if twInvoked[idx] != slicedInvoked[idx] {
+			t.Fatalf("Invocation %v differs: %v vs %v", idx, twInvoked[idx], slicedInvoked[idx])
+		}
+	}
+	if cloneLength == 0 || clone.AdvanceBy(time.Second, 0) != cloneLength || !clone.IsEmpty() {
+		t.Error("Expected the clone to keep its own events")
+	}
+}
+
+// Fills a single bucket with thousands of events and invokes them.
+func BenchmarkAdvanceHotBucket(b *testing.B) {
+	for _, sliced := range []bool{false, true} {
+		b.Run(fmt.Sprintf("sliced=%v", sliced), func(b *testing.B) {
+			event := func(*time.Time) {}
+			rng := rand.New(rand.NewSource(1))
+			for n := 0; n < b.N; n++ {
+				var options []Option
+				if sliced {
+					options = append(options, WithSliceBuckets())
+				}
+				tw := NewTimerWheel(time.Unix(0, 0), time.Second, options...)
+				for idx := 0; idx < 5000; idx++ {
+					tw.ScheduleEventAt(time.Unix(0, rng.Int63n(int64(time.Second))), event)
+				}
+				tw.AdvanceBy(time.Second, 0)
+			}
+		})
+	}
+}
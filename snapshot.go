@@ -0,0 +1,120 @@
+package gotimerwheel
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// An immutable copy of a Timer Wheel's schedule at some moment, which
+// may be read from any goroutine. See ReadView.
+type Snapshot struct {
+	now    time.Time
+	stats  Stats
+	events []snapshotEvent
+}
+
+type snapshotEvent struct {
+	at time.Time
+	e  Event
+}
+
+// Returns the Timer Wheel's current time when the Snapshot was taken.
+func (s *Snapshot) Now() time.Time {
+	return s.now
+}
+
+// Returns the number of events scheduled when the Snapshot was taken.
+func (s *Snapshot) Length() int {
+	return len(s.events)
+}
+
+// Returns the time of the earliest event scheduled when the Snapshot
+// was taken. If there were no events then false is returned.
+func (s *Snapshot) NextEventAt() (time.Time, bool) {
+	if len(s.events) == 0 {
+		return time.Time{}, false
+	}
+	return s.events[0].at, true
+}
+
+// Returns the Timer Wheel's statistics when the Snapshot was taken.
+func (s *Snapshot) Stats() Stats {
+	return s.stats
+}
+
+// Calls f for every event scheduled when the Snapshot was taken, in
+// the order in which they would be invoked. Iteration stops early if
+// f returns false. See TimerWheel.ForEach.
+func (s *Snapshot) ForEach(f func(at time.Time, e Event) bool) {
+	for _, event := range s.events {
+		if !f(event.at, event.e) {
+			return
+		}
+	}
+}
+
+// Returns a Snapshot of the Timer Wheel as it is now. Taking a
+// Snapshot copies every scheduled event, so is O(n).
+func (tw *TimerWheel) Snapshot() *Snapshot {
+	s := &Snapshot{now: tw.Now(), stats: tw.Stats(), events: make([]snapshotEvent, 0, tw.Length())}
+	tw.forEachEvent(func(event *eventNode) bool {
+		s.events = append(s.events, snapshotEvent{at: tw.toTime(event.at), e: event.fun})
+		return true
+	})
+	return s
+}
+
+// A ReadView lets other goroutines, such as metrics and admin
+// endpoints, read Snapshots of a Timer Wheel without locking it, and
+// without ever blocking the goroutine which drives it. See
+// TimerWheel.ReadView. Unlike a TimerWheel, a ReadView is safe for
+// concurrent use.
+type ReadView struct {
+	snapshot atomic.Value
+	// Set by readers to ask for a fresh Snapshot.
+	requested int32
+}
+
+// Returns the Timer Wheel's ReadView, creating it (and publishing a
+// first Snapshot) if need be. Like every other TimerWheel method, this
+// must be called from the goroutine which owns the Timer Wheel: it is
+// the returned ReadView which may be shared.
+func (tw *TimerWheel) ReadView() *ReadView {
+	if tw.readView == nil {
+		tw.readView = &ReadView{}
+		tw.PublishSnapshot()
+	}
+	return tw.readView
+}
+
+// Returns the most recently published Snapshot, and asks the Timer
+// Wheel for a fresh one. Snapshots are taken by the goroutine driving
+// the Timer Wheel, at the end of its next call to AdvanceTo (or
+// AdvanceBy and so on) after a fresh one has been asked for, so the
+// cost of copying the schedule is only paid while someone is reading
+// it. The Snapshot returned is therefore as of the last advance after
+// the previous call to Snapshot, or as of PublishSnapshot.
+func (rv *ReadView) Snapshot() *Snapshot {
+	atomic.StoreInt32(&rv.requested, 1)
+	return rv.snapshot.Load().(*Snapshot)
+}
+
+// Publishes a Snapshot of the Timer Wheel as it is now to its
+// ReadView, whether or not one has been asked for. There is no need
+// to call this unless events have been scheduled or cancelled since
+// the last advance and readers should see them straight away. Does
+// nothing if the Timer Wheel has no ReadView.
+func (tw *TimerWheel) PublishSnapshot() {
+	if tw.readView == nil {
+		return
+	}
+	atomic.StoreInt32(&tw.readView.requested, 0)
+	tw.readView.snapshot.Store(tw.Snapshot())
+}
+
+// Publishes a Snapshot if a reader has asked for one.
+func (tw *TimerWheel) publishRequestedSnapshot() {
+	if rv := tw.readView; rv != nil && atomic.LoadInt32(&rv.requested) == 1 {
+		tw.PublishSnapshot()
+	}
+}
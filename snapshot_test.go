@@ -0,0 +1,96 @@
+package gotimerwheel
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	tw.ScheduleEventAt(start.Add(time.Hour), nop)
+	tw.ScheduleEventAt(start.Add(2*time.Millisecond), nop)
+	s := tw.Snapshot()
+	tw.AdvanceBy(time.Second, 0)
+	if s.Length() != 2 || !s.Now().Equal(start) || s.Stats().Scheduled != 2 {
+		t.Errorf("Expected the snapshot to be unchanged by the advance, but got %v", s.Length())
+	}
+	if at, found := s.NextEventAt(); !found || !at.Equal(start.Add(2*time.Millisecond)) {
+		t.Errorf("Expected the next event at 2ms, but got %v", at)
+	}
+	var times []time.Time
+	s.ForEach(func(at time.Time, e Event) bool {
+		times = append(times, at)
+		return true
+	})
+	if len(times) != 2 || !times[1].Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected both events in order, but got %v", times)
+	}
+	if _, found := (&Snapshot{}).NextEventAt(); found {
+		t.Error("Expected no next event in an empty snapshot")
+	}
+}
+
+func TestReadView(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	nop := func(*time.Time) {}
+	tw.ScheduleEventAt(start.Add(time.Millisecond), nop)
+	rv := tw.ReadView()
+	if tw.ReadView() != rv {
+		t.Error("Expected the same ReadView")
+	}
+	if s := rv.Snapshot(); s.Length() != 1 || !s.Now().Equal(start) {
+		t.Errorf("Expected the first snapshot, but got %v", s.Length())
+	}
+	tw.ScheduleEventAt(start.Add(time.Hour), nop)
+	// only published by the next advance
+	if s := rv.Snapshot(); s.Length() != 1 {
+		t.Errorf("Expected the first snapshot still, but got %v", s.Length())
+	}
+	tw.AdvanceBy(time.Millisecond, 0)
+	if s := rv.Snapshot(); s.Length() != 1 || !s.Now().Equal(start.Add(time.Millisecond)) {
+		t.Errorf("Expected a snapshot after the advance, but got %v at %v", s.Length(), s.Now())
+	}
+	// not asked for, so not taken
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.AdvanceBy(time.Millisecond, 0)
+	if s := rv.Snapshot(); !s.Now().Equal(start.Add(2 * time.Millisecond)) {
+		t.Errorf("Expected the snapshot asked for by the previous read, but got %v", s.Now())
+	}
+	tw.ScheduleEventAt(start.Add(time.Minute), nop)
+	tw.PublishSnapshot()
+	if s := rv.Snapshot(); s.Length() != 2 {
+		t.Errorf("Expected the published snapshot, but got %v", s.Length())
+	}
+
+	// readers on other goroutines never block the advancing one
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for idx := 0; idx < 4; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := rv.Snapshot()
+				s.ForEach(func(time.Time, Event) bool { return true })
+				if s.Length() > 0 {
+					s.NextEventAt()
+				}
+			}
+		}()
+	}
+	for idx := 0; idx < 1000; idx++ {
+		tw.ScheduleEventIn(time.Millisecond, nop)
+		tw.AdvanceBy(time.Millisecond, 0)
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,130 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// One stage of a StagedTimeout.
+type Stage struct {
+	// How long after the StagedTimeout is armed this stage fires.
+	After time.Duration
+	// Invoked when the stage fires, with the same argument as an
+	// Event. If it returns true then the timeout is resolved: all
+	// stages which have not yet fired are cancelled. May be nil.
+	Fire func(now *time.Time) (resolved bool)
+}
+
+// A StagedTimeout arms a sequence of deadlines as a single object,
+// for example warn after 5s, retry after 10s and abort after 30s.
+// Stages fire in order of their After durations. As with the Timer
+// Wheel, only the goroutine driving it may use a StagedTimeout, though
+// others can through Intake.Do.
+type StagedTimeout struct {
+	tw      *TimerWheel
+	stages  []Stage
+	pending []*eventNode
+	// The Timer Wheel's count of Clears when the stages were armed.
+	clears uint64
+}
+
+// Creates a StagedTimeout and arms all its stages relative to the
+// Timer Wheel's current time. Returns ScheduledInPast if any stage
+// has a negative After, and otherwise any error from scheduling the
+// stages (for example Closed), in which case none of them are
+// armed.
+func (tw *TimerWheel) NewStagedTimeout(stages ...Stage) (*StagedTimeout, error) {
+	for _, stage := range stages {
+		if stage.After < 0 {
+			return nil, tw.pastError(tw.now + int64(stage.After))
+		}
+	}
+	st := &StagedTimeout{
+		tw:      tw,
+		stages:  stages,
+		pending: make([]*eventNode, len(stages)),
+	}
+	if err := st.arm(); err != nil {
+		st.Cancel()
+		return nil, err
+	}
+	return st, nil
+}
+
+// Returns true if any stage has yet to fire. Stages removed by Clear,
+// Reset or Close of the Timer Wheel never will.
+func (st *StagedTimeout) Active() bool {
+	if st.cleared() {
+		return false
+	}
+	for _, event := range st.pending {
+		if event != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancels every stage which has yet to fire. Returns false if there
+// were none.
+func (st *StagedTimeout) Cancel() bool {
+	if st.cleared() {
+		for idx := range st.pending {
+			st.pending[idx] = nil
+		}
+		return false
+	}
+	cancelled := false
+	for idx, event := range st.pending {
+		if event != nil {
+			st.tw.cancelEvent(event)
+			st.pending[idx] = nil
+			cancelled = true
+		}
+	}
+	return cancelled
+}
+
+// Cancels every stage which has yet to fire and then re-arms all the
+// stages relative to the Timer Wheel's current time. This works
+// whether or not the timeout has already resolved or fired. Returns
+// any error from scheduling the stages (for example Closed), in which
+// case none of them are armed.
+func (st *StagedTimeout) Reset() error {
+	st.Cancel()
+	if err := st.arm(); err != nil {
+		st.Cancel()
+		return err
+	}
+	return nil
+}
+
+func (st *StagedTimeout) cleared() bool {
+	return st.clears != st.tw.clears
+}
+
+// Schedules every stage, returning the first error. After is never
+// negative, so the stages are never in the past.
+func (st *StagedTimeout) arm() error {
+	st.clears = st.tw.clears
+	var firstErr error
+	for idx := range st.stages {
+		idx := idx
+		at := st.tw.Now().Add(st.stages[idx].After)
+		event := st.tw.newEvent(eventNode{at: at.UnixNano(), fun: func(now *time.Time) { st.fire(idx, now) }})
+		if err := st.tw.scheduleEvent(event); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		st.pending[idx] = event
+	}
+	return firstErr
+}
+
+func (st *StagedTimeout) fire(idx int, now *time.Time) {
+	st.pending[idx] = nil
+	if fire := st.stages[idx].Fire; fire != nil && fire(now) {
+		st.Cancel()
+	}
+}
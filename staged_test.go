@@ -0,0 +1,80 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStagedTimeout(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1)
+	fired := []string{}
+	stage := func(after time.Duration, name string, resolves bool) Stage {
+		return Stage{After: after, Fire: func(*time.Time) bool {
+			fired = append(fired, name)
+			return resolves
+		}}
+	}
+	st, err := tw.NewStagedTimeout(stage(30, "abort", true), stage(5, "warn", false), stage(10, "retry", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNowLength(t, tw, start, 3)
+	// all due in one advance, but retry resolves so abort never fires
+	tw.AdvanceTo(time.Unix(0, 100), 0)
+	if len(fired) != 2 || fired[0] != "warn" || fired[1] != "retry" {
+		t.Errorf("Expected warn then retry, but got %v", fired)
+	}
+	if st.Active() || st.Cancel() {
+		t.Error("Expected timeout to be resolved")
+	}
+	assertNowLength(t, tw, time.Unix(0, 100), 0)
+
+	// reset re-arms everything relative to now
+	fired = fired[:0]
+	st.Reset()
+	tw.AdvanceTo(time.Unix(0, 107), 0)
+	if len(fired) != 1 || fired[0] != "warn" || !st.Active() {
+		t.Errorf("Expected only warn, but got %v", fired)
+	}
+	st.Reset()
+	tw.AdvanceTo(time.Unix(0, 114), 0)
+	if len(fired) != 2 || fired[1] != "warn" {
+		t.Errorf("Expected warn again after reset, but got %v", fired)
+	}
+	if !st.Cancel() || st.Active() {
+		t.Error("Expected to cancel the remaining stages")
+	}
+	tw.AdvanceTo(time.Unix(0, 1000), 0)
+	if len(fired) != 2 {
+		t.Errorf("Expected nothing more to fire, but got %v", fired)
+	}
+
+	if _, err := tw.NewStagedTimeout(Stage{After: -1}); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+}
+
+// Once every stage has fired or been cancelled, Cancel is a no-op
+// that returns false, however often it is called.
+func TestStagedTimeoutAfterDone(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), 1)
+	fired := 0
+	st, _ := tw.NewStagedTimeout(Stage{After: 1, Fire: func(*time.Time) bool { fired++; return false }})
+	tw.AdvanceTo(time.Unix(0, 1), 0)
+	for idx := 0; idx < 2; idx++ {
+		if st.Active() || st.Cancel() {
+			t.Error("Expected fired timeout to be inactive and ignore Cancel")
+		}
+	}
+	cancelled, _ := tw.NewStagedTimeout(Stage{After: 5}, Stage{After: 10})
+	if !cancelled.Cancel() || cancelled.Cancel() || cancelled.Active() {
+		t.Error("Expected only the first Cancel to succeed")
+	}
+	tw.AdvanceTo(time.Unix(0, 20), 0)
+	if fired != 1 {
+		t.Errorf("Expected 1 stage fired, but got %v", fired)
+	}
+	assertNowLength(t, tw, time.Unix(0, 20), 0)
+}
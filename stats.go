@@ -0,0 +1,85 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Statistics about a Timer Wheel, as returned by Stats. All totals
+// are cumulative since the Timer Wheel was created.
+type Stats struct {
+	// Number of events successfully scheduled.
+	Scheduled uint64
+	// Number of events invoked.
+	Fired uint64
+	// Number of events not invoked because they were too late (see
+	// WithMaxLateness).
+	Expired uint64
+	// Number of events removed before being invoked, including keyed
+	// events replaced by rescheduling the same key, events evicted
+	// (see WithEviction) and events moved out by Merge.
+	Cancelled uint64
+	// Number of events scheduled, or refused, with the same tag and
+	// time as an already scheduled event (see
+	// WithDuplicateDetection).
+	Duplicates uint64
+	// Number of times a bucket of a coarser wheel in the hierarchy
+	// has been moved down into a finer wheel.
+	Cascades uint64
+	// The largest number of events ever held in a single bucket of
+	// the root wheel. If this is regularly well above 100 then
+	// consider a smaller bucketSize.
+	MaxBucketOccupancy int
+	// The number of currently scheduled events at each level of the
+	// hierarchy. Index 0 is the root wheel, whose buckets are
+	// bucketSize wide; each subsequent level's buckets are as wide as
+	// the whole ring of the previous level.
+	Levels []int
+	// The number of currently scheduled events held in the overflow
+	// heap (see WithOverflowHeap).
+	Overflow int
+	// How late events have been invoked, if WithLagHistogram was
+	// given.
+	Lag LagHistogram
+	// How far the Timer Wheel's current time was behind the time
+	// AdvanceToNow advanced to, at the most recent call, and the
+	// largest such gap. See WithSkewAlarm.
+	Skew    time.Duration
+	MaxSkew time.Duration
+}
+
+type counters struct {
+	scheduled          uint64
+	fired              uint64
+	expired            uint64
+	cancelled          uint64
+	cascades           uint64
+	duplicates         uint64
+	maxBucketOccupancy int
+}
+
+func (c *counters) noteOccupancy(count int) {
+	if count > c.maxBucketOccupancy {
+		c.maxBucketOccupancy = count
+	}
+}
+
+// Returns statistics about the Timer Wheel. This is O(buckets).
+func (tw *TimerWheel) Stats() Stats {
+	stats := Stats{
+		Scheduled:          tw.stats.scheduled,
+		Fired:              tw.stats.fired,
+		Expired:            tw.stats.expired,
+		Cancelled:          tw.stats.cancelled,
+		Cascades:           tw.stats.cascades,
+		Duplicates:         tw.stats.duplicates,
+		MaxBucketOccupancy: tw.stats.maxBucketOccupancy,
+		Overflow:           len(tw.overflow.events),
+		Lag:                tw.lag.histogram(),
+		Skew:               time.Duration(tw.skew.last),
+		MaxSkew:            time.Duration(tw.skew.max),
+	}
+	for level := tw; level != nil; level = level.next {
+		stats.Levels = append(stats.Levels, level.levelLength())
+	}
+	return stats
+}
@@ -0,0 +1,54 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	run := createBasicRun(t)
+	stats := run.Stats()
+	if stats.Scheduled != uint64(run.targetExecCount) {
+		t.Errorf("Expected %v scheduled, but found %v", run.targetExecCount, stats.Scheduled)
+	}
+	if stats.Fired != 0 || stats.Cascades != 0 {
+		t.Errorf("Expected nothing fired or cascaded yet: %+v", stats)
+	}
+	// bucket 10-14 holds events at 10, 13, 14, 14
+	if stats.MaxBucketOccupancy != 4 {
+		t.Errorf("Expected max bucket occupancy of 4, but found %v", stats.MaxBucketOccupancy)
+	}
+	assertLevels(t, stats.Levels, 8, 6)
+
+	run.AdvanceTo(run.end, 0)
+	stats = run.Stats()
+	if stats.Fired != uint64(run.targetExecCount) {
+		t.Errorf("Expected %v fired, but found %v", run.targetExecCount, stats.Fired)
+	}
+	if stats.Cascades == 0 {
+		t.Error("Expected at least one cascade")
+	}
+	assertLevels(t, stats.Levels, 0)
+}
+
+func TestStatsLevels(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, 1, WithNilEvents())
+	tw.ScheduleEventAt(time.Unix(0, ringLength-1), nil)
+	tw.ScheduleEventAt(time.Unix(0, ringLength), nil)
+	tw.ScheduleEventAt(time.Unix(0, ringLength*(ringLength+1)), nil)
+	assertLevels(t, tw.Stats().Levels, 1, 1, 1)
+}
+
+func assertLevels(t *testing.T, levels []int, expected ...int) {
+	if len(levels) != len(expected) {
+		t.Errorf("Expected levels %v, but found %v", expected, levels)
+		return
+	}
+	for idx, count := range expected {
+		if levels[idx] != count {
+			t.Errorf("Expected levels %v, but found %v", expected, levels)
+			return
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Schedules an event, labelled with tag, to be invoked at the
+// indicated time. Tags are free-form labels used for grouping events,
+// for example in aggregated notifications (see WithAggregation).
+// Otherwise, this is just the same as ScheduleEventAt.
+func (tw *TimerWheel) ScheduleTaggedEventAt(tag string, at time.Time, e Event) error {
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: at.UnixNano(), fun: e, tag: tag}))
+}
+
+// Schedules an event, labelled with tag, to be invoked at the current
+// Timer Wheel's time plus the supplied duration. See
+// ScheduleTaggedEventAt.
+func (tw *TimerWheel) ScheduleTaggedEventIn(tag string, in time.Duration, e Event) error {
+	return tw.ScheduleTaggedEventAt(tag, tw.after(in), e)
+}
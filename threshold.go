@@ -0,0 +1,41 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type bucketThreshold struct {
+	threshold int
+	exceeded  func(start, end time.Time, count int)
+}
+
+// Calls exceeded whenever a bucket of the root wheel comes to hold
+// more than threshold events, with the time range the bucket covers
+// and its new count. Exceeded is called once each time a bucket
+// crosses the threshold, not for every event beyond it. As the root
+// wheel's buckets should normally hold no more than around 100
+// events (see NewTimerWheel), this shows when bucketSize is too
+// large for the workload. Exceeded is called from within whichever
+// call schedules or cascades the event, and must not schedule or
+// cancel events. Threshold must be at least 1.
+func WithBucketThreshold(threshold int, exceeded func(start, end time.Time, count int)) Option {
+	if threshold < 1 {
+		panic("TimerWheel bucket threshold must be at least 1")
+	}
+	return func(tw *TimerWheel) {
+		tw.threshold = bucketThreshold{threshold: threshold, exceeded: exceeded}
+	}
+}
+
+// Called once added events have been put in the root wheel's bucket
+// idx.
+func (tw *TimerWheel) noteOccupancy(idx int, added int) {
+	count := tw.ring[idx].count
+	tw.stats.noteOccupancy(count)
+	bt := &tw.threshold
+	if bt.exceeded == nil || count <= bt.threshold || count-added > bt.threshold {
+		return
+	}
+	start := tw.start + tw.bucketSize*int64(idx)
+	bt.exceeded(tw.toTime(start), tw.toTime(start+tw.bucketSize), count)
+}
@@ -0,0 +1,43 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketThreshold(t *testing.T) {
+	start := time.Unix(0, 0)
+	type crossing struct {
+		start, end time.Time
+		count      int
+	}
+	var crossings []crossing
+	tw := NewTimerWheel(start, time.Millisecond, WithBucketThreshold(2, func(start, end time.Time, count int) {
+		crossings = append(crossings, crossing{start, end, count})
+	}))
+	nop := func(*time.Time) {}
+	for idx := 0; idx < 4; idx++ {
+		tw.ScheduleEventAt(start.Add(3*time.Millisecond), nop)
+	}
+	if len(crossings) != 1 {
+		t.Fatalf("Expected the threshold to be crossed once, but got %v", crossings)
+	}
+	if c := crossings[0]; !c.start.Equal(start.Add(3*time.Millisecond)) || !c.end.Equal(start.Add(4*time.Millisecond)) || c.count != 3 {
+		t.Errorf("Unexpected crossing %v", c)
+	}
+
+	// a batch which takes a bucket straight past the threshold, and
+	// events cascaded down from the next wheel
+	events := []ScheduledEvent{}
+	for idx := 0; idx < 5; idx++ {
+		events = append(events, ScheduledEvent{At: start.Add(5 * time.Millisecond), Event: nop})
+		events = append(events, ScheduledEvent{At: start.Add(40 * time.Millisecond), Event: nop})
+	}
+	if err := tw.ScheduleEvents(events); err != nil {
+		t.Fatal(err)
+	}
+	tw.AdvanceTo(start.Add(32*time.Millisecond), 0)
+	if len(crossings) != 3 || crossings[1].count != 5 || crossings[2].count != 3 {
+		t.Errorf("Expected crossings by the batch and the cascade, but got %v", crossings)
+	}
+}
@@ -0,0 +1,76 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A Throttler invokes an event at most once per interval of the
+// Timer Wheel's time, however often it is called. See NewThrottler.
+type Throttler struct {
+	tw       *TimerWheel
+	interval int64
+	trailing bool
+	f        Event
+	// The time of the most recent invocation, if invoked is set.
+	last    int64
+	invoked bool
+	trailer *eventNode
+}
+
+// Creates a Throttler for f. If trailing is set then a call which is
+// throttled arranges for f to be invoked once more at the end of the
+// interval, so the last call is never lost. Interval must be greater
+// than 0, and f must not be nil.
+func (tw *TimerWheel) NewThrottler(interval time.Duration, trailing bool, f Event) *Throttler {
+	if interval <= 0 {
+		panic("Throttler interval must be greater than 0")
+	}
+	if f == nil {
+		panic("Throttler event must not be nil")
+	}
+	return &Throttler{tw: tw, interval: int64(interval), trailing: trailing, f: f}
+}
+
+// Invokes the Throttler's event straight away, with the Timer Wheel's
+// current time, unless it has been invoked within the last interval.
+// Otherwise, if the Throttler is trailing, the event is scheduled for
+// the end of the interval, unless it already has been. Returns true
+// if the event was invoked straight away, along with any error from
+// scheduling the trailing invocation (for example Closed).
+func (th *Throttler) Call() (bool, error) {
+	now := th.tw.now
+	if !th.invoked || now-th.last >= th.interval {
+		th.invoke(now, th.tw.Now())
+		return true, nil
+	}
+	if !th.trailing || th.trailer != nil {
+		return false, nil
+	}
+	event := th.tw.newEvent(eventNode{at: th.last + th.interval})
+	event.fun = func(now *time.Time) {
+		th.trailer = nil
+		th.invoke(event.at, *now)
+	}
+	if err := th.tw.scheduleEvent(event); err != nil {
+		return false, err
+	}
+	th.trailer = event
+	return false, nil
+}
+
+func (th *Throttler) invoke(at int64, now time.Time) {
+	th.last = at
+	th.invoked = true
+	th.f(&now)
+}
+
+// Cancels any trailing invocation waiting to be invoked. Returns true
+// if there was one.
+func (th *Throttler) Cancel() bool {
+	if th.trailer == nil {
+		return false
+	}
+	th.tw.cancelEvent(th.trailer)
+	th.trailer = nil
+	return true
+}
@@ -0,0 +1,61 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottler(t *testing.T) {
+	for _, trailing := range []bool{false, true} {
+		start := time.Unix(0, 0)
+		tw := NewTimerWheel(start, time.Millisecond)
+		var invokedAt []time.Time
+		th := tw.NewThrottler(10*time.Millisecond, trailing, func(now *time.Time) { invokedAt = append(invokedAt, *now) })
+		// a call every 3ms for 20ms
+		immediate := 0
+		for idx := 0; idx < 7; idx++ {
+			invoked, err := th.Call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if invoked {
+				immediate++
+			}
+			tw.AdvanceBy(3*time.Millisecond, 0)
+		}
+		// The call at 0ms is invoked straight away, and those at 3, 6
+		// and 9ms are throttled. Without trailing, the call at 12ms is
+		// next to be invoked. With trailing, the throttled calls are
+		// invoked at 10ms, by the advance to 12ms, so the calls at 12,
+		// 15 and 18ms are throttled until 20ms, invoked by the advance
+		// to 21ms.
+		var expected []time.Duration
+		if trailing {
+			expected = []time.Duration{0, 12, 21}
+		} else {
+			expected = []time.Duration{0, 12}
+		}
+		tw.AdvanceTo(start.Add(30*time.Millisecond), 0)
+		if len(invokedAt) != len(expected) {
+			t.Fatalf("Trailing %v: expected invocations at %v ms, but got %v", trailing, expected, invokedAt)
+		}
+		for idx, at := range expected {
+			if !invokedAt[idx].Equal(start.Add(at * time.Millisecond)) {
+				t.Errorf("Trailing %v: expected invocations at %v ms, but got %v", trailing, expected, invokedAt)
+			}
+		}
+		if th.Cancel() {
+			t.Error("Expected no trailing invocation to be left")
+		}
+	}
+}
+
+func TestThrottlerNil(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a nil event to be refused")
+		}
+	}()
+	tw.NewThrottler(time.Millisecond, true, nil)
+}
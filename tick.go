@@ -0,0 +1,76 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Events for a TickWheel. The argument is the tick passed to Advance.
+type TickEvent func(tick uint64)
+
+// A TickWheel is a Timer Wheel keyed on abstract, monotonically
+// increasing ticks rather than on time.Time, for simulators and game
+// loops which have no use for wall-clock time. Ticks must be less
+// than 1<<63.
+type TickWheel struct {
+	tw *TimerWheel
+}
+
+// Creates a new TickWheel. The TickWheel considers the current tick
+// to be startAt. BucketSize is measured in ticks, and should be
+// chosen just as for NewTimerWheel. Options apply as for
+// NewTimerWheel; times they report (for example to an Observer) are
+// the ticks as nanoseconds since the Unix epoch.
+func NewTickWheel(startAt uint64, bucketSize uint64, options ...Option) *TickWheel {
+	return &TickWheel{tw: NewTimerWheel(tickTime(startAt), time.Duration(bucketSize), options...)}
+}
+
+func tickTime(tick uint64) time.Time {
+	return time.Unix(0, int64(tick))
+}
+
+// Returns the TickWheel's current tick.
+func (tkw *TickWheel) Now() uint64 {
+	return uint64(tkw.tw.now)
+}
+
+// Returns the number of scheduled events in the TickWheel.
+func (tkw *TickWheel) Length() int {
+	return tkw.tw.Length()
+}
+
+// O(1) test on TickWheel having scheduled events
+func (tkw *TickWheel) IsEmpty() bool {
+	return tkw.tw.IsEmpty()
+}
+
+// Returns the TickWheel's statistics. See TimerWheel.Stats.
+func (tkw *TickWheel) Stats() Stats {
+	return tkw.tw.Stats()
+}
+
+// Schedules an event to be invoked at the indicated tick. If that
+// tick is before the TickWheel's current tick then ScheduledInPast
+// is returned. See ScheduleEventAt.
+func (tkw *TickWheel) Schedule(tick uint64, e TickEvent) error {
+	return tkw.tw.ScheduleEventAt(tickTime(tick), func(now *time.Time) { e(uint64(now.UnixNano())) })
+}
+
+// Schedules an event to be invoked the indicated number of ticks
+// after the TickWheel's current tick.
+func (tkw *TickWheel) ScheduleIn(ticks uint64, e TickEvent) error {
+	return tkw.Schedule(tkw.Now()+ticks, e)
+}
+
+// Advances the TickWheel's current tick to the indicated tick,
+// invoking every event scheduled up to and including it. See
+// AdvanceTo for the semantics of limit and the returned value.
+func (tkw *TickWheel) Advance(tick uint64, limit int) int {
+	return tkw.tw.AdvanceTo(tickTime(tick), limit)
+}
+
+// Returns the tick of the earliest scheduled event. If there are no
+// scheduled events then false is returned.
+func (tkw *TickWheel) NextTick() (uint64, bool) {
+	at, found := tkw.tw.nextEventAt()
+	return uint64(tkw.tw.invokedAt(at)), found
+}
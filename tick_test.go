@@ -0,0 +1,44 @@
+package gotimerwheel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTickWheel(t *testing.T) {
+	tkw := NewTickWheel(100, 4)
+	var invoked []uint64
+	for _, tick := range []uint64{100, 103, 150, 5000} {
+		if err := tkw.Schedule(tick, func(now uint64) { invoked = append(invoked, now) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tkw.Schedule(99, func(uint64) {}); !errors.Is(err, ScheduledInPast) {
+		t.Errorf("Expected ScheduledInPast, but got %v", err)
+	}
+	if tick, found := tkw.NextTick(); !found || tick != 100 {
+		t.Errorf("Expected next tick 100, but got %v (%v)", tick, found)
+	}
+	if count := tkw.Advance(150, 0); count != 3 {
+		t.Errorf("Expected 3 events invoked, but got %v", count)
+	}
+	if tkw.Now() != 150 || tkw.Length() != 1 {
+		t.Errorf("Expected tick 150 with 1 event, but got %v with %v", tkw.Now(), tkw.Length())
+	}
+	if err := tkw.ScheduleIn(10, func(now uint64) { invoked = append(invoked, now) }); err != nil {
+		t.Fatal(err)
+	}
+	if count := tkw.Advance(10000, 0); count != 2 || !tkw.IsEmpty() {
+		t.Errorf("Expected 2 events invoked leaving none, but got %v leaving %v", count, tkw.Length())
+	}
+	expected := []uint64{150, 150, 150, 10000, 10000}
+	for idx, tick := range expected {
+		if invoked[idx] != tick {
+			t.Errorf("Expected invocations with %v, but got %v", expected, invoked)
+			break
+		}
+	}
+	if stats := tkw.Stats(); stats.Fired != 5 {
+		t.Errorf("Expected 5 fired, but got %v", stats.Fired)
+	}
+}
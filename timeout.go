@@ -0,0 +1,93 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// A TimeoutManager tracks an idle timeout for each of many
+// connections (or sessions, or leases), identified by ID: each
+// connection times out once its timeout has passed without a call to
+// Touch. Touching is the dominant operation, so it moves the
+// connection's event with ExtendDeadline rather than replacing it,
+// and allocates nothing. Like a Coalescer, a TimeoutManager loses
+// track of its events if they are removed from the Timer Wheel by
+// other means, such as Clear. See NewTimeoutManager.
+type TimeoutManager struct {
+	tw       *TimerWheel
+	timeouts map[string]*connTimeout
+}
+
+type connTimeout struct {
+	handle    *EventHandle
+	timeout   time.Duration
+	onTimeout func(id string, now time.Time)
+}
+
+// Creates a new TimeoutManager backed by the supplied Timer Wheel.
+func NewTimeoutManager(tw *TimerWheel) *TimeoutManager {
+	return &TimeoutManager{tw: tw, timeouts: make(map[string]*connTimeout)}
+}
+
+// Starts tracking the connection with the indicated ID, which times
+// out, calling onTimeout with its ID and the Timer Wheel's time, once
+// timeout has passed since the most recent call to Register or Touch
+// for it. Registering an ID which is already registered replaces its
+// timeout. Returns any error from scheduling the event (for example
+// Closed), in which case any existing timeout for the ID is left in
+// place. Timeout must not be negative.
+func (tm *TimeoutManager) Register(id string, timeout time.Duration, onTimeout func(id string, now time.Time)) error {
+	if timeout < 0 {
+		panic("TimeoutManager timeout must not be negative")
+	}
+	ct := &connTimeout{timeout: timeout, onTimeout: onTimeout}
+	handle, err := tm.tw.ScheduleHandleIn(timeout, func(now *time.Time) {
+		if tm.timeouts[id] == ct {
+			delete(tm.timeouts, id)
+		}
+		ct.onTimeout(id, *now)
+	})
+	if err != nil {
+		return err
+	}
+	tm.Remove(id)
+	ct.handle = handle
+	tm.timeouts[id] = ct
+	return nil
+}
+
+// Pushes the connection's timeout back to its timeout after the Timer
+// Wheel's current time. Returns false if the ID is not registered (or
+// has already timed out).
+func (tm *TimeoutManager) Touch(id string) bool {
+	ct, found := tm.timeouts[id]
+	if !found {
+		return false
+	}
+	return tm.tw.ExtendDeadline(ct.handle, tm.tw.after(ct.timeout)) == nil
+}
+
+// Stops tracking the connection, so that it never times out. Returns
+// true if the ID was registered and had not yet timed out.
+func (tm *TimeoutManager) Remove(id string) bool {
+	ct, found := tm.timeouts[id]
+	if !found {
+		return false
+	}
+	delete(tm.timeouts, id)
+	return ct.handle.Cancel()
+}
+
+// Returns the time at which the connection times out, unless it is
+// touched first. If the ID is not registered then false is returned.
+func (tm *TimeoutManager) Deadline(id string) (time.Time, bool) {
+	ct, found := tm.timeouts[id]
+	if !found {
+		return time.Time{}, false
+	}
+	return ct.handle.At(), true
+}
+
+// Returns the number of connections being tracked.
+func (tm *TimeoutManager) Length() int {
+	return len(tm.timeouts)
+}
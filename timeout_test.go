@@ -0,0 +1,70 @@
+package gotimerwheel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeoutManager(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	tm := NewTimeoutManager(tw)
+	var timedOut []string
+	onTimeout := func(id string, now time.Time) { timedOut = append(timedOut, fmt.Sprint(id, "@", now.Sub(start))) }
+	tm.Register("a", 10*time.Millisecond, onTimeout)
+	tm.Register("b", 10*time.Millisecond, onTimeout)
+	tm.Register("c", 10*time.Millisecond, onTimeout)
+	// re-registering replaces the timeout
+	tm.Register("c", 30*time.Millisecond, onTimeout)
+	if tm.Length() != 3 || tw.Length() != 3 {
+		t.Errorf("Expected 3 connections, but got %v", tm.Length())
+	}
+	tw.AdvanceBy(8*time.Millisecond, 0)
+	if !tm.Touch("a") || tm.Touch("nope") {
+		t.Error("Expected only registered connections to be touched")
+	}
+	if at, _ := tm.Deadline("a"); !at.Equal(start.Add(18 * time.Millisecond)) {
+		t.Errorf("Expected a's deadline to be 18ms, but got %v", at)
+	}
+	tw.AdvanceBy(2*time.Millisecond, 0)
+	if len(timedOut) != 1 || timedOut[0] != "b@10ms" || tm.Length() != 2 {
+		t.Errorf("Expected b to time out, but got %v", timedOut)
+	}
+	if tm.Touch("b") || tm.Remove("b") {
+		t.Error("Expected b to be forgotten once timed out")
+	}
+	if !tm.Remove("a") || tm.Length() != 1 {
+		t.Error("Expected a to be removed")
+	}
+	tw.AdvanceBy(time.Second, 0)
+	if len(timedOut) != 2 || timedOut[1] != "c@1.01s" {
+		t.Errorf("Expected c to time out alone, but got %v", timedOut)
+	}
+	if tm.Length() != 0 || !tw.IsEmpty() {
+		t.Errorf("Expected nothing left, but got %v", tm.Length())
+	}
+	if _, found := tm.Deadline("c"); found {
+		t.Error("Expected no deadline for c")
+	}
+}
+
+func BenchmarkTimeoutManagerTouch(b *testing.B) {
+	const connections = 100000
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	tm := NewTimeoutManager(tw)
+	ids := make([]string, connections)
+	for idx := range ids {
+		ids[idx] = fmt.Sprint(idx)
+		tm.Register(ids[idx], time.Minute, func(string, time.Time) {})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if n%connections == 0 {
+			tw.AdvanceBy(time.Millisecond, 0)
+		}
+		tm.Touch(ids[n%connections])
+	}
+}
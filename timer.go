@@ -0,0 +1,94 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+// Returns a channel on which the Timer Wheel's time is sent once the
+// Timer Wheel is advanced to at least d after its current time, like
+// time.After. The channel is buffered, so the event never blocks
+// whoever advances the Timer Wheel. If d is not positive, the time is
+// sent on the next advance. If the Timer Wheel refuses the event, for
+// example with Closed or WheelFull, the channel is closed instead, so
+// that a receive from it returns the zero Time straight away rather
+// than blocking for ever.
+func (tw *TimerWheel) After(d time.Duration) <-chan time.Time {
+	c := make(chan time.Time, 1)
+	if d < 0 {
+		d = 0
+	}
+	if tw.ScheduleEventAt(tw.Now().Add(d), func(now *time.Time) { c <- *now }) != nil {
+		close(c)
+	}
+	return c
+}
+
+// A Timer is a single event which can be stopped and reset, like a
+// *time.Timer. See AfterFunc. Unlike a *time.Timer, it is not safe
+// for concurrent use: use Intake.Do to stop or reset it from a
+// goroutine other than the one driving the Timer Wheel.
+type Timer struct {
+	tw    *TimerWheel
+	f     func()
+	event *eventNode
+	// The Timer Wheel's count of Clears when the event was scheduled.
+	clears uint64
+	// Why the Timer Wheel refused the event, if it did.
+	err error
+}
+
+// Arranges for f to be called once the Timer Wheel is advanced to at
+// least d after its current time, like time.AfterFunc. Unlike
+// time.AfterFunc, f is called synchronously by whichever call
+// advances the Timer Wheel, not in its own goroutine. The returned
+// Timer can be used to stop or reset the call. If the Timer Wheel
+// refuses the call, for example with Closed or WheelFull, the Timer is
+// returned stopped, and its Err method says why.
+func (tw *TimerWheel) AfterFunc(d time.Duration, f func()) *Timer {
+	t := &Timer{tw: tw, f: f}
+	t.schedule(d)
+	return t
+}
+
+func (t *Timer) schedule(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	event := t.tw.newEvent(eventNode{at: t.tw.now + int64(d), fun: func(*time.Time) {
+		t.event = nil
+		t.f()
+	}})
+	t.err = t.tw.scheduleEvent(event)
+	if t.err == nil {
+		t.event, t.clears = event, t.tw.clears
+	}
+}
+
+// Returns the error with which the Timer Wheel refused the Timer when
+// it was last armed, by AfterFunc or Reset, or nil if it was armed.
+func (t *Timer) Err() error {
+	return t.err
+}
+
+// Prevents the Timer from firing. Returns true if this stopped the
+// Timer, or false if it had already fired or been stopped, or been
+// removed by Clear, Reset or Close.
+func (t *Timer) Stop() bool {
+	if t.event == nil || t.clears != t.tw.clears {
+		t.event = nil
+		return false
+	}
+	t.tw.cancelEvent(t.event)
+	t.event = nil
+	return true
+}
+
+// Changes the Timer to fire d after the Timer Wheel's current time,
+// whether or not it has already fired or been stopped. Returns true
+// if the Timer had been active. If the Timer Wheel refuses the Timer,
+// it is left stopped, and Err says why.
+func (t *Timer) Reset(d time.Duration) bool {
+	active := t.Stop()
+	t.schedule(d)
+	return active
+}
@@ -0,0 +1,84 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAfter(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	c := tw.After(10 * time.Millisecond)
+	immediate := tw.After(-time.Second)
+	tw.AdvanceBy(5*time.Millisecond, 0)
+	select {
+	case <-c:
+		t.Error("Expected nothing to be sent yet")
+	case now := <-immediate:
+		if !now.Equal(start.Add(5 * time.Millisecond)) {
+			t.Errorf("Expected %v, but got %v", start.Add(5*time.Millisecond), now)
+		}
+	}
+	tw.AdvanceBy(10*time.Millisecond, 0)
+	select {
+	case now := <-c:
+		if !now.Equal(start.Add(15 * time.Millisecond)) {
+			t.Errorf("Expected %v, but got %v", start.Add(15*time.Millisecond), now)
+		}
+	default:
+		t.Error("Expected the time to have been sent")
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	fired := 0
+	timer := tw.AfterFunc(10*time.Millisecond, func() { fired++ })
+	if !timer.Reset(20 * time.Millisecond) {
+		t.Error("Expected Reset of an active Timer to return true")
+	}
+	tw.AdvanceBy(15*time.Millisecond, 0)
+	if fired != 0 || tw.Length() != 1 {
+		t.Errorf("Expected the Timer to have moved, but got %v fired and %v scheduled", fired, tw.Length())
+	}
+	tw.AdvanceBy(5*time.Millisecond, 0)
+	if fired != 1 {
+		t.Errorf("Expected the Timer to have fired once, but got %v", fired)
+	}
+	if timer.Stop() {
+		t.Error("Expected Stop of a fired Timer to return false")
+	}
+	if timer.Reset(time.Millisecond) {
+		t.Error("Expected Reset of a fired Timer to return false")
+	}
+	if !timer.Stop() || timer.Stop() {
+		t.Error("Expected Stop to return true just once")
+	}
+	tw.AdvanceBy(time.Second, 0)
+	if fired != 1 || !tw.IsEmpty() {
+		t.Errorf("Expected the stopped Timer not to fire, but got %v", fired)
+	}
+	if stats := tw.Stats(); stats.Cancelled != 2 || stats.Fired != 1 {
+		t.Errorf("Expected 2 cancelled and 1 fired, but got %+v", stats)
+	}
+}
+
+func TestAfterClosed(t *testing.T) {
+	tw := NewTimerWheel(time.Unix(0, 0), time.Millisecond)
+	timer := tw.AfterFunc(time.Second, func() { t.Error("Refused Timer fired") })
+	tw.Close(CloseDrop)
+	select {
+	case now, ok := <-tw.After(time.Millisecond):
+		if ok || !now.IsZero() {
+			t.Errorf("Expected the channel to be closed, but got %v", now)
+		}
+	default:
+		t.Error("Expected a receive from a refused After not to block")
+	}
+	if refused := tw.AfterFunc(time.Second, func() {}); refused.Err() != Closed || refused.Stop() {
+		t.Errorf("Expected a stopped Timer and Closed, but got %v", refused.Err())
+	}
+	if timer.Err() != nil || timer.Reset(time.Second) || timer.Err() != Closed || timer.Stop() {
+		t.Errorf("Expected Reset to leave the Timer stopped with Closed, but got %v", timer.Err())
+	}
+}
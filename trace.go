@@ -0,0 +1,70 @@
+package gotimerwheel
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Starts the Timer Wheel with tracing on. See SetTracing.
+func WithTracing() Option {
+	return func(tw *TimerWheel) {
+		tw.tracing = 1
+	}
+}
+
+// Turns per-event tracing on or off. While it is on, a compact line
+// is sent to the Logger (see WithLogger) for every event scheduled,
+// invoked, expired or cancelled, such as
+//
+//	gotimerwheel: fire t=2021-01-02T03:04:05Z id=7 tag="timeout" lag=1ms
+//
+// giving the time the event is scheduled for and, where the event has
+// them, its EventID, tag and key. Cancel lines give a reason: cancel,
+// replace (by scheduling the same key again), evict (see
+// WithEviction) or clear, though Clear, Reset and Close log a single
+// line with the number of events removed rather than one per event.
+// Unlike every other method, SetTracing may be called from any
+// goroutine, so tracing can be turned on for a burst of debugging in
+// a running process. Checking whether tracing is on costs an atomic
+// load, so it is cheap to leave compiled in but off.
+func (tw *TimerWheel) SetTracing(on bool) {
+	var tracing uint32
+	if on {
+		tracing = 1
+	}
+	atomic.StoreUint32(&tw.tracing, tracing)
+}
+
+func (tw *TimerWheel) tracingOn() bool {
+	return tw.logger != nil && atomic.LoadUint32(&tw.tracing) == 1
+}
+
+// Logs a trace line for the event, ending with field=value if field
+// is not empty. Must only be called if tracingOn.
+func (tw *TimerWheel) trace(op string, event *eventNode, field, value string) {
+	line := make([]byte, 0, 96)
+	line = append(line, "gotimerwheel: "...)
+	line = append(line, op...)
+	line = append(line, " t="...)
+	line = tw.toTime(event.at).AppendFormat(line, time.RFC3339Nano)
+	if event.id != 0 {
+		line = append(line, " id="...)
+		line = strconv.AppendUint(line, uint64(event.id), 10)
+	}
+	if event.tag != "" {
+		line = append(line, " tag="...)
+		line = strconv.AppendQuote(line, event.tag)
+	}
+	if event.keyed {
+		line = append(line, " key="...)
+		line = strconv.AppendQuote(line, event.key)
+	}
+	if field != "" {
+		line = append(line, ' ')
+		line = append(line, field...)
+		line = append(line, '=')
+		line = append(line, value...)
+	}
+	tw.logger.Printf("%s", line)
+}
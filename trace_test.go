@@ -0,0 +1,64 @@
+package gotimerwheel
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTracing(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	rl := &recordingLogger{}
+	tw := NewTimerWheel(start, time.Millisecond, WithLogger(rl), WithCapacity(3), WithEviction(nil))
+	nop := func(*time.Time) {}
+	tw.ScheduleEventIn(time.Millisecond, nop)
+	if len(rl.lines) != 0 {
+		t.Fatalf("Expected nothing traced while tracing is off, but got %v", rl.lines)
+	}
+	tw.SetTracing(true)
+	id, _ := tw.ScheduleIDIn(2*time.Millisecond, nop)
+	tw.ScheduleTaggedEventAt("timeout", start.Add(time.Hour), nop)
+	tw.ScheduleKeyedEventIn("key", time.Minute, nop)
+	tw.ScheduleKeyedEventIn("key", time.Minute, nop)
+	tw.CancelID(id)
+	tw.AdvanceBy(3*time.Millisecond, 0)
+	tw.Clear()
+	tw.SetTracing(false)
+	tw.ScheduleEventIn(time.Millisecond, nop)
+	expected := []string{
+		`gotimerwheel: schedule t=1970-01-01T00:00:00.002Z id=1`,
+		`gotimerwheel: schedule t=1970-01-01T01:00:00Z tag="timeout"`,
+		`gotimerwheel: cancel t=1970-01-01T01:00:00Z tag="timeout" reason=evict`,
+		`gotimerwheel: schedule t=1970-01-01T00:01:00Z key="key"`,
+		`gotimerwheel: cancel t=1970-01-01T00:01:00Z key="key" reason=replace`,
+		`gotimerwheel: schedule t=1970-01-01T00:01:00Z key="key"`,
+		`gotimerwheel: cancel t=1970-01-01T00:00:00.002Z id=1 reason=cancel`,
+		`gotimerwheel: fire t=1970-01-01T00:00:00.001Z lag=2ms`,
+		`gotimerwheel: cancel n=1 reason=clear`,
+	}
+	var traced []string
+	for _, line := range rl.lines {
+		if !strings.Contains(line, "created level") {
+			traced = append(traced, line)
+		}
+	}
+	if len(traced) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, traced)
+	}
+	for idx, line := range expected {
+		if traced[idx] != line {
+			t.Errorf("Expected %q, but got %q", line, traced[idx])
+		}
+	}
+}
+
+func TestTracingExpired(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	rl := &recordingLogger{}
+	tw := NewTimerWheel(start, time.Millisecond, WithLogger(rl), WithTracing(), WithMaxLateness(0, nil))
+	tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+	tw.AdvanceBy(time.Second, 0)
+	if len(rl.lines) != 2 || rl.lines[1] != "gotimerwheel: expire t=1970-01-01T00:00:00.001Z lag=999ms" {
+		t.Errorf("Expected the event to be traced as expired, but got %v", rl.lines)
+	}
+}
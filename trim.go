@@ -0,0 +1,98 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type idleTrim struct {
+	// 0 unless WithIdleTrim was given.
+	after int64
+	// Set once an advance has found the Timer Wheel empty, at since,
+	// when Stats.Scheduled was scheduled.
+	idle      bool
+	since     int64
+	scheduled uint64
+	trimmed   bool
+}
+
+// Calls Trim once the Timer Wheel has sat empty, with nothing
+// scheduled, for at least after of its own time, as seen by the
+// advances which find it empty. This suits processes holding many
+// mostly-idle Timer Wheels, such as one per tenant, which would
+// otherwise each hold on to the storage of their busiest moment. The
+// Timer Wheel is trimmed once per idle period, and storage grows back
+// as events are scheduled again. After must be greater than 0.
+func WithIdleTrim(after time.Duration) Option {
+	if after <= 0 {
+		panic("TimerWheel idle trim period must be greater than 0")
+	}
+	return func(tw *TimerWheel) {
+		tw.idleTrim.after = int64(after)
+	}
+}
+
+// Releases storage which the Timer Wheel grew to hold events it no
+// longer holds: the slices of empty buckets (see WithSliceBuckets),
+// the overflow heap (see WithOverflowHeap) if it is empty, and the
+// maps indexing events by key, by EventID and by tag and time (see
+// WithDuplicateDetection), which never shrink by themselves. The maps
+// are copied if they still hold events, so this is O(n) in the number
+// of events scheduled. The rings of the hierarchy are kept, and
+// everything released is allocated again as it is needed.
+func (tw *TimerWheel) Trim() {
+	for level := tw; level != nil; level = level.next {
+		for idx := range level.ring {
+			if b := &level.ring[idx]; b.sliced && b.count == 0 {
+				b.entries, b.head = nil, 0
+			}
+		}
+	}
+	if len(tw.overflow.events) == 0 {
+		tw.overflow.events = nil
+	}
+	if len(tw.keys) == 0 {
+		tw.keys = nil
+	} else {
+		keys := make(map[string]*eventNode, len(tw.keys))
+		for key, event := range tw.keys {
+			keys[key] = event
+		}
+		tw.keys = keys
+	}
+	if len(tw.ids) == 0 {
+		tw.ids = nil
+	} else {
+		ids := make(map[EventID]*eventNode, len(tw.ids))
+		for id, event := range tw.ids {
+			ids[id] = event
+		}
+		tw.ids = ids
+	}
+	if tw.duplicates.enabled {
+		events := make(map[duplicateKey]*eventNode, len(tw.duplicates.events))
+		for key, event := range tw.duplicates.events {
+			events[key] = event
+		}
+		tw.duplicates.events = events
+	}
+}
+
+// Called at the end of every advance.
+func (tw *TimerWheel) trimIfIdle() {
+	t := &tw.idleTrim
+	if t.after == 0 {
+		return
+	}
+	if !tw.IsEmpty() {
+		t.idle = false
+		return
+	}
+	if !t.idle || t.scheduled != tw.stats.scheduled {
+		t.idle, t.trimmed, t.since, t.scheduled = true, false, tw.now, tw.stats.scheduled
+		return
+	}
+	if !t.trimmed && tw.now-t.since >= t.after {
+		tw.Trim()
+		t.trimmed = true
+	}
+}
@@ -0,0 +1,78 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrim(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithSliceBuckets(), WithDuplicateDetection(DuplicateCount))
+	nop := func(*time.Time) {}
+	for idx := 0; idx < 1000; idx++ {
+		tw.ScheduleTaggedEventAt("tag", start.Add(time.Duration(idx)), nop)
+	}
+	tw.ScheduleKeyedEventIn("key", time.Hour, nop)
+	id, _ := tw.ScheduleIDIn(time.Hour, nop)
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.Trim()
+	if b := tw.ring[0]; b.count != 0 || cap(b.entries) != 0 {
+		t.Errorf("Expected the emptied bucket's slice to be released, but it has capacity %v", cap(b.entries))
+	}
+	if len(tw.duplicates.events) != 0 || tw.Length() != 2 {
+		t.Errorf("Expected only the keyed and identified events to be left, but got %v", tw.Length())
+	}
+	if !tw.CancelKey("key") || !tw.CancelID(id) {
+		t.Error("Expected the events to be found after trimming")
+	}
+	if err := tw.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	tw.Trim()
+	if tw.keys != nil || tw.ids != nil {
+		t.Error("Expected the empty maps to be released")
+	}
+	if err := tw.ScheduleKeyedEventIn("key", time.Millisecond, nop); err != nil || tw.AdvanceBy(time.Millisecond, 0) != 1 {
+		t.Errorf("Expected to schedule again after trimming, but got %v", err)
+	}
+}
+
+func TestIdleTrim(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond, WithSliceBuckets(), WithIdleTrim(time.Second))
+	fill := func() {
+		for idx := 0; idx < 100; idx++ {
+			tw.ScheduleEventIn(time.Millisecond, func(*time.Time) {})
+		}
+	}
+	trimmed := func() bool {
+		for _, b := range tw.ring {
+			if cap(b.entries) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	fill()
+	tw.AdvanceBy(time.Millisecond, 0)
+	tw.AdvanceBy(500*time.Millisecond, 0)
+	if trimmed() {
+		t.Fatal("Expected nothing trimmed before the Timer Wheel has been idle for long enough")
+	}
+	// Events scheduled and cancelled between advances restart the
+	// idle period.
+	h, _ := tw.ScheduleHandleIn(time.Millisecond, func(*time.Time) {})
+	h.Cancel()
+	tw.AdvanceBy(600*time.Millisecond, 0)
+	if trimmed() {
+		t.Fatal("Expected scheduling to restart the idle period")
+	}
+	tw.AdvanceBy(time.Second, 0)
+	if !trimmed() {
+		t.Fatal("Expected the idle Timer Wheel to be trimmed")
+	}
+	fill()
+	if tw.AdvanceBy(time.Millisecond, 0) != 100 {
+		t.Error("Expected storage to grow back on demand")
+	}
+}
@@ -0,0 +1,33 @@
+package gotimerwheel
+
+import (
+	"time"
+)
+
+type eventTTL struct {
+	ttl       int64
+	onExpired Event
+}
+
+// Schedules an event to be invoked at the indicated time, but only
+// while it is still valid: if, when the event is due, the time being
+// advanced to is more than ttl after at (for example because the
+// advance is working through a backlog in batches with a limit), then
+// onExpired (if non-nil) is invoked instead of e, and the event is
+// counted as Expired rather than Fired in Stats. This is a
+// per-event WithMaxLateness, and takes precedence over it. Ttl must
+// not be negative. Otherwise, this is just the same as
+// ScheduleEventAt.
+func (tw *TimerWheel) ScheduleTTLEventAt(at time.Time, ttl time.Duration, e Event, onExpired Event) error {
+	if ttl < 0 {
+		panic("TimerWheel event ttl must not be negative")
+	}
+	return tw.scheduleEvent(tw.newEvent(eventNode{at: at.UnixNano(), fun: e, ttl: &eventTTL{ttl: int64(ttl), onExpired: onExpired}}))
+}
+
+// Schedules an event with a validity window to be invoked at the
+// current Timer Wheel's time plus the supplied duration. See
+// ScheduleTTLEventAt.
+func (tw *TimerWheel) ScheduleTTLEventIn(in time.Duration, ttl time.Duration, e Event, onExpired Event) error {
+	return tw.ScheduleTTLEventAt(tw.after(in), ttl, e, onExpired)
+}
@@ -0,0 +1,34 @@
+package gotimerwheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLEvent(t *testing.T) {
+	start := time.Unix(0, 0)
+	// the wheel-wide bound is looser, and overridden
+	tw := NewTimerWheel(start, time.Millisecond, WithMaxLateness(time.Hour, nil))
+	var invoked, expired []int
+	for idx := 1; idx <= 5; idx++ {
+		idx := idx
+		tw.ScheduleTTLEventAt(start.Add(time.Duration(idx)*time.Millisecond), 2*time.Millisecond,
+			func(*time.Time) { invoked = append(invoked, idx) },
+			func(*time.Time) { expired = append(expired, idx) })
+	}
+	tw.ScheduleTTLEventIn(time.Millisecond, 0, func(*time.Time) { invoked = append(invoked, 0) }, nil)
+	// a backlog: by the time the events are due, it's 5ms, so those
+	// at 1ms and 2ms are stale
+	if count := tw.AdvanceTo(start.Add(5*time.Millisecond), 0); count != 6 {
+		t.Errorf("Expected 6 events, but got %v", count)
+	}
+	if len(expired) != 2 || expired[0] != 1 || expired[1] != 2 {
+		t.Errorf("Expected events 1 and 2 to expire, but got %v", expired)
+	}
+	if len(invoked) != 3 || invoked[0] != 3 {
+		t.Errorf("Expected events 3 to 5 invoked, but got %v", invoked)
+	}
+	if stats := tw.Stats(); stats.Fired != 3 || stats.Expired != 3 {
+		t.Errorf("Expected 3 fired and 3 expired, but got %+v", stats)
+	}
+}
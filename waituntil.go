@@ -0,0 +1,106 @@
+package gotimerwheel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Goroutines blocked in WaitUntil. The goroutine driving the Timer
+// Wheel publishes its current time at the end of every advance, and
+// only takes the lock if someone is waiting.
+type timeWaiters struct {
+	now   int64
+	count int32
+	lock  sync.Mutex
+	// Unsorted: there are rarely more than a handful.
+	waiting []*timeWaiter
+}
+
+type timeWaiter struct {
+	at      int64
+	reached chan struct{}
+}
+
+func newTimeWaiters(now int64) *timeWaiters {
+	return &timeWaiters{now: now}
+}
+
+// Blocks until the Timer Wheel's current time reaches t, or until ctx
+// is done, in which case ctx.Err() is returned. Unlike every other
+// TimerWheel method, WaitUntil is safe to call from goroutines other
+// than the one which drives the Timer Wheel: it is the driving
+// goroutine which wakes waiters, at the end of each call to AdvanceTo
+// (or AdvanceBy and so on, and Rebase), so a waiter is woken once all
+// the events due by t have been invoked. If the Timer Wheel has
+// already reached t then WaitUntil returns straight away; otherwise,
+// calling it from the driving goroutine blocks until ctx is done.
+func (tw *TimerWheel) WaitUntil(ctx context.Context, t time.Time) error {
+	w := tw.root.waiters
+	waiter := &timeWaiter{at: t.UnixNano(), reached: make(chan struct{})}
+	w.lock.Lock()
+	atomic.AddInt32(&w.count, 1)
+	// Checked after count is raised, so either this sees the time of
+	// an advance, or that advance sees the waiter.
+	if atomic.LoadInt64(&w.now) >= waiter.at {
+		atomic.AddInt32(&w.count, -1)
+		w.lock.Unlock()
+		return nil
+	}
+	w.waiting = append(w.waiting, waiter)
+	w.lock.Unlock()
+
+	select {
+	case <-waiter.reached:
+		return nil
+	case <-ctx.Done():
+		if w.forget(waiter) {
+			return ctx.Err()
+		}
+		// reached at the same moment
+		return nil
+	}
+}
+
+// Records the Timer Wheel's current time, waking every waiter it has
+// reached.
+func (w *timeWaiters) reach(now int64) {
+	atomic.StoreInt64(&w.now, now)
+	if atomic.LoadInt32(&w.count) == 0 {
+		return
+	}
+	w.lock.Lock()
+	waiting := w.waiting[:0]
+	for _, waiter := range w.waiting {
+		if waiter.at <= now {
+			close(waiter.reached)
+			atomic.AddInt32(&w.count, -1)
+		} else {
+			waiting = append(waiting, waiter)
+		}
+	}
+	for idx := len(waiting); idx < len(w.waiting); idx++ {
+		w.waiting[idx] = nil
+	}
+	w.waiting = waiting
+	w.lock.Unlock()
+}
+
+// Removes a waiter whose context is done. Returns false if it had
+// already been woken.
+func (w *timeWaiters) forget(waiter *timeWaiter) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for idx, other := range w.waiting {
+		if other == waiter {
+			last := len(w.waiting) - 1
+			w.waiting[idx] = w.waiting[last]
+			w.waiting[last] = nil
+			w.waiting = w.waiting[:last]
+			atomic.AddInt32(&w.count, -1)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package gotimerwheel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitUntil(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	// already reached
+	if err := tw.WaitUntil(context.Background(), start); err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := tw.WaitUntil(ctx, start.Add(time.Hour)); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, but got %v", err)
+	}
+	if len(tw.waiters.waiting) != 0 || tw.waiters.count != 0 {
+		t.Errorf("Expected the waiter to be forgotten, but got %v", len(tw.waiters.waiting))
+	}
+
+	var lock sync.Mutex
+	invoked := 0
+	for idx := 1; idx <= 10; idx++ {
+		tw.ScheduleEventAt(start.Add(time.Duration(idx)*time.Millisecond), func(*time.Time) {
+			lock.Lock()
+			invoked++
+			lock.Unlock()
+		})
+	}
+	var wg sync.WaitGroup
+	for idx := 1; idx <= 10; idx++ {
+		idx := idx
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tw.WaitUntil(context.Background(), start.Add(time.Duration(idx)*time.Millisecond)); err != nil {
+				t.Errorf("Expected no error, but got %v", err)
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if invoked < idx {
+				t.Errorf("Expected at least %v events invoked before waking, but got %v", idx, invoked)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			tw.AdvanceBy(time.Millisecond, 0)
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestWaitUntilRebase(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	woken := make(chan error)
+	go func() {
+		woken <- tw.WaitUntil(context.Background(), start.Add(time.Minute))
+	}()
+	for atomic.LoadInt32(&tw.waiters.count) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	tw.Rebase(time.Minute)
+	if err := <-woken; err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+	// clones have waiters of their own
+	clone := tw.Clone()
+	if clone.waiters == tw.waiters {
+		t.Error("Expected the clone to have its own waiters")
+	}
+	if err := clone.WaitUntil(context.Background(), start.Add(time.Minute)); err != nil {
+		t.Errorf("Expected the clone to have reached its time, but got %v", err)
+	}
+}
+
+func TestWaitUntilWithinBucket(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Second)
+	woken := make(chan error)
+	go func() {
+		woken <- tw.WaitUntil(context.Background(), start.Add(time.Millisecond))
+	}()
+	for atomic.LoadInt32(&tw.waiters.count) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// still within the first bucket
+	tw.AdvanceBy(time.Millisecond, 0)
+	if err := <-woken; err != nil {
+		t.Errorf("Expected no error, but got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package gotimerwheel
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	UnknownHandler = errors.New("No handler is registered under that name")
+)
+
+// Builds the Event for a named callback from the payload it was
+// scheduled with.
+type Handler func(payload []byte) Event
+
+// Handlers map names to the Handlers which build their events, so
+// that a schedule written out by one process can be rebuilt by
+// another.
+type Handlers map[string]Handler
+
+func (h Handlers) event(name string, payload []byte) (Event, error) {
+	handler, found := h[name]
+	if !found {
+		return nil, UnknownHandler
+	}
+	return handler(payload), nil
+}
+
+// A WAL makes the events scheduled through it survive a crash of the
+// process, by appending a record to a write-ahead log as each event
+// is scheduled, cancelled and invoked. Events are given by the name
+// of a Handler and a payload rather than as an Event, so that on
+// restart Replay can read the log back and rebuild the pending
+// schedule. See NewWAL.
+type WAL struct {
+	tw       *TimerWheel
+	enc      *json.Encoder
+	handlers Handlers
+	pending  map[uint64]*eventNode
+	nextID   uint64
+	err      error
+}
+
+type walRecord struct {
+	Op      string `json:"op"`
+	ID      uint64 `json:"id"`
+	At      int64  `json:"at,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Payload []byte `json:"payload,omitempty"`
+}
+
+const (
+	walSchedule = "schedule"
+	walCancel   = "cancel"
+	walFire     = "fire"
+)
+
+// Creates a WAL which schedules events in tw, resolving their names
+// through handlers, and appends its records, one JSON object per
+// line, to w. For the log to be durable, w should sync each write
+// (for example a file opened with os.O_SYNC). After a restart, create
+// the WAL afresh and Replay the old log before scheduling anything
+// else.
+func NewWAL(tw *TimerWheel, w io.Writer, handlers Handlers) *WAL {
+	return &WAL{
+		tw:       tw,
+		enc:      json.NewEncoder(w),
+		handlers: handlers,
+		pending:  make(map[uint64]*eventNode),
+		nextID:   1,
+	}
+}
+
+// Schedules the event built by the named Handler from payload to be
+// invoked at the indicated time, and logs it. Returns the event's ID,
+// with which it may be cancelled. Returns UnknownHandler if there is
+// no such Handler, any error from scheduling the event (for example
+// ScheduledInPast), or any error writing the log, in which case the
+// event is not scheduled.
+func (wal *WAL) ScheduleEventAt(at time.Time, name string, payload []byte) (uint64, error) {
+	id := wal.nextID
+	if err := wal.schedule(id, at.UnixNano(), name, payload); err != nil {
+		return 0, err
+	}
+	wal.nextID++
+	return id, nil
+}
+
+// Schedules the event built by the named Handler from payload to be
+// invoked at the Timer Wheel's current time plus the supplied
+// duration. See ScheduleEventAt.
+func (wal *WAL) ScheduleEventIn(in time.Duration, name string, payload []byte) (uint64, error) {
+	return wal.ScheduleEventAt(wal.tw.after(in), name, payload)
+}
+
+func (wal *WAL) schedule(id uint64, at int64, name string, payload []byte) error {
+	if wal.err != nil {
+		return wal.err
+	}
+	e, err := wal.handlers.event(name, payload)
+	if err != nil {
+		return err
+	}
+	event := wal.tw.newEvent(eventNode{at: at})
+	event.fun = func(now *time.Time) {
+		delete(wal.pending, id)
+		e(now)
+		wal.write(walRecord{Op: walFire, ID: id})
+	}
+	if err := wal.tw.scheduleEvent(event); err != nil {
+		return err
+	}
+	if err := wal.write(walRecord{Op: walSchedule, ID: id, At: at, Name: name, Payload: payload}); err != nil {
+		wal.tw.cancelEvent(event)
+		return err
+	}
+	wal.pending[id] = event
+	return nil
+}
+
+// Cancels the event with the indicated ID, and logs it. Returns true
+// if the event was waiting to be invoked, along with any error
+// writing the log. If the log cannot be written, the event is
+// cancelled all the same, but would be rescheduled by Replay.
+func (wal *WAL) Cancel(id uint64) (bool, error) {
+	event, found := wal.pending[id]
+	if !found {
+		return false, nil
+	}
+	delete(wal.pending, id)
+	if !wal.tw.cancelEvent(event) {
+		return false, nil
+	}
+	return true, wal.write(walRecord{Op: walCancel, ID: id})
+}
+
+// Returns the number of events scheduled through the WAL which are
+// still waiting to be invoked.
+func (wal *WAL) Length() int {
+	return len(wal.pending)
+}
+
+// Returns the first error writing the log. Once the log cannot be
+// written, every attempt to schedule through the WAL fails with the
+// same error, as does Replay.
+func (wal *WAL) Err() error {
+	return wal.err
+}
+
+func (wal *WAL) write(record walRecord) error {
+	if wal.err != nil {
+		return wal.err
+	}
+	wal.err = wal.enc.Encode(&record)
+	return wal.err
+}
+
+// Reads a log written by a WAL (typically before a crash) and
+// schedules again every event which it records as scheduled but
+// neither cancelled nor invoked, writing them to this WAL's log, so
+// the new log is complete without the old one. Events whose time has
+// passed are scheduled for the Timer Wheel's current time, so they
+// are invoked by its next advance. Because an event is logged as
+// invoked only once it returns, an event which was running at the
+// time of the crash is invoked again. A final record torn by the
+// crash is ignored. Returns the number of events scheduled, and
+// UnknownHandler if a recorded name has no Handler, in which case
+// nothing is scheduled.
+func (wal *WAL) Replay(r io.Reader) (int, error) {
+	records := make(map[uint64]walRecord)
+	var order []uint64
+	dec := json.NewDecoder(r)
+	for {
+		var record walRecord
+		err := dec.Decode(&record)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		if record.ID >= wal.nextID {
+			wal.nextID = record.ID + 1
+		}
+		switch record.Op {
+		case walSchedule:
+			if _, found := records[record.ID]; !found {
+				order = append(order, record.ID)
+			}
+			records[record.ID] = record
+		case walCancel, walFire:
+			delete(records, record.ID)
+		}
+	}
+	for _, id := range order {
+		if record, found := records[id]; found {
+			if _, found := wal.handlers[record.Name]; !found {
+				return 0, UnknownHandler
+			}
+		}
+	}
+	count := 0
+	for _, id := range order {
+		record, found := records[id]
+		if !found {
+			continue
+		}
+		// An ID cancelled and then scheduled again is in order twice.
+		delete(records, id)
+		at := record.At
+		if at < wal.tw.now {
+			at = wal.tw.now
+		}
+		if err := wal.schedule(id, at, record.Name, record.Payload); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,91 @@
+package gotimerwheel
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWAL(t *testing.T) {
+	start := time.Unix(0, 0)
+	var invoked []string
+	handlers := Handlers{
+		"send": func(payload []byte) Event {
+			return func(*time.Time) { invoked = append(invoked, string(payload)) }
+		},
+	}
+	log := &bytes.Buffer{}
+	tw := NewTimerWheel(start, time.Millisecond)
+	wal := NewWAL(tw, log, handlers)
+	if _, err := wal.ScheduleEventIn(time.Millisecond, "nope", nil); err != UnknownHandler {
+		t.Errorf("Expected UnknownHandler, but got %v", err)
+	}
+	first, _ := wal.ScheduleEventIn(time.Millisecond, "send", []byte("first"))
+	second, _ := wal.ScheduleEventIn(2*time.Millisecond, "send", []byte("second"))
+	wal.ScheduleEventIn(3*time.Millisecond, "send", []byte("third"))
+	wal.ScheduleEventIn(time.Hour, "send", []byte("fourth"))
+	if first == second || wal.Length() != 4 || tw.Length() != 4 {
+		t.Errorf("Expected 4 events with distinct IDs, but got %v", wal.Length())
+	}
+	if cancelled, err := wal.Cancel(second); !cancelled || err != nil {
+		t.Errorf("Expected the second event to be cancelled, but got %v, %v", cancelled, err)
+	}
+	if cancelled, _ := wal.Cancel(second); cancelled {
+		t.Error("Expected the second event to be cancelled only once")
+	}
+	tw.AdvanceBy(2*time.Millisecond, 0)
+	if len(invoked) != 1 || invoked[0] != "first" {
+		t.Errorf("Expected the first event invoked, but got %v", invoked)
+	}
+	// a crash tears the record being written
+	log.WriteString(`{"op":"fire","id":3`)
+
+	// the restarted process rebuilds the third and fourth events, the
+	// third now overdue
+	invoked = nil
+	restarted := NewTimerWheel(start.Add(time.Second), time.Millisecond)
+	newLog := &bytes.Buffer{}
+	wal = NewWAL(restarted, newLog, handlers)
+	if count, err := wal.Replay(bytes.NewReader(log.Bytes())); count != 2 || err != nil {
+		t.Fatalf("Expected 2 events replayed, but got %v, %v", count, err)
+	}
+	restarted.AdvanceBy(0, 0)
+	if len(invoked) != 1 || invoked[0] != "third" || restarted.Length() != 1 {
+		t.Errorf("Expected the overdue third event invoked, but got %v", invoked)
+	}
+	// IDs carry on from the old log
+	if id, _ := wal.ScheduleEventIn(time.Millisecond, "send", nil); id != 5 {
+		t.Errorf("Expected ID 5, but got %v", id)
+	}
+	// the new log stands alone
+	again := NewWAL(NewTimerWheel(start.Add(time.Second), time.Millisecond), &bytes.Buffer{}, handlers)
+	if count, err := again.Replay(bytes.NewReader(newLog.Bytes())); count != 2 || err != nil {
+		t.Errorf("Expected the fourth and fifth events replayed, but got %v, %v", count, err)
+	}
+
+	// a name without a handler schedules nothing
+	empty := NewTimerWheel(start, time.Millisecond)
+	if _, err := NewWAL(empty, &bytes.Buffer{}, Handlers{}).Replay(strings.NewReader(log.String())); err != UnknownHandler || !empty.IsEmpty() {
+		t.Errorf("Expected UnknownHandler scheduling nothing, but got %v", err)
+	}
+}
+
+func TestWALWriteError(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := NewTimerWheel(start, time.Millisecond)
+	wal := NewWAL(tw, failingWriter{}, Handlers{"nop": func([]byte) Event { return func(*time.Time) {} }})
+	if _, err := wal.ScheduleEventIn(time.Millisecond, "nop", nil); err == nil || wal.Err() != err {
+		t.Errorf("Expected the write error, but got %v", err)
+	}
+	if !tw.IsEmpty() || wal.Length() != 0 {
+		t.Error("Expected the event not to be scheduled")
+	}
+}
@@ -0,0 +1,153 @@
+// Package wheelbench compares a TimerWheel against the standard
+// library's timers on the same workload, reporting scheduling
+// throughput, allocations and firing lateness. The wheelbench command
+// runs it from the command line.
+package wheelbench
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// A description of the work to do.
+type Workload struct {
+	// How many timers to create.
+	Timers int
+	// Each timer's delay is uniformly distributed between 0 and
+	// MaxDelay.
+	MaxDelay time.Duration
+	// The bucket size of the TimerWheel.
+	BucketSize time.Duration
+	// How often the TimerWheel is advanced to the current time.
+	Tick time.Duration
+	// Seeds the random delays, so runs can be repeated.
+	Seed int64
+}
+
+// Lateness percentiles: how long after their due time timers fired.
+type Lateness struct {
+	P50, P90, P99, Max time.Duration
+}
+
+// The outcome of running a Workload against one implementation.
+type Result struct {
+	Name   string
+	Timers int
+	// How long it took to create all the timers.
+	ScheduleDuration time.Duration
+	// Heap allocations and bytes per timer whilst creating them.
+	AllocsPerTimer float64
+	BytesPerTimer  float64
+	Lateness       Lateness
+}
+
+// Timers created per second.
+func (r Result) SchedulesPerSecond() float64 {
+	return float64(r.Timers) / r.ScheduleDuration.Seconds()
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%-10s %10.0f timers/s %6.2f allocs/timer %8.1f B/timer  lateness p50 %-10v p90 %-10v p99 %-10v max %v",
+		r.Name, r.SchedulesPerSecond(), r.AllocsPerTimer, r.BytesPerTimer,
+		r.Lateness.P50, r.Lateness.P90, r.Lateness.P99, r.Lateness.Max)
+}
+
+// Runs the workload on a single TimerWheel, advanced every Tick by
+// the calling goroutine.
+func RunWheel(w Workload) Result {
+	delays := w.delays()
+	lateness := make([]time.Duration, 0, w.Timers)
+	record := func(at time.Time) gotimerwheel.Event {
+		return func(*time.Time) { lateness = append(lateness, time.Since(at)) }
+	}
+
+	before := readMemStats()
+	start := time.Now()
+	tw := gotimerwheel.NewTimerWheel(start, w.BucketSize)
+	for _, delay := range delays {
+		at := start.Add(delay)
+		tw.ScheduleEventAt(at, record(at))
+	}
+	scheduleDuration := time.Since(start)
+	after := readMemStats()
+
+	ticker := time.NewTicker(w.Tick)
+	defer ticker.Stop()
+	for !tw.IsEmpty() {
+		now := <-ticker.C
+		tw.AdvanceTo(now, 0)
+	}
+	return w.result("wheel", scheduleDuration, before, after, lateness)
+}
+
+// Runs the workload with one time.AfterFunc per timer.
+func RunStdlib(w Workload) Result {
+	delays := w.delays()
+	lateness := make([]time.Duration, w.Timers)
+	var fired int64
+	var wg sync.WaitGroup
+	wg.Add(w.Timers)
+	record := func(at time.Time) func() {
+		return func() {
+			lateness[atomic.AddInt64(&fired, 1)-1] = time.Since(at)
+			wg.Done()
+		}
+	}
+
+	before := readMemStats()
+	start := time.Now()
+	for _, delay := range delays {
+		at := start.Add(delay)
+		time.AfterFunc(time.Until(at), record(at))
+	}
+	scheduleDuration := time.Since(start)
+	after := readMemStats()
+
+	wg.Wait()
+	return w.result("stdlib", scheduleDuration, before, after, lateness)
+}
+
+func (w Workload) delays() []time.Duration {
+	rng := rand.New(rand.NewSource(w.Seed))
+	delays := make([]time.Duration, w.Timers)
+	for idx := range delays {
+		delays[idx] = time.Duration(rng.Int63n(int64(w.MaxDelay) + 1))
+	}
+	return delays
+}
+
+func (w Workload) result(name string, scheduleDuration time.Duration, before, after *runtime.MemStats, lateness []time.Duration) Result {
+	timers := float64(w.Timers)
+	return Result{
+		Name:             name,
+		Timers:           w.Timers,
+		ScheduleDuration: scheduleDuration,
+		AllocsPerTimer:   float64(after.Mallocs-before.Mallocs) / timers,
+		BytesPerTimer:    float64(after.TotalAlloc-before.TotalAlloc) / timers,
+		Lateness:         percentiles(lateness),
+	}
+}
+
+func readMemStats() *runtime.MemStats {
+	ms := new(runtime.MemStats)
+	runtime.ReadMemStats(ms)
+	return ms
+}
+
+func percentiles(lateness []time.Duration) Lateness {
+	if len(lateness) == 0 {
+		return Lateness{}
+	}
+	sort.Slice(lateness, func(a, b int) bool { return lateness[a] < lateness[b] })
+	at := func(fraction float64) time.Duration {
+		return lateness[int(fraction*float64(len(lateness)-1))]
+	}
+	return Lateness{P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: lateness[len(lateness)-1]}
+}
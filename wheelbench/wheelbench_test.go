@@ -0,0 +1,25 @@
+package wheelbench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	w := Workload{
+		Timers:     1000,
+		MaxDelay:   20 * time.Millisecond,
+		BucketSize: time.Millisecond,
+		Tick:       time.Millisecond,
+		Seed:       1,
+	}
+	for _, result := range []Result{RunWheel(w), RunStdlib(w)} {
+		t.Log(result)
+		if result.Timers != w.Timers || result.ScheduleDuration <= 0 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if result.Lateness.Max < result.Lateness.P50 {
+			t.Errorf("Percentiles out of order: %+v", result.Lateness)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package wheelmetrics exports the counters of registered Timer
+// Wheels (see gotimerwheel.Register) as expvar variables and in the
+// Prometheus text exposition format, so that timer backlogs can be
+// graphed. Everything here reads the snapshots registered Timer
+// Wheels publish, and so is safe to use from any goroutine.
+package wheelmetrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// The metrics of one registered Timer Wheel.
+type Metrics struct {
+	// Number of events currently scheduled.
+	Pending   int
+	Scheduled uint64
+	Fired     uint64
+	Expired   uint64
+	Cancelled uint64
+	Cascades  uint64
+	// Set only if the Timer Wheel has a LagObserver.
+	MeanLag time.Duration `json:",omitempty"`
+	MaxLag  time.Duration `json:",omitempty"`
+}
+
+var lagObservers = struct {
+	sync.Mutex
+	observers map[string]*LagObserver
+}{observers: make(map[string]*LagObserver)}
+
+// Records how late events fire, that is how far the Timer Wheel's
+// time is beyond an event's time when it is invoked. Install it with
+// gotimerwheel.WithObserver. It is safe for the Timer Wheel to
+// update it whilst other goroutines read it.
+type LagObserver struct {
+	count int64
+	total int64
+	max   int64
+}
+
+// Creates a LagObserver whose figures are reported alongside those of
+// the Timer Wheel registered under name.
+func NewLagObserver(name string) *LagObserver {
+	lo := new(LagObserver)
+	lagObservers.Lock()
+	lagObservers.observers[name] = lo
+	lagObservers.Unlock()
+	return lo
+}
+
+func (lo *LagObserver) OnScheduled(at time.Time)   {}
+func (lo *LagObserver) OnCascade(level int)        {}
+func (lo *LagObserver) OnDroppedPast(at time.Time) {}
+
+func (lo *LagObserver) OnFired(at time.Time, lag time.Duration) {
+	atomic.AddInt64(&lo.count, 1)
+	atomic.AddInt64(&lo.total, int64(lag))
+	for {
+		max := atomic.LoadInt64(&lo.max)
+		if int64(lag) <= max || atomic.CompareAndSwapInt64(&lo.max, max, int64(lag)) {
+			return
+		}
+	}
+}
+
+// Returns the mean and maximum lag of the events invoked so far.
+func (lo *LagObserver) Lag() (mean, max time.Duration) {
+	count := atomic.LoadInt64(&lo.count)
+	if count == 0 {
+		return 0, 0
+	}
+	return time.Duration(atomic.LoadInt64(&lo.total) / count), time.Duration(atomic.LoadInt64(&lo.max))
+}
+
+// Returns the metrics of every registered Timer Wheel, by name.
+func Snapshot() map[string]Metrics {
+	lagObservers.Lock()
+	defer lagObservers.Unlock()
+	all := gotimerwheel.RegisteredStats()
+	metrics := make(map[string]Metrics, len(all))
+	for name, stats := range all {
+		m := Metrics{
+			Pending:   stats.Overflow,
+			Scheduled: stats.Scheduled,
+			Fired:     stats.Fired,
+			Expired:   stats.Expired,
+			Cancelled: stats.Cancelled,
+			Cascades:  stats.Cascades,
+		}
+		for _, count := range stats.Levels {
+			m.Pending += count
+		}
+		if lo, found := lagObservers.observers[name]; found {
+			m.MeanLag, m.MaxLag = lo.Lag()
+		}
+		metrics[name] = m
+	}
+	return metrics
+}
+
+// Publishes Snapshot as an expvar variable with the indicated name.
+// Like expvar.Publish, this panics if the name is already in use.
+func Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} { return Snapshot() }))
+}
+
+// Writes Snapshot to w in the Prometheus text exposition format, with
+// each Timer Wheel's name as the wheel label.
+func WritePrometheus(w io.Writer) error {
+	metrics := Snapshot()
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := []struct {
+		name, kind, help string
+		value            func(m Metrics) float64
+	}{
+		{"gotimerwheel_pending", "gauge", "Number of events currently scheduled.", func(m Metrics) float64 { return float64(m.Pending) }},
+		{"gotimerwheel_scheduled_total", "counter", "Number of events scheduled.", func(m Metrics) float64 { return float64(m.Scheduled) }},
+		{"gotimerwheel_fired_total", "counter", "Number of events invoked.", func(m Metrics) float64 { return float64(m.Fired) }},
+		{"gotimerwheel_expired_total", "counter", "Number of events too late to be invoked.", func(m Metrics) float64 { return float64(m.Expired) }},
+		{"gotimerwheel_cancelled_total", "counter", "Number of events cancelled.", func(m Metrics) float64 { return float64(m.Cancelled) }},
+		{"gotimerwheel_cascades_total", "counter", "Number of buckets moved down the hierarchy.", func(m Metrics) float64 { return float64(m.Cascades) }},
+		{"gotimerwheel_lag_mean_seconds", "gauge", "Mean lag of invoked events.", func(m Metrics) float64 { return m.MeanLag.Seconds() }},
+		{"gotimerwheel_lag_max_seconds", "gauge", "Maximum lag of invoked events.", func(m Metrics) float64 { return m.MaxLag.Seconds() }},
+	}
+	for _, family := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.kind); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "%s{wheel=%q} %v\n", family.name, name, family.value(metrics[name])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns an http.Handler which serves WritePrometheus, for a
+// Prometheus server to scrape.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WritePrometheus(w)
+	})
+}
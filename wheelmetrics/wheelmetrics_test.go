@@ -0,0 +1,53 @@
+package wheelmetrics
+
+import (
+	"bytes"
+	"expvar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+func TestMetrics(t *testing.T) {
+	start := time.Unix(0, 0)
+	lo := NewLagObserver("metrics")
+	tw := gotimerwheel.NewTimerWheel(start, time.Millisecond, gotimerwheel.WithObserver(lo))
+	if err := gotimerwheel.Register("metrics", tw); err != nil {
+		t.Fatal(err)
+	}
+	defer gotimerwheel.Unregister("metrics")
+	for _, at := range []time.Duration{time.Millisecond, 3 * time.Millisecond, time.Hour} {
+		tw.ScheduleEventAt(start.Add(at), func(*time.Time) {})
+	}
+	tw.AdvanceTo(start.Add(5*time.Millisecond), 0)
+
+	m := Snapshot()["metrics"]
+	if m.Pending != 1 || m.Scheduled != 3 || m.Fired != 2 {
+		t.Errorf("Expected 1 pending, 3 scheduled and 2 fired, but got %+v", m)
+	}
+	if m.MeanLag != 3*time.Millisecond || m.MaxLag != 4*time.Millisecond {
+		t.Errorf("Expected mean lag 3ms and max lag 4ms, but got %v and %v", m.MeanLag, m.MaxLag)
+	}
+
+	Publish("gotimerwheel")
+	if v := expvar.Get("gotimerwheel").String(); !strings.Contains(v, `"Pending":1`) {
+		t.Errorf("Expected expvar to report the pending event, but got %v", v)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE gotimerwheel_fired_total counter",
+		`gotimerwheel_fired_total{wheel="metrics"} 2`,
+		`gotimerwheel_pending{wheel="metrics"} 1`,
+		`gotimerwheel_lag_max_seconds{wheel="metrics"} 0.004`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Expected %q in:\n%v", line, buf.String())
+		}
+	}
+}
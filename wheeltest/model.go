@@ -0,0 +1,240 @@
+package wheeltest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// The kinds of operation in a workload.
+type OpKind int
+
+const (
+	// Schedule an event for At.
+	Schedule OpKind = iota
+	// Cancel the Event'th event scheduled, whether or not it is still
+	// pending.
+	Cancel
+	// Advance to At, invoking at most Limit events (0 for no limit).
+	Advance
+)
+
+// One operation of a workload. See Workload.Generate and Check.
+type Op struct {
+	Kind  OpKind
+	At    time.Time
+	Event int
+	Limit int
+}
+
+// Describes a randomised workload for a Timer Wheel.
+type Workload struct {
+	// The current time of the Timer Wheel the workload is for.
+	Start time.Time
+	// The bucketSize of the Timer Wheel, so that times can be chosen
+	// on and either side of the boundaries of buckets, at every level
+	// of the hierarchy, where off-by-one mistakes live.
+	BucketSize time.Duration
+	// How far beyond the latest time advanced to events are scheduled,
+	// at most.
+	Horizon time.Duration
+	// The number of operations.
+	Ops int
+}
+
+// Returns a random sequence of operations. Events are never scheduled
+// in the past, and the target of each advance is never before that of
+// the advance before it.
+func (w Workload) Generate(rng *rand.Rand) []Op {
+	ops := make([]Op, 0, w.Ops)
+	latest, scheduled := w.Start, 0
+	for len(ops) < w.Ops {
+		switch n := rng.Intn(10); {
+		case n < 6:
+			ops = append(ops, Op{Kind: Schedule, At: w.pick(rng, latest, w.Horizon)})
+			scheduled++
+		case n < 7 && scheduled > 0:
+			ops = append(ops, Op{Kind: Cancel, Event: rng.Intn(scheduled)})
+		default:
+			op := Op{Kind: Advance, At: w.pick(rng, latest, w.Horizon/4)}
+			if rng.Intn(3) == 0 {
+				op.Limit = 1 + rng.Intn(5)
+			}
+			latest = op.At
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Picks a time from after up to within later, often on or either side
+// of a bucket boundary.
+func (w Workload) pick(rng *rand.Rand, after time.Time, within time.Duration) time.Time {
+	at := after
+	if within > 0 {
+		at = at.Add(time.Duration(rng.Int63n(int64(within))))
+	}
+	if rng.Intn(3) == 0 {
+		width := w.BucketSize
+		for level := rng.Intn(4); level > 0; level-- {
+			width *= 32
+		}
+		at = at.Add(-at.Sub(w.Start) % width).Add(time.Duration(rng.Intn(3)-1) * time.Nanosecond)
+		if at.Before(after) {
+			at = after
+		}
+	}
+	return at
+}
+
+type modelEvent struct {
+	at time.Time
+	id int
+}
+
+// A reference model of a Timer Wheel: a plain list of pending events,
+// sorted by time and then by the order in which they were scheduled.
+// It is too slow for real use, but is simple enough to be obviously
+// right. The zero Model is empty and ready to use.
+type Model struct {
+	pending []modelEvent
+	ids     int
+}
+
+// Schedules an event, returning its id: 0 for the first event
+// scheduled, 1 for the next and so on.
+func (m *Model) Schedule(at time.Time) int {
+	id := m.ids
+	m.ids++
+	idx := sort.Search(len(m.pending), func(i int) bool { return m.pending[i].at.After(at) })
+	m.pending = append(m.pending, modelEvent{})
+	copy(m.pending[idx+1:], m.pending[idx:])
+	m.pending[idx] = modelEvent{at: at, id: id}
+	return id
+}
+
+// Cancels the event with the indicated id. Returns false if it is no
+// longer pending.
+func (m *Model) Cancel(id int) bool {
+	for idx, event := range m.pending {
+		if event.id == id {
+			m.pending = append(m.pending[:idx], m.pending[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advances to now, returning the ids of the events invoked, in order.
+// As with TimerWheel.AdvanceTo, a limit greater than 0 stops the
+// advance once that many events have been invoked.
+func (m *Model) AdvanceTo(now time.Time, limit int) []int {
+	var fired []int
+	for len(m.pending) > 0 && !m.pending[0].at.After(now) && (limit <= 0 || len(fired) < limit) {
+		fired = append(fired, m.pending[0].id)
+		m.pending = m.pending[1:]
+	}
+	return fired
+}
+
+// Returns the number of pending events.
+func (m *Model) Length() int {
+	return len(m.pending)
+}
+
+// Returns the time of the earliest pending event, if there is one.
+func (m *Model) NextEventAt() (time.Time, bool) {
+	if len(m.pending) == 0 {
+		return time.Time{}, false
+	}
+	return m.pending[0].at, true
+}
+
+// Runs ops against tw and against a Model, and reports an error at
+// the first operation after which they disagree: about which events
+// are invoked and in what order, the time each is invoked with, the
+// number of events returned by AdvanceTo, the number pending, or the
+// time of the next event. Tw's CheckInvariants is also called after
+// every operation. Returns false if anything is amiss. Tw must
+// have no events scheduled. Advances which stop part way through a
+// cascade (see gotimerwheel.WithIncrementalCascade) are carried on
+// until it is done. Options which change how or when events are
+// invoked, such as WithApproximateExpiry, WithMaxLateness and
+// WithAggregation, are not modelled.
+func Check(t testing.TB, tw *gotimerwheel.TimerWheel, ops []Op) bool {
+	t.Helper()
+	model := new(Model)
+	var handles []*gotimerwheel.EventHandle
+	var fired []int
+	var invokedAt []time.Time
+	for idx, op := range ops {
+		switch op.Kind {
+		case Schedule:
+			id := model.Schedule(op.At)
+			h, err := tw.ScheduleHandleAt(op.At, func(now *time.Time) {
+				fired = append(fired, id)
+				invokedAt = append(invokedAt, *now)
+			})
+			if err != nil {
+				t.Errorf("Op %v: scheduling at %v failed: %v", idx, op.At, err)
+				return false
+			}
+			handles = append(handles, h)
+		case Cancel:
+			if expected, cancelled := model.Cancel(op.Event), handles[op.Event].Cancel(); cancelled != expected {
+				t.Errorf("Op %v: expected cancelling event %v to return %v, but got %v", idx, op.Event, expected, cancelled)
+				return false
+			}
+		case Advance:
+			expected := model.AdvanceTo(op.At, op.Limit)
+			fired, invokedAt = fired[:0], invokedAt[:0]
+			count := tw.AdvanceTo(op.At, op.Limit)
+			for tw.IsCascading() && (op.Limit <= 0 || count < op.Limit) {
+				limit := 0
+				if op.Limit > 0 {
+					limit = op.Limit - count
+				}
+				count += tw.AdvanceTo(op.At, limit)
+			}
+			if count != len(fired) || !equalIDs(fired, expected) {
+				t.Errorf("Op %v: advancing to %v (limit %v) expected to invoke %v, but invoked %v and returned %v", idx, op.At, op.Limit, expected, fired, count)
+				return false
+			}
+			for _, now := range invokedAt {
+				if !now.Equal(op.At) {
+					t.Errorf("Op %v: expected events to be invoked with %v, but got %v", idx, op.At, now)
+					return false
+				}
+			}
+		}
+		if err := tw.CheckInvariants(); err != nil {
+			t.Errorf("Op %v: %v", idx, err)
+			return false
+		}
+		if tw.Length() != model.Length() {
+			t.Errorf("Op %v: expected %v events pending, but got %v", idx, model.Length(), tw.Length())
+			return false
+		}
+		expectedAt, expectedFound := model.NextEventAt()
+		if at, found := tw.NextEventAt(); found != expectedFound || !at.Equal(expectedAt) {
+			t.Errorf("Op %v: expected the next event at %v (%v), but got %v (%v)", idx, expectedAt, expectedFound, at, found)
+			return false
+		}
+	}
+	return true
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
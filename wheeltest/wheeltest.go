@@ -0,0 +1,73 @@
+// Package wheeltest provides assertions for tests of code driven by a
+// TimerWheel: drive the wheel forwards and check that events fire in
+// the expected order at the expected times. It also provides a
+// generator of random workloads, and Check, which runs a workload
+// against a Timer Wheel and a simple reference Model to find the
+// cascade and boundary bugs that hand-written tests miss.
+package wheeltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// An event that a Timer Wheel is expected to invoke.
+type ExpectedFire struct {
+	// The time the event is expected to be scheduled for.
+	At time.Time
+	// How far either side of At the event's time may be.
+	Tolerance time.Duration
+}
+
+// Fires the next len(want) events of tw, one at a time and in order,
+// and checks that each event's time is within tolerance of the
+// corresponding expected time. Events scheduled by the fired events
+// take part. Events scheduled for the same time fire in the order
+// they were scheduled. Reports an error for every mismatch, and
+// if tw runs out of events early. Returns the times of the events
+// that fired.
+func AssertFires(t testing.TB, tw *gotimerwheel.TimerWheel, want []ExpectedFire) []time.Time {
+	t.Helper()
+	fired := make([]time.Time, 0, len(want))
+	for len(fired) < len(want) {
+		at, found := tw.NextEventAt()
+		if !found {
+			break
+		}
+		tw.AdvanceTo(at, 1)
+		fired = append(fired, at)
+	}
+	for idx, got := range fired {
+		expected := want[idx]
+		if diff := got.Sub(expected.At); diff < -expected.Tolerance || diff > expected.Tolerance {
+			t.Errorf("Event %v fired at %v, but expected %v (±%v)", idx, got, expected.At, expected.Tolerance)
+		}
+	}
+	if len(fired) < len(want) {
+		t.Errorf("Expected %v events to fire, but only %v did: %v", len(want), len(fired), fired)
+	}
+	return fired
+}
+
+// Checks that tw has no events scheduled up to and including until,
+// and advances it to until.
+func AssertNoFiresUntil(t testing.TB, tw *gotimerwheel.TimerWheel, until time.Time) {
+	t.Helper()
+	if at, found := tw.NextEventAt(); found && !at.After(until) {
+		t.Errorf("Expected no events to fire until %v, but one is scheduled at %v", until, at)
+		return
+	}
+	tw.AdvanceTo(until, 0)
+}
+
+// Returns ExpectedFires for the indicated times, all with the same
+// tolerance.
+func At(tolerance time.Duration, times ...time.Time) []ExpectedFire {
+	want := make([]ExpectedFire, len(times))
+	for idx, at := range times {
+		want[idx] = ExpectedFire{At: at, Tolerance: tolerance}
+	}
+	return want
+}
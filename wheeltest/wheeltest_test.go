@@ -0,0 +1,96 @@
+package wheeltest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/msackman/gotimerwheel"
+)
+
+// Captures failures rather than failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (rtb *recordingTB) Errorf(format string, args ...interface{}) {
+	rtb.errors = append(rtb.errors, fmt.Sprintf(format, args...))
+}
+
+func (rtb *recordingTB) Helper() {}
+
+func newWheel() *gotimerwheel.TimerWheel {
+	tw := gotimerwheel.NewTimerWheel(time.Unix(0, 0), 1)
+	// a recurring event every 10, and a one-off at 15
+	tw.ScheduleRecurringEventIn(10, func(now time.Time) (time.Time, bool) {
+		return now.Add(10), true
+	})
+	tw.ScheduleEventIn(15, func(*time.Time) {})
+	return tw
+}
+
+func TestAssertFires(t *testing.T) {
+	tw := newWheel()
+	fired := AssertFires(t, tw, At(0, time.Unix(0, 10), time.Unix(0, 15), time.Unix(0, 20)))
+	if len(fired) != 3 {
+		t.Errorf("Expected 3 fires, but got %v", fired)
+	}
+	AssertNoFiresUntil(t, tw, time.Unix(0, 29))
+	AssertFires(t, tw, []ExpectedFire{{At: time.Unix(0, 31), Tolerance: 1}})
+}
+
+func TestAssertFiresFailures(t *testing.T) {
+	rtb := &recordingTB{TB: t}
+	AssertFires(rtb, newWheel(), At(2, time.Unix(0, 10), time.Unix(0, 20)))
+	if len(rtb.errors) != 1 {
+		t.Errorf("Expected one mismatch, but got %v", rtb.errors)
+	}
+
+	rtb = &recordingTB{TB: t}
+	tw := gotimerwheel.NewTimerWheel(time.Unix(0, 0), 1)
+	tw.ScheduleEventIn(5, func(*time.Time) {})
+	AssertFires(rtb, tw, At(0, time.Unix(0, 5), time.Unix(0, 6)))
+	if len(rtb.errors) != 1 {
+		t.Errorf("Expected running out of events to be reported, but got %v", rtb.errors)
+	}
+
+	rtb = &recordingTB{TB: t}
+	AssertNoFiresUntil(rtb, newWheel(), time.Unix(0, 10))
+	if len(rtb.errors) != 1 {
+		t.Errorf("Expected a fire at 10 to be reported, but got %v", rtb.errors)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	start := time.Unix(0, 0)
+	variants := map[string][]gotimerwheel.Option{
+		"default":     nil,
+		"slices":      {gotimerwheel.WithSliceBuckets()},
+		"overflow":    {gotimerwheel.WithOverflowHeap(2)},
+		"incremental": {gotimerwheel.WithIncrementalCascade(3)},
+		"arena":       {gotimerwheel.WithArena(64)},
+	}
+	for name, options := range variants {
+		for seed := int64(0); seed < 20; seed++ {
+			w := Workload{Start: start, BucketSize: time.Millisecond, Horizon: time.Minute, Ops: 500}
+			tw := gotimerwheel.NewTimerWheel(start, time.Millisecond, options...)
+			if !Check(t, tw, w.Generate(rand.New(rand.NewSource(seed)))) {
+				t.Fatalf("%v: failed with seed %v", name, seed)
+			}
+		}
+	}
+}
+
+func TestCheckFindsDisagreement(t *testing.T) {
+	start := time.Unix(0, 0)
+	tw := gotimerwheel.NewTimerWheel(start, time.Millisecond)
+	// scheduled behind Check's back
+	tw.ScheduleEventAt(start.Add(time.Second), func(*time.Time) {})
+	rtb := &recordingTB{TB: t}
+	ops := []Op{{Kind: Schedule, At: start.Add(time.Millisecond)}}
+	if Check(rtb, tw, ops) || len(rtb.errors) != 1 {
+		t.Errorf("Expected Check to report the extra event, but got %v", rtb.errors)
+	}
+}